@@ -8,7 +8,9 @@ import (
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/uuid"
 
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
@@ -25,6 +27,9 @@ type CloudEventAgentClient[T ResourceObject] struct {
 	lister           Lister[T]
 	codecs           map[types.CloudEventsDataType]Codec[T]
 	statusHashGetter StatusHashGetter[T]
+	handlerRunner    *handlerRunner[T]
+	resyncGate       *resyncGate
+	knownSources     *knownSources
 	agentID          string
 	clusterName      string
 }
@@ -46,27 +51,47 @@ func NewCloudEventAgentClient[T ResourceObject](
 	baseClient := &baseClient{
 		clientID:               agentOptions.AgentID,
 		cloudEventsOptions:     agentOptions.CloudEventsOptions,
-		cloudEventsRateLimiter: NewRateLimiter(agentOptions.EventRateLimit),
-		reconnectedChan:        make(chan struct{}),
+		cloudEventsRateLimiter: newEventRateLimiter(agentOptions.EventRateLimit),
+		reconnectedChan:        make(chan struct{}, 1),
+		outbox:                 newOutbox(agentOptions.AgentID, agentOptions.Outbox),
+		deadLetterSink:         agentOptions.DeadLetterSink,
+		dispatcher:             newDispatcher(agentOptions.Dispatch),
+		dedupCache:             newDedupCache(agentOptions.AgentID, agentOptions.Deduplication),
+		resyncChunker:          newResyncChunker(agentOptions.ResyncChunk),
+		encoder:                newEventEncoder(agentOptions.Encoding, extensionPeer(types.ExtensionOriginalSource), sourcePeer),
+		versions:               newVersionNegotiator(agentOptions.Conversion, codecDataTypes(codecs...), extensionPeer(types.ExtensionOriginalSource), sourcePeer),
+		signer:                 agentOptions.Signer,
+		verifier:               agentOptions.Verifier,
+		encryptor:              agentOptions.Encryptor,
+		decryptor:              agentOptions.Decryptor,
+		recipient:              extensionPeer(types.ExtensionOriginalSource),
+		tracer:                 newTracer(agentOptions.TracerProvider),
+		connectionState:        newConnectionStateTracker(agentOptions.AgentID),
+		reconnectBackoff:       newReconnectBackoff(agentOptions.Reconnect),
 	}
 
 	if err := baseClient.connect(ctx); err != nil {
 		return nil, err
 	}
 
-	evtCodes := make(map[types.CloudEventsDataType]Codec[T])
-	for _, codec := range codecs {
-		evtCodes[codec.EventDataType()] = codec
-	}
-
-	return &CloudEventAgentClient[T]{
+	client := &CloudEventAgentClient[T]{
 		baseClient:       baseClient,
 		lister:           lister,
-		codecs:           evtCodes,
+		codecs:           newCodecs(codecs...),
 		statusHashGetter: statusHashGetter,
+		handlerRunner:    newHandlerRunner[T](baseClient, agentOptions.HandlerRetry),
+		resyncGate:       newResyncGate[T](ctx, lister),
+		knownSources:     newKnownSources[T](agentOptions.ClusterName, lister),
 		agentID:          agentOptions.AgentID,
 		clusterName:      agentOptions.ClusterName,
-	}, nil
+	}
+
+	if agentOptions.Heartbeat != nil {
+		// start a go routine to publish the heartbeats of this agent
+		go client.runHeartbeat(ctx, agentOptions.Heartbeat)
+	}
+
+	return client, nil
 }
 
 // ReconnectedChan returns a chan which indicates the source/agent client is reconnected.
@@ -75,10 +100,52 @@ func (c *CloudEventAgentClient[T]) ReconnectedChan() <-chan struct{} {
 	return c.reconnectedChan
 }
 
+// Status returns the status of the agent client, it's used to check the health of the client.
+func (c *CloudEventAgentClient[T]) Status() ClientStatus {
+	status := c.status()
+	status.PendingResyncRequests, status.PendingResyncSince = c.resyncGate.pendingRequests()
+	return status
+}
+
+// KnownSources returns the sources that the agent has received resources or resync requests from, and the sources
+// of the resources that are listed by the lister if the lister is a SourcesLister.
+func (c *CloudEventAgentClient[T]) KnownSources() []string {
+	return c.knownSources.list()
+}
+
 // Resync the resources spec by sending a spec resync request from the current to the given source.
+//
+// If the given source is `types.SourceAll`, the agent sends a resync request to each of its known sources, the
+// request is broadcast to all sources only when the agent does not know any source yet.
 func (c *CloudEventAgentClient[T]) Resync(ctx context.Context, source string) error {
+	return withSpan(ctx, c.tracer, resyncSpanName, func(ctx context.Context) error {
+		if source != types.SourceAll {
+			return c.resync(ctx, source)
+		}
+
+		sources := c.KnownSources()
+		if len(sources) == 0 {
+			return c.resync(ctx, types.SourceAll)
+		}
+
+		errs := []error{}
+		for _, knownSource := range sources {
+			if err := c.resync(ctx, knownSource); err != nil {
+				errs = append(errs, fmt.Errorf("failed to resync the source %s, %v", knownSource, err))
+			}
+		}
+		return utilerrors.NewAggregate(errs)
+	}, eventSourceAttribute.String(c.agentID), clusterNameAttribute.String(c.clusterName))
+}
+
+func (c *CloudEventAgentClient[T]) resync(ctx context.Context, source string) error {
 	// only resync the resources whose event data type is registered
-	for eventDataType := range c.codecs {
+	for eventDataType, codec := range c.codecs {
+		if codec.EventDataType() != eventDataType {
+			// the other supported versions of a versioned codec
+			continue
+		}
+
 		// list the resource objects that are maintained by the current agent with the given source
 		options := types.ListOptions{Source: source, ClusterName: c.clusterName, CloudEventsDataType: eventDataType}
 		objs, err := c.lister.List(options)
@@ -105,19 +172,28 @@ func (c *CloudEventAgentClient[T]) Resync(ctx context.Context, source string) er
 			Action:              types.ResyncRequestAction,
 		}
 
-		evt := types.NewEventBuilder(c.agentID, eventType).
-			WithOriginalSource(source).
-			WithClusterName(c.clusterName).
-			NewEvent()
-		if err := evt.SetData(cloudevents.ApplicationJSON, resources); err != nil {
-			return fmt.Errorf("failed to set data to cloud event: %v", err)
-		}
+		// split the large resync request into chunks
+		chunks := payload.SplitResourceVersionList(resources, c.resyncChunker.size)
+		chunkID := uuid.New().String()
+		for i, chunk := range chunks {
+			builder := types.NewEventBuilder(c.agentID, eventType).
+				WithOriginalSource(source).
+				WithClusterName(c.clusterName)
+			if len(chunks) > 1 {
+				builder.WithChunk(chunkID, i, len(chunks))
+			}
 
-		if err := c.publish(ctx, evt); err != nil {
-			return err
-		}
+			evt := builder.NewEvent()
+			if err := evt.SetData(cloudevents.ApplicationJSON, chunk); err != nil {
+				return fmt.Errorf("failed to set data to cloud event: %v", err)
+			}
+
+			if err := c.publish(ctx, evt); err != nil {
+				return err
+			}
 
-		increaseCloudEventsSentCounter(evt.Source(), c.clusterName, eventDataType.String())
+			increaseCloudEventsSentCounter(evt.Source(), c.clusterName, eventDataType.String())
+		}
 	}
 
 	return nil
@@ -148,10 +224,54 @@ func (c *CloudEventAgentClient[T]) Publish(ctx context.Context, eventType types.
 	return nil
 }
 
+// PublishBatch publishes the resources status from an agent to the sources. The resources are grouped by their
+// sources, and the resources of a source are sent in a pipeline if the transport supports it, so the resources should
+// be different from each other. It returns the errors of the resources in the order of the given resources, the error
+// of a resource is nil if the resource is published.
+func (c *CloudEventAgentClient[T]) PublishBatch(ctx context.Context, eventType types.CloudEventsType, objs []T) []error {
+	errs := make([]error, len(objs))
+
+	codec, ok := c.codecs[eventType.CloudEventsDataType]
+	if !ok {
+		return fillErrors(errs, fmt.Errorf("failed to find a codec for event %s", eventType.CloudEventsDataType))
+	}
+
+	if eventType.SubResource != types.SubResourceStatus {
+		return fillErrors(errs, fmt.Errorf("unsupported event eventType %s", eventType))
+	}
+
+	evts := []cloudevents.Event{}
+	indexes := []int{}
+	for i, obj := range objs {
+		evt, err := codec.Encode(c.agentID, eventType, obj)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		evts = append(evts, *evt)
+		indexes = append(indexes, i)
+	}
+
+	for j, err := range c.publishBatch(ctx, evts) {
+		if err != nil {
+			errs[indexes[j]] = err
+			continue
+		}
+
+		increaseCloudEventsSentCounter(evts[j].Source(), c.clusterName, eventType.CloudEventsDataType.String())
+	}
+
+	return errs
+}
+
 // Subscribe the events that are from the source status resync request or source resource spec request.
 // For status resync request, agent publish the current resources status back as response.
 // For resource spec request, agent receives resource spec and handles the spec with resource handlers.
 func (c *CloudEventAgentClient[T]) Subscribe(ctx context.Context, handlers ...ResourceHandler[T]) {
+	// start a go routine to retry the failed resources
+	go c.handlerRunner.run(ctx)
+
 	c.subscribe(ctx, func(ctx context.Context, evt cloudevents.Event) {
 		c.receive(ctx, evt, handlers...)
 	})
@@ -161,22 +281,61 @@ func (c *CloudEventAgentClient[T]) receive(ctx context.Context, evt cloudevents.
 	eventType, err := types.ParseCloudEventsType(evt.Type())
 	if err != nil {
 		klog.Errorf("failed to parse cloud event type %s, %v", evt.Type(), err)
+		c.deadLetter(ctx, evt, options.DeadLetterStageParse, err)
 		return
 	}
 
 	increaseCloudEventsReceivedCounter(evt.Source(), c.clusterName, eventType.CloudEventsDataType.String())
 
 	if eventType.Action == types.ResyncRequestAction {
+		c.knownSources.add(evt.Source())
 		if eventType.SubResource != types.SubResourceStatus {
 			klog.Warningf("unsupported resync event type %s, ignore", eventType)
 			return
 		}
 
-		startTime := time.Now()
-		if err := c.respondResyncStatusRequest(ctx, eventType.CloudEventsDataType, evt); err != nil {
-			klog.Errorf("failed to resync manifestsstatus, %v", err)
+		// the request may be split into chunks, wait for all of the chunks
+		request, err := c.resyncChunker.assemble(*eventType, evt)
+		if err != nil {
+			klog.Errorf("failed to assemble the resync request %s, %v", evt.ID(), err)
+			c.deadLetter(ctx, evt, options.DeadLetterStageDecode, err)
+			return
+		}
+		if request == nil {
+			return
+		}
+
+		// the request is answered after the lister is synced
+		c.resyncGate.respond(ctx, *request, func(ctx context.Context, evt cloudevents.Event) {
+			startTime := time.Now()
+			if err := withSpan(ctx, c.tracer, respondResyncSpanName, func(ctx context.Context) error {
+				return c.respondResyncStatusRequest(ctx, eventType.CloudEventsDataType, evt)
+			}); err != nil {
+				klog.Errorf("failed to resync manifestsstatus, %v", err)
+			}
+			updateResourceStatusResyncDurationMetric(evt.Source(), c.clusterName, eventType.CloudEventsDataType.String(), startTime)
+		})
+
+		return
+	}
+
+	if eventType.Action == types.MerkleResyncRequestAction {
+		c.knownSources.add(evt.Source())
+		if eventType.SubResource != types.SubResourceStatus {
+			klog.Warningf("unsupported resync event type %s, ignore", eventType)
+			return
 		}
-		updateResourceStatusResyncDurationMetric(evt.Source(), c.clusterName, eventType.CloudEventsDataType.String(), startTime)
+
+		// the request is answered after the lister is synced
+		c.resyncGate.respond(ctx, evt, func(ctx context.Context, evt cloudevents.Event) {
+			startTime := time.Now()
+			if err := withSpan(ctx, c.tracer, respondResyncSpanName, func(ctx context.Context) error {
+				return c.respondMerkleResyncRequest(ctx, eventType.CloudEventsDataType, evt)
+			}); err != nil {
+				klog.Errorf("failed to resync resources status with merkle tree, %v", err)
+			}
+			updateResourceStatusResyncDurationMetric(evt.Source(), c.clusterName, eventType.CloudEventsDataType.String(), startTime)
+		})
 
 		return
 	}
@@ -200,15 +359,21 @@ func (c *CloudEventAgentClient[T]) receive(ctx context.Context, evt cloudevents.
 	codec, ok := c.codecs[eventType.CloudEventsDataType]
 	if !ok {
 		klog.Warningf("failed to find the codec for event %s, ignore", eventType.CloudEventsDataType)
+		c.deadLetter(ctx, evt, options.DeadLetterStageCodec,
+			fmt.Errorf("failed to find the codec for event %s", eventType.CloudEventsDataType))
 		return
 	}
 
 	obj, err := codec.Decode(&evt)
 	if err != nil {
 		klog.Errorf("failed to decode spec, %v", err)
+		c.deadLetter(ctx, evt, options.DeadLetterStageDecode, err)
 		return
 	}
 
+	// the source of the resource is persisted with the resource by the codec, e.g. the originalsource label
+	c.knownSources.add(evt.Source())
+
 	action, err := c.specAction(evt.Source(), eventType.CloudEventsDataType, obj)
 	if err != nil {
 		klog.Errorf("failed to generate spec action %s, %v", evt, err)
@@ -220,11 +385,7 @@ func (c *CloudEventAgentClient[T]) receive(ctx context.Context, evt cloudevents.
 		return
 	}
 
-	for _, handler := range handlers {
-		if err := handler(action, obj); err != nil {
-			klog.Errorf("failed to handle spec event %s, %v", evt, err)
-		}
-	}
+	c.handlerRunner.handle(ctx, evt, action, obj, handlers...)
 }
 
 // Upon receiving the status resync event, the agent responds by sending resource status events to the broker as
@@ -290,6 +451,117 @@ func (c *CloudEventAgentClient[T]) respondResyncStatusRequest(
 	return nil
 }
 
+// Upon receiving the merkle resync request, the agent builds a merkle tree over the status of the resources it
+// maintains and compares the nodes of the request with its own nodes:
+//   - If a node is matched, the status of the resources under the node is not changed, do nothing.
+//   - If a mismatched node is a leaf node, the agent compares the status hashes of the resources under the node, and
+//     sends the changed resources status.
+//   - If a mismatched node is not a leaf node, the agent responds with its child nodes of the mismatched node.
+//
+// The agent always responds the request of the root node, so that the source knows the agent supports the merkle
+// resync.
+func (c *CloudEventAgentClient[T]) respondMerkleResyncRequest(
+	ctx context.Context, eventDataType types.CloudEventsDataType, evt cloudevents.Event,
+) error {
+	nodes, err := payload.DecodeMerkleResyncRequest(evt)
+	if err != nil {
+		return err
+	}
+
+	options := types.ListOptions{ClusterName: c.clusterName, Source: evt.Source(), CloudEventsDataType: eventDataType}
+	objs, err := c.lister.List(options)
+	if err != nil {
+		return err
+	}
+
+	currentHashes := map[string]string{}
+	statusHashes := []payload.ResourceStatusHash{}
+	for _, obj := range objs {
+		statusHash, err := c.statusHashGetter(obj)
+		if err != nil {
+			continue
+		}
+
+		currentHashes[string(obj.GetUID())] = statusHash
+		statusHashes = append(statusHashes, payload.ResourceStatusHash{
+			ResourceID: string(obj.GetUID()),
+			StatusHash: statusHash,
+		})
+	}
+
+	statusEventType := types.CloudEventsType{
+		CloudEventsDataType: eventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              types.ResyncResponseAction,
+	}
+
+	tree := payload.NewMerkleTree(nodes.Depth, statusHashes)
+	children := []payload.MerkleNode{}
+	isRootRequest := false
+	for _, node := range nodes.Nodes {
+		if len(node.Path) == 0 {
+			isRootRequest = true
+		}
+
+		if tree.Hash(node.Path) == node.Hash {
+			continue
+		}
+
+		if !tree.IsLeaf(node.Path) {
+			children = append(children, tree.Children(node.Path)...)
+			continue
+		}
+
+		for _, obj := range objs {
+			resourceID := string(obj.GetUID())
+			if !tree.Contains(node.Path, resourceID) {
+				continue
+			}
+
+			lastHash, ok := findStatusHash(resourceID, node.StatusHashes)
+			if !ok {
+				// ignore the resource that is not on the source, but exists on the agent, wait for the source deleting it
+				klog.Infof("The resource %s is not found from the source, ignore", resourceID)
+				continue
+			}
+
+			currentHash, ok := currentHashes[resourceID]
+			if !ok || currentHash == lastHash {
+				continue
+			}
+
+			if err := c.Publish(ctx, statusEventType, obj); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(children) == 0 && !isRootRequest {
+		return nil
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: eventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              types.MerkleResyncResponseAction,
+	}
+
+	response := types.NewEventBuilder(c.agentID, eventType).
+		WithOriginalSource(evt.Source()).
+		WithClusterName(c.clusterName).
+		NewEvent()
+	if err := response.SetData(cloudevents.ApplicationJSON, &payload.MerkleNodeList{Depth: tree.Depth(), Nodes: children}); err != nil {
+		return fmt.Errorf("failed to set data to cloud event: %v", err)
+	}
+
+	if err := c.publish(ctx, response); err != nil {
+		return err
+	}
+
+	increaseCloudEventsSentCounter(response.Source(), c.clusterName, eventDataType.String())
+	return nil
+}
+
 func (c *CloudEventAgentClient[T]) specAction(
 	source string, eventDataType types.CloudEventsDataType, obj T) (evt types.ResourceAction, err error) {
 	options := types.ListOptions{ClusterName: c.clusterName, Source: source, CloudEventsDataType: eventDataType}
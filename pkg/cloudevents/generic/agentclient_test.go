@@ -2,6 +2,9 @@ package generic
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"fmt"
 	"sync"
 	"testing"
@@ -15,8 +18,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubetypes "k8s.io/apimachinery/pkg/types"
 
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/deadletter"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/signing"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
@@ -581,3 +587,144 @@ func (c *mockResourceCodec) Decode(evt *cloudevents.Event) (*mockResource, error
 
 	return res, nil
 }
+
+func TestReceiveResourceSpecDeadLetter(t *testing.T) {
+	cases := []struct {
+		name          string
+		requestEvent  cloudevents.Event
+		handlerErr    error
+		expectedStage options.DeadLetterStage
+	}{
+		{
+			name: "unparsable event type",
+			requestEvent: func() cloudevents.Event {
+				evt := cloudevents.NewEvent()
+				evt.SetType("unsupported")
+				return evt
+			}(),
+			expectedStage: options.DeadLetterStageParse,
+		},
+		{
+			name: "no registered codec for the resource",
+			requestEvent: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					SubResource: types.SubResourceSpec,
+					Action:      "test_create_request",
+				}
+
+				evt := cloudevents.NewEvent()
+				evt.SetType(eventType.String())
+				evt.SetExtension(types.ExtensionClusterName, "cluster1")
+				return evt
+			}(),
+			expectedStage: options.DeadLetterStageCodec,
+		},
+		{
+			name: "failed to decode the resource",
+			requestEvent: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: mockEventDataType,
+					SubResource:         types.SubResourceSpec,
+					Action:              "test_create_request",
+				}
+
+				evt := cloudevents.NewEvent()
+				evt.SetType(eventType.String())
+				evt.SetExtension(types.ExtensionClusterName, "cluster1")
+				return evt
+			}(),
+			expectedStage: options.DeadLetterStageDecode,
+		},
+		{
+			name: "failed to handle the resource",
+			requestEvent: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: mockEventDataType,
+					SubResource:         types.SubResourceSpec,
+					Action:              "test_create_request",
+				}
+
+				evt, _ := newMockResourceCodec().Encode(testAgentName, eventType, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"})
+				return *evt
+			}(),
+			handlerErr:    fmt.Errorf("failed to handle"),
+			expectedStage: options.DeadLetterStageHandle,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sink := deadletter.NewRingBufferSink(10)
+			agentOptions := fake.NewAgentOptions(gochan.New(), nil, "cluster1", testAgentName)
+			agentOptions.DeadLetterSink = sink
+			agent, err := NewCloudEventAgentClient[*mockResource](context.TODO(), agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+			require.NoError(t, err)
+
+			agent.receive(context.TODO(), c.requestEvent, func(event types.ResourceAction, resource *mockResource) error {
+				return c.handlerErr
+			})
+
+			letters := sink.List()
+			require.Len(t, letters, 1)
+			require.Equal(t, c.expectedStage, letters[0].Stage)
+			require.Equal(t, testAgentName, letters[0].ClientID)
+			require.Equal(t, c.requestEvent.ID(), letters[0].Event.ID())
+		})
+	}
+}
+
+func TestReceiveSignedResourceSpec(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer, err := signing.NewJWSSigner(testSourceName, key)
+	require.NoError(t, err)
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	newEvent := func() cloudevents.Event {
+		evt, err := newMockResourceCodec().Encode(testSourceName, eventType,
+			&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"})
+		require.NoError(t, err)
+		return *evt
+	}
+
+	sink := deadletter.NewRingBufferSink(10)
+	agentOptions := fake.NewAgentOptions(gochan.New(), nil, "cluster1", testAgentName)
+	agentOptions.DeadLetterSink = sink
+	agentOptions.Verifier = signing.NewJWSVerifier(signing.StaticKeyStore{testSourceName: key.Public()})
+	agent, err := NewCloudEventAgentClient[*mockResource](context.TODO(), agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	received := 0
+	receive := agent.verifyReceived(func(ctx context.Context, evt cloudevents.Event) {
+		received++
+	})
+
+	signed := newEvent()
+	require.NoError(t, signer.Sign(&signed))
+	receive(context.TODO(), signed)
+	require.Equal(t, 1, received)
+	require.Empty(t, sink.List())
+
+	// the unsigned event is rejected
+	unsigned := newEvent()
+	receive(context.TODO(), unsigned)
+	require.Equal(t, 1, received)
+
+	// the tampered event is rejected
+	tampered := newEvent()
+	require.NoError(t, signer.Sign(&tampered))
+	tampered.SetExtension(types.ExtensionClusterName, "cluster2")
+	receive(context.TODO(), tampered)
+	require.Equal(t, 1, received)
+
+	letters := sink.List()
+	require.Len(t, letters, 2)
+	for i, evt := range []cloudevents.Event{unsigned, tampered} {
+		require.Equal(t, options.DeadLetterStageVerify, letters[i].Stage)
+		require.Equal(t, evt.ID(), letters[i].Event.ID())
+	}
+}
@@ -1,19 +1,22 @@
 package generic
 
 import (
+	"container/list"
 	"context"
 	"fmt"
 	"sync"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/client"
+	"go.opentelemetry.io/otel/trace"
 
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
 
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/encoding"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
 )
 
@@ -23,6 +26,7 @@ const (
 )
 
 // the reconnect backoff will stop at [1,5) min interval. If we don't backoff for 10min, we reset the backoff.
+// It's used by the clients that are not configured with a ReconnectPolicy.
 var DelayFn = wait.Backoff{
 	Duration: 5 * time.Second,
 	Cap:      1 * time.Minute,
@@ -39,10 +43,29 @@ type baseClient struct {
 	cloudEventsOptions     options.CloudEventsOptions
 	cloudEventsProtocol    options.CloudEventsProtocol
 	cloudEventsClient      cloudevents.Client
-	cloudEventsRateLimiter flowcontrol.RateLimiter
+	cloudEventsRateLimiter eventRateLimiter
 	receiverChan           chan int
 	reconnectedChan        chan struct{}
 	clientReady            bool
+	outbox                 *outbox
+	deadLetterSink         options.DeadLetterSink
+	dispatcher             *dispatcher
+	dedupCache             *dedupCache
+	resyncChunker          *resyncChunker
+	encoder                *eventEncoder
+	versions               *versionNegotiator
+	signer                 options.EventSigner
+	verifier               options.EventVerifier
+	encryptor              options.EventEncryptor
+	decryptor              options.EventDecryptor
+	// recipient returns the recipient of a sent event, it is a cluster for a source and a source for an agent
+	recipient peerFunc
+	tracer    trace.Tracer
+
+	connectionState  *connectionStateTracker
+	reconnectBackoff *reconnectBackoff
+	subscribedTime   time.Time
+	lastReceivedTime time.Time
 }
 
 func (c *baseClient) connect(ctx context.Context) error {
@@ -51,12 +74,14 @@ func (c *baseClient) connect(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	c.connectionState.set(ConnectionStateReady, nil)
 
 	// start a go routine to handle cloudevents client connection errors
 	go func() {
 		for {
 			if !c.isClientReady() {
 				klog.V(4).Infof("reconnecting the cloudevents client")
+				c.connectionState.set(ConnectionStateConnecting, nil)
 
 				c.cloudEventsClient, err = c.newCloudEventsClient(ctx)
 				// TODO enhance the cloudevents SKD to avoid wrapping the error type to distinguish the net connection
@@ -64,22 +89,34 @@ func (c *baseClient) connect(ctx context.Context) error {
 				if err != nil {
 					// failed to reconnect, try agin
 					runtime.HandleError(fmt.Errorf("the cloudevents client reconnect failed, %v", err))
-					<-wait.RealTimer(DelayFn()).C()
+					c.connectionState.set(ConnectionStateDisconnected, err)
+
+					delay, retry := c.reconnectBackoff.failed()
+					if !retry {
+						runtime.HandleError(fmt.Errorf("the cloudevents client gives up reconnecting after %d attempts",
+							c.reconnectBackoff.failures))
+						c.close(err)
+						return
+					}
+
+					<-wait.RealTimer(delay).C()
 					continue
 				}
 				// the cloudevents network connection is back, mark the client ready and send the receiver restart signal
 				klog.V(4).Infof("the cloudevents client is reconnected")
 				increaseClientReconnectedCounter(c.clientID)
+				c.reconnectBackoff.succeeded()
 				c.setClientReady(true)
+				c.connectionState.set(ConnectionStateReady, nil)
+				// send the events that are queued during the disconnection
+				go c.drainOutbox(ctx)
 				c.sendReceiverSignal(restartReceiverSignal)
 				c.sendReconnectedSignal()
 			}
 
 			select {
 			case <-ctx.Done():
-				if c.receiverChan != nil {
-					close(c.receiverChan)
-				}
+				c.close(ctx.Err())
 				return
 			case err, ok := <-c.cloudEventsOptions.ErrorChan():
 				if !ok {
@@ -93,11 +130,12 @@ func (c *baseClient) connect(ctx context.Context) error {
 				// and close the current client
 				c.sendReceiverSignal(stopReceiverSignal)
 				c.setClientReady(false)
+				c.connectionState.set(ConnectionStateDisconnected, err)
 				if err := c.cloudEventsProtocol.Close(ctx); err != nil {
 					runtime.HandleError(fmt.Errorf("failed to close the cloudevents protocol, %v", err))
 				}
 
-				<-wait.RealTimer(DelayFn()).C()
+				<-wait.RealTimer(c.reconnectBackoff.delay()).C()
 			}
 		}
 	}()
@@ -105,36 +143,220 @@ func (c *baseClient) connect(ctx context.Context) error {
 	return nil
 }
 
+// close stops the receiver and marks the client closed, the client will not reconnect anymore.
+func (c *baseClient) close(reason error) {
+	c.RLock()
+	if c.receiverChan != nil {
+		close(c.receiverChan)
+	}
+	c.RUnlock()
+
+	c.connectionState.set(ConnectionStateClosed, reason)
+}
+
+// ConnectionState returns the current state of the client connection.
+func (c *baseClient) ConnectionState() ConnectionState {
+	return c.connectionState.get()
+}
+
+// OnConnectionStateChange subscribes the changes of the client connection state, the handler is called in order
+// with the changes until the context is done.
+func (c *baseClient) OnConnectionStateChange(ctx context.Context, handler ConnectionStateHandler) {
+	c.connectionState.subscribe(ctx, handler)
+}
+
 func (c *baseClient) publish(ctx context.Context, evt cloudevents.Event) error {
-	now := time.Now()
+	ctx, span := startEventSpan(ctx, c.tracer, publishSpanName, trace.SpanKindProducer, evt)
+	defer span.End()
 
-	if err := c.cloudEventsRateLimiter.Wait(ctx); err != nil {
-		return fmt.Errorf("client rate limiter Wait returned an error: %w", err)
+	// propagate the trace context to the receiver of the event
+	evt = evt.Clone()
+	injectTraceContext(ctx, &evt)
+
+	err := c.publishEvent(ctx, evt)
+	recordSpanError(span, err)
+	return err
+}
+
+func (c *baseClient) publishEvent(ctx context.Context, evt cloudevents.Event) error {
+	evt, queued, err := c.prepareEvent(ctx, evt)
+	if err != nil {
+		increasePublishFailuresCounter(c.clientID, evt, publishFailureReasonPrepare)
+		return err
 	}
 
-	latency := time.Since(now)
-	if latency > longThrottleLatency {
-		klog.Warningf(fmt.Sprintf("Waited for %v due to client-side throttling, not priority and fairness, request: %s",
-			latency, evt))
+	if queued {
+		return nil
+	}
+
+	return c.send(ctx, evt)
+}
+
+// prepareEvent converts, encodes, encrypts and signs the event before it's sent. If the client is not ready or has pending
+// events, the event is queued to the outbox and true is returned.
+func (c *baseClient) prepareEvent(ctx context.Context, evt cloudevents.Event) (cloudevents.Event, bool, error) {
+	if c.versions != nil {
+		converted, err := c.versions.convertSent(evt)
+		if err != nil {
+			return evt, false, err
+		}
+		evt = converted
+	}
+
+	if c.encoder != nil {
+		encoded, err := c.encoder.encode(evt)
+		if err != nil {
+			return evt, false, err
+		}
+		evt = encoded
+	}
+
+	if c.encryptor != nil {
+		if err := c.encryptor.Encrypt(c.recipient(evt), &evt); err != nil {
+			return evt, false, err
+		}
+	}
+
+	if c.signer != nil {
+		if err := c.signer.Sign(&evt); err != nil {
+			return evt, false, err
+		}
+	}
+
+	if c.outbox != nil {
+		ready := c.isClientReady()
+		queued, err := c.outbox.addIfPending(evt, ready)
+		if err != nil {
+			return evt, false, err
+		}
+
+		if queued {
+			klog.V(4).Infof("the cloudevents client is not ready or has pending events, queue the event %s", evt.ID())
+			if ready {
+				// the previous draining may be interrupted, restart it and avoid the draining to be canceled with
+				// the current publish context
+				go c.drainOutbox(context.WithoutCancel(ctx))
+			}
+			return evt, true, nil
+		}
+	}
+
+	return evt, false, nil
+}
+
+func (c *baseClient) send(ctx context.Context, evt cloudevents.Event) error {
+	defer observePublishDuration(c.clientID, evt, time.Now())
+
+	if err := c.waitRateLimiter(ctx, evt); err != nil {
+		increasePublishFailuresCounter(c.clientID, evt, publishFailureReasonRateLimit)
+		return err
 	}
 
 	sendingCtx, err := c.cloudEventsOptions.WithContext(ctx, evt.Context)
 	if err != nil {
+		increasePublishFailuresCounter(c.clientID, evt, publishFailureReasonContext)
 		return err
 	}
 
+	return c.sendWithContext(sendingCtx, evt)
+}
+
+func (c *baseClient) waitRateLimiter(ctx context.Context, evt cloudevents.Event) error {
+	now := time.Now()
+
+	recipient := c.recipient(evt)
+	priority := eventPriorityOf(evt, recipient)
+	if err := c.cloudEventsRateLimiter.Wait(ctx, priority, recipient); err != nil {
+		return fmt.Errorf("client rate limiter Wait returned an error: %w", err)
+	}
+
+	latency := time.Since(now)
+	observeRateLimiterWaitDuration(c.clientID, priority, latency)
+	if latency > longThrottleLatency {
+		klog.Warningf("Waited for %v due to client-side throttling, priority: %s, request: %s", latency, priority, evt)
+	}
+
+	return nil
+}
+
+// sendWithContext sends the event with the sending context that is returned by the CloudEventsOptions.
+func (c *baseClient) sendWithContext(sendingCtx context.Context, evt cloudevents.Event) error {
 	if !c.isClientReady() {
+		increasePublishFailuresCounter(c.clientID, evt, publishFailureReasonNotReady)
 		return fmt.Errorf("the cloudevents client is not ready")
 	}
 
 	klog.V(4).Infof("Sending event: %v\n%s", sendingCtx, evt)
 	if result := c.cloudEventsClient.Send(sendingCtx, evt); cloudevents.IsUndelivered(result) {
+		increasePublishFailuresCounter(c.clientID, evt, publishFailureReasonSend)
 		return fmt.Errorf("failed to send event %s, %v", evt.Context, result)
 	}
 
+	observeEventSize(c.clientID, evt, eventDirectionSent)
 	return nil
 }
 
+// outboxRetryBackoff is the backoff of resending a queued event that fails to be sent.
+var outboxRetryBackoff = wait.Backoff{
+	Duration: 1 * time.Second,
+	Cap:      30 * time.Second,
+	Steps:    10,
+	Factor:   2.0,
+	Jitter:   0.1,
+}
+
+// drainOutbox sends the queued events in order until the outbox is empty. If an event fails to be sent, it's resent
+// with a backoff, and it's dropped if it still fails after the max retries, so it does not block the events behind
+// it. If the client is disconnected again, the draining is stopped and the remaining events are kept in the outbox
+// until the client is reconnected.
+func (c *baseClient) drainOutbox(ctx context.Context) {
+	if c.outbox == nil || !c.outbox.startDraining() {
+		return
+	}
+	defer c.outbox.stopDraining()
+
+	backoff := outboxRetryBackoff
+	var failedElem *list.Element
+	retries := 0
+	for c.isClientReady() {
+		elem, ok := c.outbox.front()
+		if !ok {
+			return
+		}
+
+		if elem != failedElem {
+			failedElem, retries = nil, 0
+		}
+
+		evt := elem.Value.(*outboxItem).event
+		if err := c.send(ctx, evt); err != nil {
+			if retries >= c.outbox.maxRetries {
+				runtime.HandleError(fmt.Errorf("failed to send the queued event %s after %d retries, drop it, %v",
+					evt.ID(), retries, err))
+				increaseOutboxDroppedCounter(c.clientID)
+				c.outbox.remove(elem)
+				backoff = outboxRetryBackoff
+				continue
+			}
+
+			failedElem = elem
+			retries++
+			delay := backoff.Step()
+			runtime.HandleError(fmt.Errorf("failed to send the queued event %s, retry after %v, %v", evt.ID(), delay, err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-wait.RealTimer(delay).C():
+			}
+			continue
+		}
+
+		backoff = outboxRetryBackoff
+		c.outbox.remove(elem)
+	}
+}
+
 func (c *baseClient) subscribe(ctx context.Context, receive receiveFn) {
 	c.Lock()
 	defer c.Unlock()
@@ -146,6 +368,34 @@ func (c *baseClient) subscribe(ctx context.Context, receive receiveFn) {
 	}
 
 	c.receiverChan = make(chan int)
+	c.subscribedTime = time.Now()
+
+	// decode the event data that is encoded by the peer
+	receive = c.decodeReceived(receive)
+
+	if c.decryptor != nil {
+		// decrypt the event data before decoding it
+		receive = c.decryptReceived(receive)
+	}
+
+	if c.dedupCache != nil {
+		// drop the duplicated events before decrypting and decoding them
+		receive = c.dedupReceived(receive)
+	}
+
+	if c.verifier != nil {
+		// verify the signature of the event before decrypting and decoding it
+		receive = c.verifyReceived(receive)
+	}
+
+	// start a span for the received event with the trace context of the event
+	receive = c.traceReceived(receive)
+
+	if c.dispatcher != nil {
+		// the received events are processed by the dispatcher workers
+		c.dispatcher.run(ctx, receive)
+		receive = c.dispatcher.dispatch
+	}
 
 	// start a go routine to handle cloudevents subscription
 	go func() {
@@ -157,7 +407,10 @@ func (c *baseClient) subscribe(ctx context.Context, receive receiveFn) {
 				go func() {
 					if err := c.cloudEventsClient.StartReceiver(receiverCtx, func(evt cloudevents.Event) {
 						klog.V(4).Infof("Received event: %s", evt)
-						receive(receiverCtx, evt)
+						receivedTime := time.Now()
+						c.setLastReceivedTime(receivedTime)
+						observeEventSize(c.clientID, evt, eventDirectionReceived)
+						receive(withReceivedTime(receiverCtx, receivedTime), evt)
 					}); err != nil {
 						runtime.HandleError(fmt.Errorf("failed to receive cloudevents, %v", err))
 					}
@@ -193,6 +446,107 @@ func (c *baseClient) subscribe(ctx context.Context, receive receiveFn) {
 	}()
 }
 
+// decodeReceived wraps the receive func to decode the received event data to JSON before receiving it.
+func (c *baseClient) decodeReceived(receive receiveFn) receiveFn {
+	return func(ctx context.Context, evt cloudevents.Event) {
+		decoded, err := encoding.DecodeEvent(evt)
+		if err != nil {
+			klog.Errorf("failed to decode the event %s, %v", evt.ID(), err)
+			c.deadLetter(ctx, evt, options.DeadLetterStageDecode, err)
+			return
+		}
+
+		if c.encoder != nil {
+			c.encoder.learn(decoded)
+		}
+
+		if c.versions != nil {
+			converted, err := c.versions.convertReceived(decoded)
+			if err != nil {
+				klog.Errorf("failed to convert the event %s, %v", evt.ID(), err)
+				c.deadLetter(ctx, evt, options.DeadLetterStageCodec, err)
+				return
+			}
+			decoded = converted
+		}
+
+		receive(ctx, decoded)
+	}
+}
+
+// traceReceived wraps the receive func to receive the event in a span that continues the trace of the sender.
+func (c *baseClient) traceReceived(receive receiveFn) receiveFn {
+	return func(ctx context.Context, evt cloudevents.Event) {
+		ctx, span := startEventSpan(extractTraceContext(ctx, evt), c.tracer, receiveSpanName, trace.SpanKindConsumer, evt)
+		defer span.End()
+
+		receive(ctx, evt)
+	}
+}
+
+// verifyReceived wraps the receive func to reject the received events that are not signed or have invalid signatures.
+func (c *baseClient) verifyReceived(receive receiveFn) receiveFn {
+	return func(ctx context.Context, evt cloudevents.Event) {
+		if err := c.verifier.Verify(evt); err != nil {
+			klog.Errorf("failed to verify the event %s, %v", evt.ID(), err)
+			c.deadLetter(ctx, evt, options.DeadLetterStageVerify, err)
+			return
+		}
+
+		receive(ctx, evt)
+	}
+}
+
+// decryptReceived wraps the receive func to decrypt the received event data before receiving it.
+func (c *baseClient) decryptReceived(receive receiveFn) receiveFn {
+	return func(ctx context.Context, evt cloudevents.Event) {
+		decrypted := evt.Clone()
+		if err := c.decryptor.Decrypt(&decrypted); err != nil {
+			klog.Errorf("failed to decrypt the event %s, %v", evt.ID(), err)
+			c.deadLetter(ctx, evt, options.DeadLetterStageDecrypt, err)
+			return
+		}
+
+		receive(ctx, decrypted)
+	}
+}
+
+// deadLetter sends a received event that failed to be processed to the dead letter sink.
+func (c *baseClient) deadLetter(ctx context.Context, evt cloudevents.Event, stage options.DeadLetterStage, reason error) {
+	// the handle failures are counted by the handler runner
+	if stage != options.DeadLetterStageHandle {
+		increaseDecodeFailuresCounter(c.clientID, evt, string(stage))
+	}
+
+	if c.deadLetterSink == nil {
+		return
+	}
+
+	letter := options.DeadLetter{
+		ClientID:  c.clientID,
+		Stage:     stage,
+		Reason:    reason.Error(),
+		Timestamp: time.Now(),
+		Event:     evt,
+	}
+	if err := c.deadLetterSink.Put(ctx, letter); err != nil {
+		runtime.HandleError(fmt.Errorf("failed to put the event %s to the dead letter sink, %v", evt.ID(), err))
+	}
+}
+
+type receivedTimeKey struct{}
+
+// withReceivedTime returns a copy of the context that carries the time when the event is received.
+func withReceivedTime(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, receivedTimeKey{}, t)
+}
+
+// receivedTimeFrom returns the time when the event is received from the context.
+func receivedTimeFrom(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(receivedTimeKey{}).(time.Time)
+	return t, ok
+}
+
 func (c *baseClient) sendReceiverSignal(signal int) {
 	c.RLock()
 	defer c.RUnlock()
@@ -202,10 +556,36 @@ func (c *baseClient) sendReceiverSignal(signal int) {
 	}
 }
 
+// sendReconnectedSignal notifies the client is reconnected without blocking, if a previous signal is not received
+// yet, the signals are coalesced.
 func (c *baseClient) sendReconnectedSignal() {
 	c.RLock()
 	defer c.RUnlock()
-	c.reconnectedChan <- struct{}{}
+
+	select {
+	case c.reconnectedChan <- struct{}{}:
+	default:
+		klog.V(4).Infof("the previous reconnected signal is not received yet, skip sending it")
+	}
+}
+
+func (c *baseClient) setLastReceivedTime(t time.Time) {
+	c.Lock()
+	defer c.Unlock()
+	c.lastReceivedTime = t
+}
+
+// status returns the connection and subscription status of the client.
+func (c *baseClient) status() ClientStatus {
+	c.RLock()
+	defer c.RUnlock()
+
+	return ClientStatus{
+		ConnectionState:  c.connectionState.get(),
+		Subscribed:       c.receiverChan != nil,
+		SubscribedTime:   c.subscribedTime,
+		LastReceivedTime: c.lastReceivedTime,
+	}
 }
 
 func (c *baseClient) isClientReady() bool {
@@ -227,7 +607,13 @@ func (c *baseClient) newCloudEventsClient(ctx context.Context) (cloudevents.Clie
 		return nil, err
 	}
 
-	cloudEventsClient, err := cloudevents.NewClient(c.cloudEventsProtocol)
+	clientOpts := []client.Option{}
+	if c.dispatcher != nil {
+		// receive the events one by one in order, the dispatcher processes them concurrently
+		clientOpts = append(clientOpts, client.WithPollGoroutines(1), client.WithBlockingCallback())
+	}
+
+	cloudEventsClient, err := cloudevents.NewClient(c.cloudEventsProtocol, clientOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,124 @@
+package generic
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.opentelemetry.io/otel/trace"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// publishBatch publishes the events and returns their errors in the order of the events, the error of an event is
+// nil if the event is published.
+//
+// The events are grouped by their recipients and types, the groups are published concurrently. The sending context
+// of a group is derived once from its first event, and the events of a group are sent in a pipeline if the transport
+// supports it, so the events of a group may be delivered out of order.
+func (c *baseClient) publishBatch(ctx context.Context, evts []cloudevents.Event) []error {
+	errs := make([]error, len(evts))
+
+	type groupKey struct {
+		recipient string
+		eventType string
+	}
+	keys := []groupKey{}
+	groups := map[groupKey][]int{}
+	for i, evt := range evts {
+		key := groupKey{recipient: c.recipient(evt), eventType: evt.Type()}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	maxInflight := 1
+	if sender, ok := c.cloudEventsOptions.(options.PipelinedSender); ok && sender.MaxInflightEvents() > 1 {
+		maxInflight = sender.MaxInflightEvents()
+	}
+
+	wg := sync.WaitGroup{}
+	for _, key := range keys {
+		wg.Add(1)
+		go func(indexes []int) {
+			defer wg.Done()
+			c.publishGroup(ctx, evts, indexes, maxInflight, errs)
+		}(groups[key])
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// publishGroup publishes the events of a group with the given indexes and saves their errors to the errs.
+func (c *baseClient) publishGroup(ctx context.Context, evts []cloudevents.Event, indexes []int, maxInflight int,
+	errs []error) {
+	var sendingCtx context.Context
+	inflight := make(chan struct{}, maxInflight)
+	wg := sync.WaitGroup{}
+
+	for _, i := range indexes {
+		publishCtx, span := startEventSpan(ctx, c.tracer, publishSpanName, trace.SpanKindProducer, evts[i])
+		finish := func(err error) {
+			errs[i] = err
+			recordSpanError(span, err)
+			span.End()
+		}
+
+		// propagate the trace context to the receiver of the event
+		evt := evts[i].Clone()
+		injectTraceContext(publishCtx, &evt)
+
+		evt, queued, err := c.prepareEvent(publishCtx, evt)
+		if err != nil {
+			increasePublishFailuresCounter(c.clientID, evt, publishFailureReasonPrepare)
+			finish(err)
+			continue
+		}
+
+		if queued {
+			finish(nil)
+			continue
+		}
+
+		startTime := time.Now()
+		if err := c.waitRateLimiter(ctx, evt); err != nil {
+			increasePublishFailuresCounter(c.clientID, evt, publishFailureReasonRateLimit)
+			finish(err)
+			continue
+		}
+
+		if sendingCtx == nil {
+			sendingCtx, err = c.cloudEventsOptions.WithContext(ctx, evt.Context)
+			if err != nil {
+				// try to derive the sending context with the next event
+				sendingCtx = nil
+				increasePublishFailuresCounter(c.clientID, evt, publishFailureReasonContext)
+				finish(err)
+				continue
+			}
+		}
+
+		inflight <- struct{}{}
+		wg.Add(1)
+		go func(sendingCtx context.Context) {
+			defer wg.Done()
+			defer func() { <-inflight }()
+			err := c.sendWithContext(sendingCtx, evt)
+			observePublishDuration(c.clientID, evt, startTime)
+			finish(err)
+		}(sendingCtx)
+	}
+
+	wg.Wait()
+}
+
+// fillErrors sets all errors to the given error.
+func fillErrors(errs []error, err error) []error {
+	for i := range errs {
+		errs[i] = err
+	}
+	return errs
+}
@@ -0,0 +1,146 @@
+package generic
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/stretchr/testify/require"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// pipelinedOptions is a CloudEventsOptions that supports pipelined sending and counts the derived sending contexts.
+type pipelinedOptions struct {
+	options.CloudEventsOptions
+	contexts atomic.Int32
+}
+
+func (o *pipelinedOptions) WithContext(ctx context.Context, evtCtx cloudevents.EventContext) (context.Context, error) {
+	o.contexts.Add(1)
+	return o.CloudEventsOptions.WithContext(ctx, evtCtx)
+}
+
+func (o *pipelinedOptions) MaxInflightEvents() int {
+	return 2
+}
+
+func TestSourcePublishBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceOptions := fake.NewSourceOptions(gochan.New(), testSourceName)
+	pipelined := &pipelinedOptions{CloudEventsOptions: sourceOptions.CloudEventsOptions}
+	sourceOptions.CloudEventsOptions = pipelined
+	source, err := NewCloudEventSourceClient[*mockResource](ctx, sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	eventChan := make(chan cloudevents.Event, 10)
+	go func() {
+		_ = source.cloudEventsClient.StartReceiver(ctx, func(event cloudevents.Event) {
+			eventChan <- event
+		})
+	}()
+
+	objs := []*mockResource{
+		{UID: kubetypes.UID("r1"), ResourceVersion: "1", Namespace: "cluster1"},
+		{UID: kubetypes.UID("r2"), ResourceVersion: "1", Namespace: "cluster2"},
+		{UID: kubetypes.UID("r3"), ResourceVersion: "1", Namespace: "cluster1"},
+		{UID: kubetypes.UID("r4"), ResourceVersion: "1", Namespace: "cluster1"},
+		{UID: kubetypes.UID("r5"), ResourceVersion: "1", Namespace: "cluster2"},
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	// the resources are published in batch by type-asserting the client
+	var client CloudEventsClient[*mockResource] = source
+	publisher, ok := client.(BatchPublisher[*mockResource])
+	require.True(t, ok)
+
+	errs := publisher.PublishBatch(ctx, eventType, objs)
+	require.Len(t, errs, len(objs))
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	// the sending context is derived once for each cluster
+	require.Equal(t, int32(2), pipelined.contexts.Load())
+
+	received := map[string]string{}
+	for range objs {
+		select {
+		case evt := <-eventChan:
+			received[evt.Extensions()[types.ExtensionResourceID].(string)] =
+				evt.Extensions()[types.ExtensionClusterName].(string)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout to wait for the published events")
+		}
+	}
+	require.Equal(t, map[string]string{
+		"r1": "cluster1",
+		"r2": "cluster2",
+		"r3": "cluster1",
+		"r4": "cluster1",
+		"r5": "cluster2",
+	}, received)
+
+	// the unsupported event type fails all resources
+	errs = source.PublishBatch(ctx, types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "test_update_request",
+	}, objs)
+	require.Len(t, errs, len(objs))
+	for _, err := range errs {
+		require.Error(t, err)
+	}
+}
+
+func TestAgentPublishBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	agentOptions := fake.NewAgentOptions(gochan.New(), nil, "cluster1", testAgentName)
+	agent, err := NewCloudEventAgentClient[*mockResource](ctx, agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	eventChan := make(chan cloudevents.Event, 10)
+	go func() {
+		_ = agent.cloudEventsClient.StartReceiver(ctx, func(event cloudevents.Event) {
+			eventChan <- event
+		})
+	}()
+
+	objs := []*mockResource{
+		{UID: kubetypes.UID("r1"), ResourceVersion: "1", Namespace: "cluster1", Status: "a"},
+		{UID: kubetypes.UID("r2"), ResourceVersion: "1", Namespace: "cluster1", Status: "b"},
+	}
+
+	errs := agent.PublishBatch(ctx, types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "test_update_request",
+	}, objs)
+	require.Equal(t, []error{nil, nil}, errs)
+
+	// the events are sent one by one without pipelining
+	received := []string{}
+	for range objs {
+		select {
+		case evt := <-eventChan:
+			received = append(received, evt.Extensions()[types.ExtensionResourceID].(string))
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout to wait for the published events")
+		}
+	}
+	require.ElementsMatch(t, []string{"r1", "r2"}, received)
+}
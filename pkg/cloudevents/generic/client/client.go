@@ -0,0 +1,269 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/store"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	CreateRequestAction types.EventAction = "create_request"
+	UpdateRequestAction types.EventAction = "update_request"
+	DeleteRequestAction types.EventAction = "delete_request"
+)
+
+// ResourceClient implements the typed client interface of a Kubernetes-style resource, e.g. the
+// ManifestWorkInterface, on top of a source CloudEventsClient and a WatcherStore, so a typed clientset
+// can be backed by the CloudEvents with only a codec of the resource.
+//
+// The created/patched/deleted resources are published to the agents as the spec events and saved in the
+// store, the resources are got/listed/watched from the store.
+type ResourceClient[T store.Object, L runtime.Object] struct {
+	cloudEventsClient generic.CloudEventsClient[T]
+	watcherStore      store.WatcherStore[T]
+	namespace         string
+	sourceID          string
+	opts              ResourceClientOptions[T, L]
+}
+
+// NewResourceClient returns a ResourceClient for the given source.
+func NewResourceClient[T store.Object, L runtime.Object](
+	sourceID string,
+	cloudEventsClient generic.CloudEventsClient[T],
+	watcherStore store.WatcherStore[T],
+	opts ResourceClientOptions[T, L],
+) *ResourceClient[T, L] {
+	if opts.UID == nil {
+		opts.UID = DefaultUID
+	}
+
+	if opts.ResourceVersion == nil {
+		opts.ResourceVersion = DefaultResourceVersion[T]
+	}
+
+	return &ResourceClient[T, L]{
+		cloudEventsClient: cloudEventsClient,
+		watcherStore:      watcherStore,
+		sourceID:          sourceID,
+		opts:              opts,
+	}
+}
+
+func (c *ResourceClient[T, L]) SetNamespace(namespace string) {
+	c.namespace = namespace
+}
+
+func (c *ResourceClient[T, L]) Create(ctx context.Context, obj T, opts metav1.CreateOptions) (T, error) {
+	var empty T
+	if obj.GetNamespace() != "" && obj.GetNamespace() != c.namespace {
+		return empty, errors.NewInvalid(c.opts.GroupKind, obj.GetName(), field.ErrorList{
+			field.Invalid(
+				field.NewPath("metadata").Child("namespace"),
+				obj.GetNamespace(),
+				fmt.Sprintf("does not match the namespace %s", c.namespace),
+			),
+		})
+	}
+
+	_, exists, err := c.watcherStore.Get(c.namespace, obj.GetName())
+	if err != nil {
+		return empty, errors.NewInternalError(err)
+	}
+	if exists {
+		return empty, errors.NewAlreadyExists(c.opts.GroupResource, obj.GetName())
+	}
+
+	newObj, ok := obj.DeepCopyObject().(T)
+	if !ok {
+		return empty, errors.NewInternalError(fmt.Errorf("unknown type %T", obj))
+	}
+	newObj.SetUID(c.opts.UID(c.sourceID, c.opts.GroupResource, c.namespace, newObj.GetName()))
+	newObj.SetNamespace(c.namespace)
+	newObj.SetResourceVersion(c.opts.ResourceVersion(obj))
+
+	if err := c.validate(newObj); err != nil {
+		return empty, err
+	}
+
+	if err := c.publish(ctx, CreateRequestAction, newObj); err != nil {
+		return empty, err
+	}
+
+	// add the new resource to the local cache.
+	if err := c.watcherStore.Add(newObj); err != nil {
+		return empty, errors.NewInternalError(err)
+	}
+
+	return c.deepCopy(newObj), nil
+}
+
+func (c *ResourceClient[T, L]) Update(ctx context.Context, obj T, opts metav1.UpdateOptions) (T, error) {
+	var empty T
+	return empty, errors.NewMethodNotSupported(c.opts.GroupResource, "update")
+}
+
+func (c *ResourceClient[T, L]) UpdateStatus(ctx context.Context, obj T, opts metav1.UpdateOptions) (T, error) {
+	var empty T
+	return empty, errors.NewMethodNotSupported(c.opts.GroupResource, "updatestatus")
+}
+
+func (c *ResourceClient[T, L]) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	obj, exists, err := c.watcherStore.Get(c.namespace, name)
+	if err != nil {
+		return errors.NewInternalError(err)
+	}
+	if !exists {
+		return nil
+	}
+
+	deletingObj := c.deepCopy(obj)
+	now := metav1.Now()
+	deletingObj.SetDeletionTimestamp(&now)
+
+	if err := c.publish(ctx, DeleteRequestAction, deletingObj); err != nil {
+		return err
+	}
+
+	if len(obj.GetFinalizers()) == 0 {
+		// the resource has no any finalizers, the agent may not start yet or the deleted status of the resource
+		// is not handled yet, we delete this resource from the local cache directly, the store handler should
+		// ignore the status of the resource when it's back.
+		if err := c.watcherStore.Delete(deletingObj); err != nil {
+			return errors.NewInternalError(err)
+		}
+
+		return nil
+	}
+
+	// update the resource with deletion timestamp in the local cache.
+	if err := c.watcherStore.Update(deletingObj); err != nil {
+		return errors.NewInternalError(err)
+	}
+
+	return nil
+}
+
+func (c *ResourceClient[T, L]) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	return errors.NewMethodNotSupported(c.opts.GroupResource, "deletecollection")
+}
+
+func (c *ResourceClient[T, L]) Get(ctx context.Context, name string, opts metav1.GetOptions) (T, error) {
+	klog.V(4).Infof("getting %s %s", c.opts.GroupResource, name)
+
+	var empty T
+	obj, exists, err := c.watcherStore.Get(c.namespace, name)
+	if err != nil {
+		return empty, errors.NewInternalError(err)
+	}
+	if !exists {
+		return empty, errors.NewNotFound(c.opts.GroupResource, name)
+	}
+
+	return obj, nil
+}
+
+func (c *ResourceClient[T, L]) List(ctx context.Context, opts metav1.ListOptions) (L, error) {
+	klog.V(4).Infof("list %s", c.opts.GroupResource)
+
+	objs, err := c.watcherStore.List(c.namespace, opts)
+	if err != nil {
+		var empty L
+		return empty, errors.NewInternalError(err)
+	}
+
+	return c.opts.NewList(objs), nil
+}
+
+func (c *ResourceClient[T, L]) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	watcher, err := c.watcherStore.GetWatcher(c.namespace, opts)
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+
+	return watcher, nil
+}
+
+func (c *ResourceClient[T, L]) Patch(ctx context.Context, name string, pt kubetypes.PatchType, data []byte,
+	opts metav1.PatchOptions, subresources ...string) (T, error) {
+	klog.V(4).Infof("patching %s %s", c.opts.GroupResource, name)
+
+	var empty T
+	if len(subresources) != 0 {
+		msg := fmt.Sprintf("unsupported to update subresources %v", subresources)
+		return empty, errors.NewGenericServerResponse(
+			http.StatusMethodNotAllowed, "patch", c.opts.GroupResource, name, msg, 0, false)
+	}
+
+	lastObj, exists, err := c.watcherStore.Get(c.namespace, name)
+	if err != nil {
+		return empty, errors.NewInternalError(err)
+	}
+	if !exists {
+		return empty, errors.NewNotFound(c.opts.GroupResource, name)
+	}
+
+	patchedObj, err := Patch(pt, lastObj, data)
+	if err != nil {
+		return empty, errors.NewInternalError(err)
+	}
+	patchedObj.SetResourceVersion(c.opts.ResourceVersion(patchedObj))
+
+	if err := c.validate(patchedObj); err != nil {
+		return empty, err
+	}
+
+	if err := c.publish(ctx, UpdateRequestAction, patchedObj); err != nil {
+		return empty, err
+	}
+
+	// modify the updated resource in the local cache.
+	if err := c.watcherStore.Update(patchedObj); err != nil {
+		return empty, errors.NewInternalError(err)
+	}
+
+	return c.deepCopy(patchedObj), nil
+}
+
+func (c *ResourceClient[T, L]) validate(obj T) error {
+	if c.opts.Validate == nil {
+		return nil
+	}
+
+	if errs := c.opts.Validate(obj); len(errs) != 0 {
+		return errors.NewInvalid(c.opts.GroupKind, obj.GetName(), errs)
+	}
+
+	return nil
+}
+
+func (c *ResourceClient[T, L]) publish(ctx context.Context, action types.EventAction, obj T) error {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: c.opts.DataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              action,
+	}
+
+	if err := c.cloudEventsClient.Publish(ctx, eventType, obj); err != nil {
+		return NewPublishError(c.opts.GroupResource, obj.GetName(), err)
+	}
+
+	return nil
+}
+
+func (c *ResourceClient[T, L]) deepCopy(obj T) T {
+	// the object is copied from a T, so the assertion is always true
+	copied, _ := obj.DeepCopyObject().(T)
+	return copied
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/store"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// a ResourceClient of ManifestWorks is a ManifestWorkInterface
+var _ workv1client.ManifestWorkInterface = &ResourceClient[*workv1.ManifestWork, *workv1.ManifestWorkList]{}
+
+var configMapDataType = types.CloudEventsDataType{
+	Group:    "test",
+	Version:  "v1",
+	Resource: "configmaps",
+}
+
+type publishedConfigMap struct {
+	action types.EventAction
+	obj    *corev1.ConfigMap
+}
+
+type fakeCloudEventsClient struct {
+	generic.CloudEventsClient[*corev1.ConfigMap]
+	published  []publishedConfigMap
+	publishErr error
+}
+
+func (c *fakeCloudEventsClient) Publish(ctx context.Context, eventType types.CloudEventsType, obj *corev1.ConfigMap) error {
+	if c.publishErr != nil {
+		return c.publishErr
+	}
+
+	c.published = append(c.published, publishedConfigMap{action: eventType.Action, obj: obj})
+	return nil
+}
+
+func newConfigMapClient(t *testing.T, cloudEventsClient *fakeCloudEventsClient) *ResourceClient[*corev1.ConfigMap, *corev1.ConfigMapList] {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	watcherStore, err := store.NewLocalWatcherStore(ctx,
+		func(ctx context.Context) ([]*corev1.ConfigMap, error) { return nil, nil },
+		func(store store.WatcherStore[*corev1.ConfigMap], action types.ResourceAction, obj *corev1.ConfigMap) error {
+			return nil
+		})
+	require.NoError(t, err)
+
+	client := NewResourceClient("source1", cloudEventsClient, watcherStore,
+		ResourceClientOptions[*corev1.ConfigMap, *corev1.ConfigMapList]{
+			GroupResource: schema.GroupResource{Resource: "configmaps"},
+			GroupKind:     schema.GroupKind{Kind: "ConfigMap"},
+			DataType:      configMapDataType,
+			NewList: func(items []*corev1.ConfigMap) *corev1.ConfigMapList {
+				list := &corev1.ConfigMapList{}
+				for _, item := range items {
+					list.Items = append(list.Items, *item)
+				}
+				return list
+			},
+			Validate: func(obj *corev1.ConfigMap) field.ErrorList {
+				if _, ok := obj.Data["invalid"]; ok {
+					return field.ErrorList{field.Invalid(field.NewPath("data"), obj.Data, "invalid data")}
+				}
+				return nil
+			},
+		})
+	client.SetNamespace("ns1")
+	return client
+}
+
+func TestResourceClient(t *testing.T) {
+	ctx := context.TODO()
+	cloudEventsClient := &fakeCloudEventsClient{}
+	client := newConfigMapClient(t, cloudEventsClient)
+
+	// create
+	created, err := client.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm1"},
+		Data:       map[string]string{"a": "b"},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "ns1", created.Namespace)
+	require.Equal(t, DefaultUID("source1", schema.GroupResource{Resource: "configmaps"}, "ns1", "cm1"), created.UID)
+	require.Equal(t, "0", created.ResourceVersion)
+	require.Len(t, cloudEventsClient.published, 1)
+	require.Equal(t, CreateRequestAction, cloudEventsClient.published[0].action)
+
+	_, err = client.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm1"}}, metav1.CreateOptions{})
+	require.True(t, errors.IsAlreadyExists(err))
+
+	_, err = client.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm2", Namespace: "ns2"},
+	}, metav1.CreateOptions{})
+	require.True(t, errors.IsInvalid(err))
+
+	_, err = client.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm2"},
+		Data:       map[string]string{"invalid": "true"},
+	}, metav1.CreateOptions{})
+	require.True(t, errors.IsInvalid(err))
+	require.Len(t, cloudEventsClient.published, 1)
+
+	// get and list
+	got, err := client.Get(ctx, "cm1", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, created.UID, got.UID)
+
+	_, err = client.Get(ctx, "cm2", metav1.GetOptions{})
+	require.True(t, errors.IsNotFound(err))
+
+	list, err := client.List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+
+	// patch
+	patched, err := client.Patch(ctx, "cm1", kubetypes.MergePatchType,
+		[]byte(`{"metadata":{"labels":{"app":"test"}},"data":{"a":"c"}}`), metav1.PatchOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "test", patched.Labels["app"])
+	require.Equal(t, "c", patched.Data["a"])
+	require.Len(t, cloudEventsClient.published, 2)
+	require.Equal(t, UpdateRequestAction, cloudEventsClient.published[1].action)
+
+	list, err = client.List(ctx, metav1.ListOptions{LabelSelector: "app=test"})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+
+	_, err = client.Patch(ctx, "cm1", kubetypes.MergePatchType, []byte(`{}`), metav1.PatchOptions{}, "status")
+	require.True(t, errors.IsMethodNotSupported(err))
+
+	_, err = client.Patch(ctx, "cm2", kubetypes.MergePatchType, []byte(`{}`), metav1.PatchOptions{})
+	require.True(t, errors.IsNotFound(err))
+
+	cloudEventsClient.publishErr = fmt.Errorf("failed")
+	_, err = client.Patch(ctx, "cm1", kubetypes.MergePatchType, []byte(`{"data":{"a":"d"}}`), metav1.PatchOptions{})
+	require.True(t, IsPublishError(err))
+	got, err = client.Get(ctx, "cm1", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "c", got.Data["a"])
+	cloudEventsClient.publishErr = nil
+
+	// delete
+	require.NoError(t, client.Delete(ctx, "cm1", metav1.DeleteOptions{}))
+	require.Len(t, cloudEventsClient.published, 3)
+	require.Equal(t, DeleteRequestAction, cloudEventsClient.published[2].action)
+	require.NotNil(t, cloudEventsClient.published[2].obj.DeletionTimestamp)
+
+	_, err = client.Get(ctx, "cm1", metav1.GetOptions{})
+	require.True(t, errors.IsNotFound(err))
+
+	// delete a nonexistent resource
+	require.NoError(t, client.Delete(ctx, "cm1", metav1.DeleteOptions{}))
+	require.Len(t, cloudEventsClient.published, 3)
+}
+
+func TestPatch(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm1", Labels: map[string]string{"a": "b", "c": "d"}},
+	}
+
+	patched, err := Patch(kubetypes.MergePatchType, cm, []byte(`{"metadata":{"labels":{"c":null}}}`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "b"}, patched.Labels)
+	require.Len(t, cm.Labels, 2)
+
+	patched, err = Patch(kubetypes.JSONPatchType, cm, []byte(`[{"op":"replace","path":"/metadata/name","value":"cm2"}]`))
+	require.NoError(t, err)
+	require.Equal(t, "cm2", patched.Name)
+
+	_, err = Patch(kubetypes.StrategicMergePatchType, cm, []byte(`{}`))
+	require.Error(t, err)
+}
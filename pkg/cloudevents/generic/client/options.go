@@ -0,0 +1,85 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/store"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// UIDFunc returns the UID of a resource that is created by a source client, the UID is used as the resource ID of
+// the resource, so the returned UID must be unique and consistent for a resource.
+type UIDFunc func(sourceID string, gr schema.GroupResource, namespace, name string) kubetypes.UID
+
+// ResourceVersionFunc returns the resource version of a resource that will be published by a source client, the
+// resource version must be an int64 sequence number, "0" means the version of the resource is not maintained by the
+// source and the message broker guarantees the resource update order.
+type ResourceVersionFunc[T store.Object] func(obj T) string
+
+// ListFunc builds a typed list object, e.g. a ManifestWorkList, with the given resources.
+type ListFunc[T store.Object, L runtime.Object] func(items []T) L
+
+// ValidateFunc validates a resource before the resource is published.
+type ValidateFunc[T store.Object] func(obj T) field.ErrorList
+
+// ResourceClientOptions holds the options to build a ResourceClient.
+type ResourceClientOptions[T store.Object, L runtime.Object] struct {
+	// GroupResource is the group resource of the resource, it's used in the returned errors, e.g. NotFound.
+	GroupResource schema.GroupResource
+
+	// GroupKind is the group kind of the resource, it's used in the returned Invalid errors.
+	GroupKind schema.GroupKind
+
+	// DataType is the CloudEvents data type of the events that are published for the resource, it must be the data
+	// type of a codec of the CloudEvents client.
+	DataType types.CloudEventsDataType
+
+	// NewList builds the typed list object that is returned by List, it's required.
+	NewList ListFunc[T, L]
+
+	// UID generates the UID of a created resource. If it's nil, the DefaultUID will be used.
+	UID UIDFunc
+
+	// ResourceVersion returns the resource version of a published resource. If it's nil, the DefaultResourceVersion
+	// will be used.
+	ResourceVersion ResourceVersionFunc[T]
+
+	// Validate validates a resource before it's created or patched. If it's nil, the resource is not validated.
+	Validate ValidateFunc[T]
+}
+
+// DefaultUID returns a v5 UUID based on the source ID, the group resource, the namespace and the name of a resource
+// to make sure it is consistent.
+func DefaultUID(sourceID string, gr schema.GroupResource, namespace, name string) kubetypes.UID {
+	id := fmt.Sprintf("%s-%s-%s-%s", sourceID, gr.String(), namespace, name)
+	return kubetypes.UID(uuid.NewSHA1(uuid.NameSpaceOID, []byte(id)).String())
+}
+
+// DefaultResourceVersion returns the resource version of the resource itself, if the resource does not have it,
+// "0" will be returned.
+func DefaultResourceVersion[T store.Object](obj T) string {
+	if obj.GetResourceVersion() != "" {
+		return obj.GetResourceVersion()
+	}
+
+	return "0"
+}
+
+// AnnotationResourceVersion returns a ResourceVersionFunc that gets the resource version from the annotation with the
+// given key firstly, if no annotation is set, the DefaultResourceVersion will be used.
+func AnnotationResourceVersion[T store.Object](key string) ResourceVersionFunc[T] {
+	return func(obj T) string {
+		if resourceVersion, ok := obj.GetAnnotations()[key]; ok {
+			return resourceVersion
+		}
+
+		return DefaultResourceVersion(obj)
+	}
+}
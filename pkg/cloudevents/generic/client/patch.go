@@ -0,0 +1,60 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/store"
+)
+
+// Patch applies the patch to a resource with the patch type, the given resource is not modified.
+func Patch[T store.Object](patchType kubetypes.PatchType, obj T, patchData []byte) (T, error) {
+	var empty T
+
+	objData, err := json.Marshal(obj)
+	if err != nil {
+		return empty, err
+	}
+
+	var patchedData []byte
+	switch patchType {
+	case kubetypes.JSONPatchType:
+		var patchObj jsonpatch.Patch
+		patchObj, err = jsonpatch.DecodePatch(patchData)
+		if err != nil {
+			return empty, err
+		}
+		patchedData, err = patchObj.Apply(objData)
+		if err != nil {
+			return empty, err
+		}
+
+	case kubetypes.MergePatchType:
+		patchedData, err = jsonpatch.MergePatch(objData, patchData)
+		if err != nil {
+			return empty, err
+		}
+	default:
+		return empty, fmt.Errorf("unsupported patch type: %s", patchType)
+	}
+
+	objType := reflect.TypeOf(obj)
+	if objType.Kind() != reflect.Pointer {
+		return empty, fmt.Errorf("unsupported object type %T", obj)
+	}
+
+	patchedObj, ok := reflect.New(objType.Elem()).Interface().(T)
+	if !ok {
+		return empty, fmt.Errorf("unsupported object type %T", obj)
+	}
+	if err := json.Unmarshal(patchedData, patchedObj); err != nil {
+		return empty, err
+	}
+
+	return patchedObj, nil
+}
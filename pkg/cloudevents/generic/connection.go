@@ -0,0 +1,264 @@
+package generic
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// The defaults of the ReconnectPolicy and the ReconnectCircuitBreaker.
+const (
+	DefaultReconnectBaseDelay     = 5 * time.Second
+	DefaultReconnectMaxDelay      = 1 * time.Minute
+	DefaultReconnectFactor        = 5.0
+	DefaultReconnectJitter        = 1.0
+	DefaultReconnectResetInterval = 10 * time.Minute
+
+	DefaultCircuitBreakerFailureThreshold = 5
+	DefaultCircuitBreakerOpenDuration     = 5 * time.Minute
+)
+
+// ConnectionState is the state of the connection of a source/agent client.
+type ConnectionState string
+
+const (
+	// ConnectionStateConnecting represents the client is connecting to the broker.
+	ConnectionStateConnecting ConnectionState = "Connecting"
+
+	// ConnectionStateReady represents the client is connected and able to send/receive the events.
+	ConnectionStateReady ConnectionState = "Ready"
+
+	// ConnectionStateDisconnected represents the connection is broken, the client will reconnect after a delay.
+	ConnectionStateDisconnected ConnectionState = "Disconnected"
+
+	// ConnectionStateClosed represents the client is stopped or gives up reconnecting, it will not reconnect anymore.
+	ConnectionStateClosed ConnectionState = "Closed"
+)
+
+// ConnectionStateChange represents a transition of the connection state of a source/agent client.
+type ConnectionStateChange struct {
+	From ConnectionState
+	To   ConnectionState
+
+	// Err is the error that causes the transition, e.g. the connection error when the client is disconnected.
+	Err error
+}
+
+// ConnectionStateHandler is called when the connection state of a source/agent client is changed.
+type ConnectionStateHandler func(change ConnectionStateChange)
+
+// connectionStateTracker tracks the connection state of a client and notifies the state changes to the subscribers.
+// The changes are queued for each subscriber, so a slow subscriber does not block the client or the other
+// subscribers, and every subscriber receives the changes in order.
+type connectionStateTracker struct {
+	sync.RWMutex
+
+	clientID    string
+	state       ConnectionState
+	subscribers []*connectionStateSubscriber
+}
+
+func newConnectionStateTracker(clientID string) *connectionStateTracker {
+	return &connectionStateTracker{
+		clientID: clientID,
+		state:    ConnectionStateConnecting,
+	}
+}
+
+func (t *connectionStateTracker) get() ConnectionState {
+	t.RLock()
+	defer t.RUnlock()
+	return t.state
+}
+
+// set transits the connection state to the given state, it does nothing if the state is not changed.
+func (t *connectionStateTracker) set(state ConnectionState, err error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.state == state {
+		return
+	}
+
+	change := ConnectionStateChange{From: t.state, To: state, Err: err}
+	klog.V(4).Infof("the connection state of the client %s is changed from %s to %s", t.clientID, change.From, change.To)
+	t.state = state
+
+	subscribers := []*connectionStateSubscriber{}
+	for _, subscriber := range t.subscribers {
+		if subscriber.ctx.Err() != nil {
+			// the subscription is canceled
+			continue
+		}
+
+		subscriber.enqueue(change)
+		subscribers = append(subscribers, subscriber)
+	}
+	t.subscribers = subscribers
+}
+
+// subscribe starts a go routine to call the handler with the state changes until the context is done.
+func (t *connectionStateTracker) subscribe(ctx context.Context, handler ConnectionStateHandler) {
+	subscriber := &connectionStateSubscriber{
+		ctx:     ctx,
+		handler: handler,
+		notify:  make(chan struct{}, 1),
+	}
+
+	t.Lock()
+	t.subscribers = append(t.subscribers, subscriber)
+	t.Unlock()
+
+	go subscriber.run()
+}
+
+type connectionStateSubscriber struct {
+	sync.Mutex
+
+	ctx     context.Context
+	handler ConnectionStateHandler
+	changes []ConnectionStateChange
+	notify  chan struct{}
+}
+
+func (s *connectionStateSubscriber) enqueue(change ConnectionStateChange) {
+	s.Lock()
+	s.changes = append(s.changes, change)
+	s.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+		// the subscriber has been notified
+	}
+}
+
+func (s *connectionStateSubscriber) next() (ConnectionStateChange, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	if len(s.changes) == 0 {
+		return ConnectionStateChange{}, false
+	}
+
+	change := s.changes[0]
+	s.changes = s.changes[1:]
+	return change, true
+}
+
+func (s *connectionStateSubscriber) run() {
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.notify:
+			for change, ok := s.next(); ok; change, ok = s.next() {
+				s.handler(change)
+			}
+		}
+	}
+}
+
+// reconnectBackoff computes the delays of the reconnection attempts of a client and decides when the client gives up
+// reconnecting.
+type reconnectBackoff struct {
+	delayFn          func() time.Duration
+	maxAttempts      int
+	failureThreshold int
+	openDuration     time.Duration
+	failures         int
+}
+
+func newReconnectBackoff(policy *options.ReconnectPolicy) *reconnectBackoff {
+	if policy == nil {
+		// use the package level DelayFn
+		return &reconnectBackoff{}
+	}
+
+	baseDelay := policy.BaseDelay
+	if baseDelay <= 0 {
+		baseDelay = DefaultReconnectBaseDelay
+	}
+
+	maxDelay := policy.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultReconnectMaxDelay
+	}
+
+	factor := policy.Factor
+	if factor <= 1 {
+		factor = DefaultReconnectFactor
+	}
+
+	jitter := policy.Jitter
+	if jitter <= 0 {
+		jitter = DefaultReconnectJitter
+	}
+
+	resetInterval := policy.ResetInterval
+	if resetInterval <= 0 {
+		resetInterval = DefaultReconnectResetInterval
+	}
+
+	backoff := &reconnectBackoff{
+		delayFn: wait.Backoff{
+			Duration: baseDelay,
+			Cap:      maxDelay,
+			Steps:    math.MaxInt32, // the delay grows until it reaches the max delay
+			Factor:   factor,
+			Jitter:   jitter,
+		}.DelayWithReset(&clock.RealClock{}, resetInterval),
+		maxAttempts: policy.MaxAttempts,
+	}
+
+	if policy.CircuitBreaker != nil {
+		backoff.failureThreshold = policy.CircuitBreaker.FailureThreshold
+		if backoff.failureThreshold <= 0 {
+			backoff.failureThreshold = DefaultCircuitBreakerFailureThreshold
+		}
+
+		backoff.openDuration = policy.CircuitBreaker.OpenDuration
+		if backoff.openDuration <= 0 {
+			backoff.openDuration = DefaultCircuitBreakerOpenDuration
+		}
+	}
+
+	return backoff
+}
+
+// delay returns the delay before the next reconnection attempt.
+func (b *reconnectBackoff) delay() time.Duration {
+	if b.delayFn == nil {
+		return DelayFn()
+	}
+	return b.delayFn()
+}
+
+// failed records a failed reconnection attempt and returns the delay before the next attempt, it returns false if the
+// attempts are exhausted.
+func (b *reconnectBackoff) failed() (time.Duration, bool) {
+	b.failures++
+
+	if b.maxAttempts > 0 && b.failures >= b.maxAttempts {
+		return 0, false
+	}
+
+	if b.failureThreshold > 0 && b.failures >= b.failureThreshold {
+		klog.Warningf("the reconnection failed %d times, open the circuit for %v", b.failures, b.openDuration)
+		return b.openDuration, true
+	}
+
+	return b.delay(), true
+}
+
+// succeeded resets the consecutive failures after the client is reconnected.
+func (b *reconnectBackoff) succeeded() {
+	b.failures = 0
+}
@@ -0,0 +1,196 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/stretchr/testify/require"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+)
+
+// flakyOptions returns the protocol for the first connection and fails the following connections.
+type flakyOptions struct {
+	options.CloudEventsOptions
+
+	sync.Mutex
+	connections int
+}
+
+func (o *flakyOptions) Protocol(ctx context.Context) (options.CloudEventsProtocol, error) {
+	o.Lock()
+	defer o.Unlock()
+
+	o.connections++
+	if o.connections > 1 {
+		return nil, fmt.Errorf("broker is unavailable")
+	}
+	return o.CloudEventsOptions.Protocol(ctx)
+}
+
+func TestConnectionStateChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errChan := make(chan error)
+	agentOptions := fake.NewAgentOptions(gochan.New(), errChan, "cluster1", testAgentName)
+	agentOptions.CloudEventsOptions = &flakyOptions{CloudEventsOptions: agentOptions.CloudEventsOptions}
+	agentOptions.Reconnect = &options.ReconnectPolicy{
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+		MaxAttempts: 2,
+	}
+	agent, err := NewCloudEventAgentClient[*mockResource](ctx, agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	// the connection state is observed by type-asserting the client
+	var client CloudEventsClient[*mockResource] = agent
+	observer, ok := client.(ConnectionStateObserver)
+	require.True(t, ok)
+	require.Equal(t, ConnectionStateReady, observer.ConnectionState())
+
+	changes := make(chan ConnectionStateChange, 10)
+	observer.OnConnectionStateChange(ctx, func(change ConnectionStateChange) {
+		changes <- change
+	})
+
+	// mimic agent disconnection by sending an error, the agent fails to reconnect and gives up after two attempts
+	errChan <- fmt.Errorf("test error")
+
+	expected := []ConnectionState{
+		ConnectionStateDisconnected,
+		ConnectionStateConnecting,
+		ConnectionStateDisconnected,
+		ConnectionStateConnecting,
+		ConnectionStateDisconnected,
+		ConnectionStateClosed,
+	}
+	from := ConnectionStateReady
+	for _, state := range expected {
+		select {
+		case change := <-changes:
+			require.Equal(t, from, change.From)
+			require.Equal(t, state, change.To)
+			from = state
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout to wait for the connection state %s", state)
+		}
+	}
+	require.Equal(t, ConnectionStateClosed, agent.ConnectionState())
+}
+
+func TestConnectionStateSubscriberNotBlocking(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tracker := newConnectionStateTracker(testAgentName)
+
+	block := make(chan struct{})
+	slowChanges := make(chan ConnectionStateChange, 10)
+	tracker.subscribe(ctx, func(change ConnectionStateChange) {
+		<-block
+		slowChanges <- change
+	})
+
+	fastChanges := make(chan ConnectionStateChange, 10)
+	tracker.subscribe(ctx, func(change ConnectionStateChange) {
+		fastChanges <- change
+	})
+
+	// the slow subscriber does not block the state changes and the other subscribers
+	states := []ConnectionState{ConnectionStateReady, ConnectionStateDisconnected, ConnectionStateConnecting}
+	for _, state := range states {
+		tracker.set(state, nil)
+	}
+	// the same state is not notified
+	tracker.set(ConnectionStateConnecting, nil)
+
+	for _, state := range states {
+		require.Equal(t, state, (<-fastChanges).To)
+	}
+
+	close(block)
+	for _, state := range states {
+		require.Equal(t, state, (<-slowChanges).To)
+	}
+	require.Empty(t, slowChanges)
+	require.Empty(t, fastChanges)
+}
+
+func TestReconnectBackoff(t *testing.T) {
+	cases := []struct {
+		name           string
+		policy         *options.ReconnectPolicy
+		failures       int
+		expectedDelays []time.Duration
+		expectedGiveUp bool
+	}{
+		{
+			name: "backoff",
+			policy: &options.ReconnectPolicy{
+				BaseDelay: time.Second,
+				MaxDelay:  4 * time.Second,
+				Factor:    2,
+				Jitter:    0.001,
+			},
+			failures:       4,
+			expectedDelays: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second},
+		},
+		{
+			name: "max attempts",
+			policy: &options.ReconnectPolicy{
+				BaseDelay:   time.Second,
+				MaxDelay:    time.Second,
+				Jitter:      0.001,
+				MaxAttempts: 3,
+			},
+			failures:       3,
+			expectedDelays: []time.Duration{time.Second, time.Second},
+			expectedGiveUp: true,
+		},
+		{
+			name: "circuit breaker",
+			policy: &options.ReconnectPolicy{
+				BaseDelay: time.Second,
+				MaxDelay:  time.Second,
+				Jitter:    0.001,
+				CircuitBreaker: &options.ReconnectCircuitBreaker{
+					FailureThreshold: 2,
+					OpenDuration:     time.Hour,
+				},
+			},
+			failures:       3,
+			expectedDelays: []time.Duration{time.Second, time.Hour, time.Hour},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			backoff := newReconnectBackoff(c.policy)
+
+			delays := []time.Duration{}
+			giveUp := false
+			for i := 0; i < c.failures; i++ {
+				delay, retry := backoff.failed()
+				if !retry {
+					giveUp = true
+					break
+				}
+				// truncate the jitter
+				delays = append(delays, delay.Truncate(100*time.Millisecond))
+			}
+
+			require.Equal(t, c.expectedDelays, delays)
+			require.Equal(t, c.expectedGiveUp, giveUp)
+
+			// the failures are reset after the client is reconnected
+			backoff.succeeded()
+			require.Equal(t, 0, backoff.failures)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package generic
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"k8s.io/apimachinery/pkg/version"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// EventConvertFunc converts the data of an event to another version of its data type, the type of the converted
+// event is set by the ConversionRegistry.
+type EventConvertFunc func(evt cloudevents.Event) (cloudevents.Event, error)
+
+// ConversionRegistry registers the conversions between the versions of the cloud events data types, it implements
+// the EventConverter. A conversion is registered for each pair of the versions, the conversions are not chained.
+type ConversionRegistry struct {
+	sync.RWMutex
+
+	// conversions is keyed by the data type that is converted from and the version that is converted to
+	conversions map[types.CloudEventsDataType]map[string]EventConvertFunc
+}
+
+var _ options.EventConverter = &ConversionRegistry{}
+
+// NewConversionRegistry returns an empty ConversionRegistry.
+func NewConversionRegistry() *ConversionRegistry {
+	return &ConversionRegistry{
+		conversions: map[types.CloudEventsDataType]map[string]EventConvertFunc{},
+	}
+}
+
+// Register registers a conversion from one version of a data type to another.
+func (r *ConversionRegistry) Register(from, to types.CloudEventsDataType, convert EventConvertFunc) error {
+	if from.Group != to.Group || from.Resource != to.Resource {
+		return fmt.Errorf("unsupported conversion from %s to %s, the data types are different", from, to)
+	}
+
+	if from.Version == to.Version {
+		return fmt.Errorf("unsupported conversion from %s to %s, the versions are same", from, to)
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.conversions[from]; !ok {
+		r.conversions[from] = map[string]EventConvertFunc{}
+	}
+	r.conversions[from][to.Version] = convert
+	return nil
+}
+
+// ConvertibleVersions returns the versions that the events of the given data type can be converted to, the versions
+// are sorted from the newest to the oldest.
+func (r *ConversionRegistry) ConvertibleVersions(dataType types.CloudEventsDataType) []string {
+	r.RLock()
+	defer r.RUnlock()
+
+	versions := []string{}
+	for v := range r.conversions[dataType] {
+		versions = append(versions, v)
+	}
+	sortVersions(versions)
+	return versions
+}
+
+// Convert converts the event to the given version of its data type.
+func (r *ConversionRegistry) Convert(evt cloudevents.Event, version string) (cloudevents.Event, error) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return evt, err
+	}
+
+	if eventType.CloudEventsDataType.Version == version {
+		return evt, nil
+	}
+
+	r.RLock()
+	convert, ok := r.conversions[eventType.CloudEventsDataType][version]
+	r.RUnlock()
+	if !ok {
+		return evt, fmt.Errorf("no conversion from %s to the version %s", eventType.CloudEventsDataType, version)
+	}
+
+	converted, err := convert(evt.Clone())
+	if err != nil {
+		return evt, fmt.Errorf("failed to convert the event %s to the version %s, %v", evt.ID(), version, err)
+	}
+
+	eventType.CloudEventsDataType.Version = version
+	converted.SetType(eventType.String())
+	return converted, nil
+}
+
+// sortVersions sorts the kube-like versions from the newest to the oldest, e.g. v2, v1, v1beta1, v1alpha1.
+func sortVersions(versions []string) {
+	sort.Slice(versions, func(i, j int) bool {
+		return version.CompareKubeAwareVersionStrings(versions[i], versions[j]) > 0
+	})
+}
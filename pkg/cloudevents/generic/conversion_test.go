@@ -0,0 +1,75 @@
+package generic
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+var mockEventDataTypeV2 = types.CloudEventsDataType{
+	Group:    mockEventDataType.Group,
+	Version:  "v2",
+	Resource: mockEventDataType.Resource,
+}
+
+// newMockConversionRegistry returns a registry that converts the mock resources between v1 and v2, the converted
+// event data records the version that the event is converted to.
+func newMockConversionRegistry(t *testing.T) *ConversionRegistry {
+	registry := NewConversionRegistry()
+	require.NoError(t, registry.Register(mockEventDataType, mockEventDataTypeV2, func(evt cloudevents.Event) (cloudevents.Event, error) {
+		err := evt.SetData(cloudevents.ApplicationJSON, map[string]string{"convertedTo": "v2"})
+		return evt, err
+	}))
+	require.NoError(t, registry.Register(mockEventDataTypeV2, mockEventDataType, func(evt cloudevents.Event) (cloudevents.Event, error) {
+		err := evt.SetData(cloudevents.ApplicationJSON, map[string]string{"convertedTo": "v1"})
+		return evt, err
+	}))
+	return registry
+}
+
+func newMockEvent(t *testing.T, dataType types.CloudEventsDataType, action types.EventAction) cloudevents.Event {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: dataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              action,
+	}
+
+	evt := types.NewEventBuilder("source1", eventType).WithClusterName("cluster1").NewEvent()
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, map[string]string{}))
+	return evt
+}
+
+func TestConversionRegistry(t *testing.T) {
+	registry := newMockConversionRegistry(t)
+
+	require.Error(t, registry.Register(mockEventDataType, types.HeartbeatEventDataType, nil))
+	require.Error(t, registry.Register(mockEventDataType, mockEventDataType, nil))
+
+	require.Equal(t, []string{"v2"}, registry.ConvertibleVersions(mockEventDataType))
+	require.Empty(t, registry.ConvertibleVersions(types.HeartbeatEventDataType))
+
+	evt := newMockEvent(t, mockEventDataType, "create_request")
+	converted, err := registry.Convert(evt, "v2")
+	require.NoError(t, err)
+	require.Equal(t, "resources.test.v2.mockresources.spec.create_request", converted.Type())
+	require.JSONEq(t, `{"convertedTo":"v2"}`, string(converted.Data()))
+	require.Equal(t, evt.ID(), converted.ID())
+	require.Equal(t, "resources.test.v1.mockresources.spec.create_request", evt.Type())
+
+	// the event is not changed if it's in the version already
+	converted, err = registry.Convert(evt, "v1")
+	require.NoError(t, err)
+	require.Equal(t, evt, converted)
+
+	_, err = registry.Convert(evt, "v3")
+	require.Error(t, err)
+}
+
+func TestSortVersions(t *testing.T) {
+	versions := []string{"v1alpha1", "v1", "v2beta1", "v1beta1", "v2"}
+	sortVersions(versions)
+	require.Equal(t, []string{"v2", "v1", "v2beta1", "v1beta1", "v1alpha1"}, versions)
+}
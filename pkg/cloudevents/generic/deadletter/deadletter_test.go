@@ -0,0 +1,100 @@
+package deadletter
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+func newDeadLetter(id string) options.DeadLetter {
+	evt := cloudevents.NewEvent()
+	evt.SetID(id)
+	evt.SetSource("test-source")
+	evt.SetType("test.v1.resources.spec.create_request")
+	evt.SetExtension("resourceid", id)
+	_ = evt.SetData(cloudevents.ApplicationJSON, map[string]string{"id": id})
+
+	return options.DeadLetter{
+		ClientID:  "test-client",
+		Stage:     options.DeadLetterStageDecode,
+		Reason:    "failed to decode",
+		Timestamp: time.Now().Truncate(time.Second),
+		Event:     evt,
+	}
+}
+
+func TestRingBufferSink(t *testing.T) {
+	cases := []struct {
+		name        string
+		size        int
+		letters     int
+		expectedIDs []string
+	}{
+		{
+			name:        "buffer is not full",
+			size:        3,
+			letters:     2,
+			expectedIDs: []string{"0", "1"},
+		},
+		{
+			name:        "buffer is full",
+			size:        3,
+			letters:     3,
+			expectedIDs: []string{"0", "1", "2"},
+		},
+		{
+			name:        "overwrite the oldest letters",
+			size:        3,
+			letters:     5,
+			expectedIDs: []string{"2", "3", "4"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sink := NewRingBufferSink(c.size)
+			for i := 0; i < c.letters; i++ {
+				require.NoError(t, sink.Put(context.TODO(), newDeadLetter(fmt.Sprintf("%d", i))))
+			}
+
+			ids := []string{}
+			for _, letter := range sink.List() {
+				ids = append(ids, letter.Event.ID())
+			}
+			require.Equal(t, c.expectedIDs, ids)
+		})
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deadletters.jsonl")
+
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+
+	expected := []options.DeadLetter{newDeadLetter("1"), newDeadLetter("2")}
+	for _, letter := range expected {
+		require.NoError(t, sink.Put(context.TODO(), letter))
+	}
+	require.NoError(t, sink.Close())
+
+	letters, err := LoadFile(path)
+	require.NoError(t, err)
+	require.Len(t, letters, len(expected))
+	for i, letter := range letters {
+		require.Equal(t, expected[i].ClientID, letter.ClientID)
+		require.Equal(t, expected[i].Stage, letter.Stage)
+		require.Equal(t, expected[i].Reason, letter.Reason)
+		require.True(t, expected[i].Timestamp.Equal(letter.Timestamp))
+		require.Equal(t, expected[i].Event.ID(), letter.Event.ID())
+		require.Equal(t, expected[i].Event.Extensions(), letter.Event.Extensions())
+		require.Equal(t, expected[i].Event.Data(), letter.Event.Data())
+	}
+}
@@ -0,0 +1,91 @@
+package deadletter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// FileSink appends the dead letters to a file in JSON Lines format, each line is a JSON encoded dead letter
+// whose event is in the structured CloudEvents JSON format.
+type FileSink struct {
+	sync.Mutex
+
+	file *os.File
+}
+
+var _ options.DeadLetterSink = &FileSink{}
+
+// NewFileSink returns a FileSink that appends the dead letters to the given file, the file is created if it does
+// not exist.
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dead letter file %s, %v", path, err)
+	}
+
+	return &FileSink{file: file}, nil
+}
+
+// Put appends a dead letter to the file.
+func (s *FileSink) Put(ctx context.Context, letter options.DeadLetter) error {
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter, %v", err)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write dead letter to %s, %v", s.file.Name(), err)
+	}
+
+	return nil
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.file.Close()
+}
+
+// LoadFile reads the dead letters from a file that is written by a FileSink, the dead letters can be used to
+// inspect or replay the failed events.
+func LoadFile(path string) ([]options.DeadLetter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	letters := []options.DeadLetter{}
+	scanner := bufio.NewScanner(file)
+	// an event may be larger than the default max token size (64KB)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		letter := options.DeadLetter{}
+		if err := json.Unmarshal(scanner.Bytes(), &letter); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal dead letter %s, %v", scanner.Text(), err)
+		}
+
+		letters = append(letters, letter)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return letters, nil
+}
@@ -0,0 +1,62 @@
+package deadletter
+
+import (
+	"context"
+	"sync"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// DefaultRingBufferSize is the default number of the dead letters that are kept in a RingBufferSink.
+const DefaultRingBufferSize = 100
+
+// RingBufferSink keeps the latest dead letters in memory, the oldest dead letter is overwritten when the buffer
+// is full.
+type RingBufferSink struct {
+	sync.RWMutex
+
+	letters []options.DeadLetter
+	next    int
+	full    bool
+}
+
+var _ options.DeadLetterSink = &RingBufferSink{}
+
+// NewRingBufferSink returns a RingBufferSink with the given size. If the size is less than or equal to zero, the
+// DefaultRingBufferSize will be used.
+func NewRingBufferSink(size int) *RingBufferSink {
+	if size <= 0 {
+		size = DefaultRingBufferSize
+	}
+
+	return &RingBufferSink{
+		letters: make([]options.DeadLetter, size),
+	}
+}
+
+// Put saves a dead letter to the buffer.
+func (s *RingBufferSink) Put(ctx context.Context, letter options.DeadLetter) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.letters[s.next] = letter
+	s.next = (s.next + 1) % len(s.letters)
+	if s.next == 0 {
+		s.full = true
+	}
+
+	return nil
+}
+
+// List returns the dead letters in the buffer from the oldest to the newest.
+func (s *RingBufferSink) List() []options.DeadLetter {
+	s.RLock()
+	defer s.RUnlock()
+
+	if !s.full {
+		return append([]options.DeadLetter{}, s.letters[:s.next]...)
+	}
+
+	letters := append([]options.DeadLetter{}, s.letters[s.next:]...)
+	return append(letters, s.letters[:s.next]...)
+}
@@ -0,0 +1,148 @@
+package generic
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	// DefaultDeduplicationTTL is the default duration that a received event is remembered for deduplication.
+	DefaultDeduplicationTTL = 10 * time.Minute
+
+	// DefaultDeduplicationMaxSize is the default maximum number of the remembered events for deduplication.
+	DefaultDeduplicationMaxSize = 10000
+)
+
+// dedupCache remembers the keys of the received events for a TTL. The keys are kept in the order they're added, since
+// all keys have the same TTL, the oldest key is the first one to expire or to be evicted when the cache is full.
+type dedupCache struct {
+	sync.Mutex
+
+	clientID string
+	ttl      time.Duration
+	maxSize  int
+	clock    clock.PassiveClock
+	keys     *list.List
+	elements map[string]*list.Element
+}
+
+type dedupEntry struct {
+	key    string
+	expiry time.Time
+}
+
+func newDedupCache(clientID string, config *options.EventDeduplication) *dedupCache {
+	if config == nil {
+		return nil
+	}
+
+	ttl := config.TTL
+	if ttl <= 0 {
+		ttl = DefaultDeduplicationTTL
+	}
+
+	maxSize := config.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultDeduplicationMaxSize
+	}
+
+	return &dedupCache{
+		clientID: clientID,
+		ttl:      ttl,
+		maxSize:  maxSize,
+		clock:    clock.RealClock{},
+		keys:     list.New(),
+		elements: map[string]*list.Element{},
+	}
+}
+
+// seen returns true if the event is a duplicate of a remembered event, otherwise the event is remembered.
+func (c *dedupCache) seen(evt cloudevents.Event) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	now := c.clock.Now()
+	c.expire(now)
+
+	keys := dedupKeys(evt)
+	for _, key := range keys {
+		if _, ok := c.elements[key]; ok {
+			increaseDedupCacheHitsCounter(c.clientID)
+			return true
+		}
+	}
+
+	increaseDedupCacheMissesCounter(c.clientID)
+	for _, key := range keys {
+		c.elements[key] = c.keys.PushBack(&dedupEntry{key: key, expiry: now.Add(c.ttl)})
+	}
+
+	for c.keys.Len() > c.maxSize {
+		c.remove(c.keys.Front())
+	}
+
+	return false
+}
+
+func (c *dedupCache) len() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.keys.Len()
+}
+
+// expire removes the keys that are expired at the given time.
+func (c *dedupCache) expire(now time.Time) {
+	for elem := c.keys.Front(); elem != nil; elem = c.keys.Front() {
+		if elem.Value.(*dedupEntry).expiry.After(now) {
+			return
+		}
+		c.remove(elem)
+	}
+}
+
+func (c *dedupCache) remove(elem *list.Element) {
+	c.keys.Remove(elem)
+	delete(c.elements, elem.Value.(*dedupEntry).key)
+}
+
+// dedupKeys returns the keys that identify an event, an event is identified by its source and ID, a status event is
+// also identified by its resource ID and status update sequence ID.
+func dedupKeys(evt cloudevents.Event) []string {
+	keys := []string{fmt.Sprintf("id/%s/%s", evt.Source(), evt.ID())}
+
+	extensions := evt.Extensions()
+	sequenceID, err := cloudeventstypes.ToString(extensions[types.ExtensionStatusUpdateSequenceID])
+	if err != nil || len(sequenceID) == 0 {
+		return keys
+	}
+
+	resourceID, err := cloudeventstypes.ToString(extensions[types.ExtensionResourceID])
+	if err != nil || len(resourceID) == 0 {
+		return keys
+	}
+
+	return append(keys, fmt.Sprintf("sequence/%s/%s", resourceID, sequenceID))
+}
+
+func (c *baseClient) dedupReceived(receive receiveFn) receiveFn {
+	return func(ctx context.Context, evt cloudevents.Event) {
+		if c.dedupCache.seen(evt) {
+			klog.V(4).Infof("drop the duplicated event %s", evt.ID())
+			return
+		}
+
+		receive(ctx, evt)
+	}
+}
@@ -0,0 +1,74 @@
+package generic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func newStatusEvent(id, resourceID, sequenceID string) cloudevents.Event {
+	evt := types.NewEventBuilder(testAgentName, types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "test_update_request",
+	}).WithResourceID(resourceID).WithStatusUpdateSequenceID(sequenceID).WithClusterName("cluster1").NewEvent()
+	evt.SetID(id)
+	return evt
+}
+
+func TestDedupCache(t *testing.T) {
+	ResetCloudEventsMetrics()
+
+	fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+	cache := newDedupCache(testSourceName, &options.EventDeduplication{TTL: time.Minute, MaxSize: 4})
+	cache.clock = fakeClock
+
+	require.False(t, cache.seen(newStatusEvent("e1", "r1", "s1")))
+
+	// the event with the same ID is duplicated
+	require.True(t, cache.seen(newStatusEvent("e1", "r1", "s1")))
+
+	// the status event with the same resource ID and sequence ID is duplicated
+	require.True(t, cache.seen(newStatusEvent("e2", "r1", "s1")))
+
+	// the status event with a new sequence ID is not duplicated
+	require.False(t, cache.seen(newStatusEvent("e3", "r1", "s2")))
+	require.Equal(t, 4, cache.len())
+
+	// the oldest event is forgotten when the cache is full
+	require.False(t, cache.seen(newStatusEvent("e4", "r2", "s1")))
+	require.Equal(t, 4, cache.len())
+	require.False(t, cache.seen(newStatusEvent("e1", "r3", "s1")))
+
+	// the events are forgotten after the TTL
+	fakeClock.SetTime(fakeClock.Now().Add(2 * time.Minute))
+	require.False(t, cache.seen(newStatusEvent("e4", "r2", "s1")))
+	require.Equal(t, 2, cache.len())
+
+	require.Equal(t, 2.0, toFloat64Counter(dedupCacheHitsCounterMetric.WithLabelValues(testSourceName)))
+	require.Equal(t, 5.0, toFloat64Counter(dedupCacheMissesCounterMetric.WithLabelValues(testSourceName)))
+}
+
+func TestDedupReceived(t *testing.T) {
+	client := &baseClient{
+		clientID:   testSourceName,
+		dedupCache: newDedupCache(testSourceName, &options.EventDeduplication{}),
+	}
+
+	received := []string{}
+	receive := client.dedupReceived(func(ctx context.Context, evt cloudevents.Event) {
+		received = append(received, evt.ID())
+	})
+
+	receive(context.TODO(), newStatusEvent("e1", "r1", "s1"))
+	receive(context.TODO(), newStatusEvent("e1", "r1", "s1"))
+	receive(context.TODO(), newStatusEvent("e2", "r1", "s2"))
+	require.Equal(t, []string{"e1", "e2"}, received)
+}
@@ -0,0 +1,108 @@
+package generic
+
+import (
+	"context"
+	"hash/fnv"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	DefaultDispatchWorkers     = 10
+	DefaultDispatchMaxInFlight = 100
+)
+
+// dispatcher processes the received events with a pool of workers. An event is sharded to a worker by its resource
+// ID (or by its cluster name if the resource ID is absent), each worker processes its events in order, so the events
+// of one resource are strictly ordered while the events of different resources are processed concurrently.
+type dispatcher struct {
+	workers []chan dispatchItem
+	// inFlight bounds the number of the events that are being processed or waiting to be processed
+	inFlight chan struct{}
+}
+
+type dispatchItem struct {
+	// ctx is the context of the subscription that receives the event, it's used for its values (e.g. the received
+	// time and the trace span) only, because it's canceled once the client is reconnected while the event may be still
+	// waiting to be processed.
+	ctx   context.Context
+	event cloudevents.Event
+}
+
+func newDispatcher(config *options.EventDispatch) *dispatcher {
+	if config == nil {
+		return nil
+	}
+
+	workers := config.Workers
+	if workers <= 0 {
+		workers = DefaultDispatchWorkers
+	}
+
+	maxInFlight := config.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = DefaultDispatchMaxInFlight
+	}
+
+	d := &dispatcher{
+		workers:  make([]chan dispatchItem, workers),
+		inFlight: make(chan struct{}, maxInFlight),
+	}
+	for i := range d.workers {
+		// the in-flight limit guarantees that sending to a worker is never blocked
+		d.workers[i] = make(chan dispatchItem, maxInFlight)
+	}
+
+	return d
+}
+
+// run starts the workers to process the dispatched events with the receive func until the context is done. The events
+// that are received before a reconnection are still processed after the reconnection, an event is processed with a
+// context that carries the values of its receiving context, the context is canceled once the event is processed or the
+// given context is done.
+func (d *dispatcher) run(ctx context.Context, receive receiveFn) {
+	for _, worker := range d.workers {
+		go func(worker <-chan dispatchItem) {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case item := <-worker:
+					itemCtx, cancel := context.WithCancel(context.WithoutCancel(item.ctx))
+					stop := context.AfterFunc(ctx, cancel)
+					receive(itemCtx, item.event)
+					stop()
+					cancel()
+					<-d.inFlight
+				}
+			}
+		}(worker)
+	}
+}
+
+// dispatch sends the event to its worker, it is blocked until the number of the in-flight events is less than the
+// limit or the context is done.
+func (d *dispatcher) dispatch(ctx context.Context, evt cloudevents.Event) {
+	select {
+	case <-ctx.Done():
+		return
+	case d.inFlight <- struct{}{}:
+	}
+
+	d.workers[d.shard(evt)] <- dispatchItem{ctx: ctx, event: evt}
+}
+
+func (d *dispatcher) shard(evt cloudevents.Event) int {
+	key, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionResourceID])
+	if err != nil {
+		key, _ = cloudeventstypes.ToString(evt.Extensions()[types.ExtensionClusterName])
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(d.workers)))
+}
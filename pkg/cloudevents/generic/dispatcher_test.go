@@ -0,0 +1,132 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestDispatchInOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mutex sync.Mutex
+	received := map[string][]int{}
+	d := newDispatcher(&options.EventDispatch{Workers: 3, MaxInFlight: 5})
+	d.run(ctx, func(ctx context.Context, evt cloudevents.Event) {
+		// make the processing of the early events slower
+		seq := evt.Extensions()[types.ExtensionStatusUpdateSequenceID].(int32)
+		time.Sleep(time.Duration(10-seq%10) * time.Millisecond)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		resourceID := evt.Extensions()[types.ExtensionResourceID].(string)
+		received[resourceID] = append(received[resourceID], int(seq))
+	})
+
+	resources, events := 5, 20
+	for i := 0; i < events; i++ {
+		for j := 0; j < resources; j++ {
+			evt := cloudevents.NewEvent()
+			evt.SetExtension(types.ExtensionResourceID, fmt.Sprintf("resource-%d", j))
+			evt.SetExtension(types.ExtensionStatusUpdateSequenceID, i)
+			d.dispatch(ctx, evt)
+		}
+	}
+
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, seqs := range received {
+			if len(seqs) != events {
+				return false
+			}
+		}
+		return len(received) == resources
+	}, 10*time.Second, 10*time.Millisecond)
+
+	for resourceID, seqs := range received {
+		for i, seq := range seqs {
+			require.Equal(t, i, seq, "the events of %s are out of order", resourceID)
+		}
+	}
+}
+
+func TestDispatchBackpressure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	d := newDispatcher(&options.EventDispatch{Workers: 2, MaxInFlight: 1})
+	d.run(ctx, func(ctx context.Context, evt cloudevents.Event) {
+		<-release
+	})
+
+	d.dispatch(ctx, cloudevents.NewEvent())
+
+	dispatched := make(chan struct{})
+	go func() {
+		d.dispatch(ctx, cloudevents.NewEvent())
+		close(dispatched)
+	}()
+
+	select {
+	case <-dispatched:
+		t.Fatalf("expected the dispatch to be blocked")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case <-dispatched:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the dispatch to be unblocked")
+	}
+}
+
+func TestDispatchAfterReceiverContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type ctxKey struct{}
+	processed := make(chan error, 2)
+	block := make(chan struct{})
+	d := newDispatcher(&options.EventDispatch{Workers: 1, MaxInFlight: 5})
+	d.run(ctx, func(ctx context.Context, evt cloudevents.Event) {
+		<-block
+		if ctx.Value(ctxKey{}) != evt.ID() {
+			processed <- fmt.Errorf("the values of the receiving context are lost")
+			return
+		}
+		processed <- ctx.Err()
+	})
+
+	// the events are queued with the receiver context, then the receiver context is canceled by a reconnection
+	receiverCtx, receiverCancel := context.WithCancel(context.Background())
+	for _, id := range []string{"1", "2"} {
+		evt := cloudevents.NewEvent()
+		evt.SetID(id)
+		d.dispatch(context.WithValue(receiverCtx, ctxKey{}, id), evt)
+	}
+	receiverCancel()
+	close(block)
+
+	// the queued events are still processed with a live context
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-processed:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the queued events are not processed")
+		}
+	}
+}
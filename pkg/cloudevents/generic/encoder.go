@@ -0,0 +1,137 @@
+package generic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/encoding"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// peerFunc returns the peer of an event, the peer of a source is a cluster and the peer of an agent is a source.
+type peerFunc func(evt cloudevents.Event) string
+
+// eventEncoder encodes the data of the events that are sent to a peer with the content type that is negotiated with
+// the peer. The content types that are accepted by a peer are learned from the resync requests of the peer.
+type eventEncoder struct {
+	sync.RWMutex
+
+	config *options.EventEncoding
+	// accepted is keyed by the peer and the cloud events data type
+	accepted    map[string]map[string]bool
+	sendPeer    peerFunc
+	receivePeer peerFunc
+}
+
+func newEventEncoder(config *options.EventEncoding, sendPeer, receivePeer peerFunc) *eventEncoder {
+	if config == nil {
+		return nil
+	}
+
+	return &eventEncoder{
+		config:      config,
+		accepted:    map[string]map[string]bool{},
+		sendPeer:    sendPeer,
+		receivePeer: receivePeer,
+	}
+}
+
+// encode advertises the accepted content types in the resync requests and encodes the event data with the first
+// preferred content type that is accepted by the peer of the event.
+func (e *eventEncoder) encode(evt cloudevents.Event) (cloudevents.Event, error) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return evt, err
+	}
+
+	if isResyncRequest(eventType.Action) {
+		evt = evt.Clone()
+		evt.SetExtension(types.ExtensionAcceptEncodings, strings.Join(acceptedContentTypes(), ","))
+	}
+
+	e.RLock()
+	accepted := e.accepted[encoderKey(e.sendPeer(evt), eventType.CloudEventsDataType)]
+	e.RUnlock()
+
+	for _, contentType := range e.preferredContentTypes(eventType.CloudEventsDataType) {
+		if contentType == cloudevents.ApplicationJSON {
+			return evt, nil
+		}
+
+		if accepted[contentType] {
+			return encoding.EncodeEvent(evt, contentType)
+		}
+	}
+
+	return evt, nil
+}
+
+// learn records the content types that are accepted by the peer of a received resync request. If the resync request
+// does not advertise the accepted content types, e.g. the peer is rolled back to a version without encoding support,
+// the content types that were learned from the peer are forgotten, so the event data is sent to the peer as JSON.
+func (e *eventEncoder) learn(evt cloudevents.Event) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil || !isResyncRequest(eventType.Action) {
+		return
+	}
+
+	key := encoderKey(e.receivePeer(evt), eventType.CloudEventsDataType)
+
+	contentTypes, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionAcceptEncodings])
+	if err != nil {
+		e.Lock()
+		defer e.Unlock()
+		delete(e.accepted, key)
+		return
+	}
+
+	accepted := map[string]bool{}
+	for _, contentType := range strings.Split(contentTypes, ",") {
+		accepted[strings.TrimSpace(contentType)] = true
+	}
+
+	e.Lock()
+	defer e.Unlock()
+	e.accepted[key] = accepted
+}
+
+func (e *eventEncoder) preferredContentTypes(dataType types.CloudEventsDataType) []string {
+	if contentTypes, ok := e.config.DataTypeContentTypes[dataType.String()]; ok {
+		return contentTypes
+	}
+	return e.config.ContentTypes
+}
+
+func encoderKey(peer string, dataType types.CloudEventsDataType) string {
+	return fmt.Sprintf("%s/%s", peer, dataType)
+}
+
+func acceptedContentTypes() []string {
+	contentTypes := append(encoding.ContentTypes(), cloudevents.ApplicationJSON)
+	sort.Strings(contentTypes)
+	return contentTypes
+}
+
+func isResyncRequest(action types.EventAction) bool {
+	return action == types.ResyncRequestAction || action == types.MerkleResyncRequestAction
+}
+
+func extensionPeer(extension string) peerFunc {
+	return func(evt cloudevents.Event) string {
+		peer, err := cloudeventstypes.ToString(evt.Extensions()[extension])
+		if err != nil {
+			return ""
+		}
+		return peer
+	}
+}
+
+func sourcePeer(evt cloudevents.Event) string {
+	return evt.Source()
+}
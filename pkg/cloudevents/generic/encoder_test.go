@@ -0,0 +1,67 @@
+package generic
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/encoding"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestEventEncoder(t *testing.T) {
+	otherDataType := types.CloudEventsDataType{Group: "test", Version: "v1", Resource: "others"}
+	encoder := newEventEncoder(&options.EventEncoding{
+		ContentTypes:         []string{encoding.ContentTypeZstdJSON, encoding.ContentTypeGzipJSON},
+		DataTypeContentTypes: map[string][]string{otherDataType.String(): {cloudevents.ApplicationJSON}},
+	}, extensionPeer(types.ExtensionClusterName), extensionPeer(types.ExtensionClusterName))
+
+	newEvent := func(dataType types.CloudEventsDataType, action types.EventAction) cloudevents.Event {
+		eventType := types.CloudEventsType{CloudEventsDataType: dataType, SubResource: types.SubResourceSpec, Action: action}
+		evt := types.NewEventBuilder(testSourceName, eventType).WithClusterName("cluster1").NewEvent()
+		require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, map[string]string{"test": "test"}))
+		return evt
+	}
+
+	// the accepted content types of the peer are unknown
+	evt, err := encoder.encode(newEvent(mockEventDataType, "create_request"))
+	require.NoError(t, err)
+	require.Equal(t, cloudevents.ApplicationJSON, evt.DataContentType())
+
+	// the resync request advertises the accepted content types
+	evt, err = encoder.encode(newEvent(mockEventDataType, types.ResyncRequestAction))
+	require.NoError(t, err)
+	require.Contains(t, evt.Extensions()[types.ExtensionAcceptEncodings], encoding.ContentTypeZstdJSON)
+
+	// learn the accepted content types from the resync request of the peer
+	request := newEvent(mockEventDataType, types.ResyncRequestAction)
+	request.SetExtension(types.ExtensionAcceptEncodings, "application/json,application/json+gzip")
+	encoder.learn(request)
+
+	evt, err = encoder.encode(newEvent(mockEventDataType, "create_request"))
+	require.NoError(t, err)
+	require.Equal(t, encoding.ContentTypeGzipJSON, evt.DataContentType())
+
+	// the received events that are not resync requests do not change the accepted content types
+	encoder.learn(newEvent(mockEventDataType, "create_request"))
+	evt, err = encoder.encode(newEvent(mockEventDataType, "create_request"))
+	require.NoError(t, err)
+	require.Equal(t, encoding.ContentTypeGzipJSON, evt.DataContentType())
+
+	// the peer is downgraded to a version without encoding support, the accepted content types are forgotten
+	encoder.learn(newEvent(mockEventDataType, types.ResyncRequestAction))
+	evt, err = encoder.encode(newEvent(mockEventDataType, "create_request"))
+	require.NoError(t, err)
+	require.Equal(t, cloudevents.ApplicationJSON, evt.DataContentType())
+
+	// the content types of the data type are overridden
+	request = newEvent(otherDataType, types.ResyncRequestAction)
+	request.SetExtension(types.ExtensionAcceptEncodings, "application/json,application/json+gzip")
+	encoder.learn(request)
+
+	evt, err = encoder.encode(newEvent(otherDataType, "create_request"))
+	require.NoError(t, err)
+	require.Equal(t, cloudevents.ApplicationJSON, evt.DataContentType())
+}
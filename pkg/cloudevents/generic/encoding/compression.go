@@ -0,0 +1,76 @@
+package encoding
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// MaxDecodedSize is the maximum size (64MiB) of the decompressed event data. The received events are always decoded,
+// so the limit protects the receivers from the small compressed data that expands to a huge size.
+const MaxDecodedSize = 64 * 1024 * 1024
+
+type gzipEncoding struct{}
+
+func (e *gzipEncoding) ContentType() string {
+	return ContentTypeGzipJSON
+}
+
+func (e *gzipEncoding) Encode(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (e *gzipEncoding) Decode(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// read one more byte to find out the data that exceeds the limit
+	decoded, err := io.ReadAll(io.LimitReader(reader, MaxDecodedSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decoded) > MaxDecodedSize {
+		return nil, fmt.Errorf("the decompressed data exceeds the limit %d bytes", MaxDecodedSize)
+	}
+
+	return decoded, nil
+}
+
+// the zstd encoder and decoder are safe for concurrent use with EncodeAll and DecodeAll
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(MaxDecodedSize))
+)
+
+type zstdEncoding struct{}
+
+func (e *zstdEncoding) ContentType() string {
+	return ContentTypeZstdJSON
+}
+
+func (e *zstdEncoding) Encode(data []byte) ([]byte, error) {
+	return zstdEncoder.EncodeAll(data, nil), nil
+}
+
+func (e *zstdEncoding) Decode(data []byte) ([]byte, error) {
+	decoded, err := zstdDecoder.DecodeAll(data, nil)
+	if err == zstd.ErrDecoderSizeExceeded {
+		return nil, fmt.Errorf("the decompressed data exceeds the limit %d bytes", MaxDecodedSize)
+	}
+	return decoded, err
+}
@@ -0,0 +1,8 @@
+// Package encoding provides the encodings of the cloud event data, the JSON event data is compressed with gzip or zstd
+// to reduce the bandwidth.
+//
+// A protobuf encoding is not provided. The event data of the payload types embeds arbitrary manifests, so a generic
+// protobuf representation of the JSON is larger than the JSON itself, and the compression reduces the size much more.
+// The other encodings, e.g. a protobuf encoding with the generated messages of a payload type, can be added with
+// Register.
+package encoding
@@ -0,0 +1,120 @@
+package encoding
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+const (
+	// ContentTypeGzipJSON represents the event data is gzip compressed JSON.
+	ContentTypeGzipJSON = "application/json+gzip"
+
+	// ContentTypeZstdJSON represents the event data is zstd compressed JSON.
+	ContentTypeZstdJSON = "application/json+zstd"
+)
+
+// Encoding encodes the JSON data of a cloud event to another format and decodes it back. The encoded format is
+// identified by the datacontenttype of the cloud event.
+type Encoding interface {
+	// ContentType returns the datacontenttype of the encoded event data.
+	ContentType() string
+
+	// Encode encodes the JSON event data.
+	Encode(data []byte) ([]byte, error)
+
+	// Decode decodes the encoded event data to JSON.
+	Decode(data []byte) ([]byte, error)
+}
+
+var (
+	lock      sync.RWMutex
+	encodings = map[string]Encoding{}
+)
+
+func init() {
+	Register(&gzipEncoding{})
+	Register(&zstdEncoding{})
+}
+
+// Register registers an encoding, the encoding with the same content type will be replaced.
+func Register(encoding Encoding) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	encodings[encoding.ContentType()] = encoding
+}
+
+// Get returns the encoding of the given content type.
+func Get(contentType string) (Encoding, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	encoding, ok := encodings[mediaType(contentType)]
+	return encoding, ok
+}
+
+// ContentTypes returns the content types of the registered encodings.
+func ContentTypes() []string {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	contentTypes := []string{}
+	for contentType := range encodings {
+		contentTypes = append(contentTypes, contentType)
+	}
+	return contentTypes
+}
+
+// EncodeEvent encodes the JSON data of an event with the encoding of the given content type. The event is not changed
+// if its data is not JSON.
+func EncodeEvent(evt cloudevents.Event, contentType string) (cloudevents.Event, error) {
+	if mediaType(evt.DataContentType()) != cloudevents.ApplicationJSON || len(evt.Data()) == 0 {
+		return evt, nil
+	}
+
+	encoding, ok := Get(contentType)
+	if !ok {
+		return evt, fmt.Errorf("unsupported encoding %s", contentType)
+	}
+
+	data, err := encoding.Encode(evt.Data())
+	if err != nil {
+		return evt, fmt.Errorf("failed to encode the event data with %s, %v", contentType, err)
+	}
+
+	encoded := evt.Clone()
+	if err := encoded.SetData(encoding.ContentType(), data); err != nil {
+		return evt, err
+	}
+	return encoded, nil
+}
+
+// DecodeEvent decodes the data of an event to JSON if the event data is encoded by a registered encoding, otherwise
+// the event is returned directly.
+func DecodeEvent(evt cloudevents.Event) (cloudevents.Event, error) {
+	encoding, ok := Get(evt.DataContentType())
+	if !ok {
+		return evt, nil
+	}
+
+	data, err := encoding.Decode(evt.Data())
+	if err != nil {
+		return evt, fmt.Errorf("failed to decode the event data with %s, %v", encoding.ContentType(), err)
+	}
+
+	decoded := evt.Clone()
+	decoded.SetDataContentType(cloudevents.ApplicationJSON)
+	decoded.DataEncoded = data
+	decoded.DataBase64 = false
+	return decoded, nil
+}
+
+func mediaType(contentType string) string {
+	if i := strings.IndexRune(contentType, ';'); i != -1 {
+		contentType = contentType[:i]
+	}
+	return strings.TrimSpace(strings.ToLower(contentType))
+}
@@ -0,0 +1,91 @@
+package encoding
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeAndDecodeEvent(t *testing.T) {
+	data := map[string]any{
+		"metadata": map[string]any{"name": "test", "generation": float64(2)},
+		"spec":     map[string]any{"workload": []any{"a", "b"}},
+	}
+
+	for _, contentType := range []string{ContentTypeGzipJSON, ContentTypeZstdJSON} {
+		t.Run(contentType, func(t *testing.T) {
+			evt := cloudevents.NewEvent()
+			evt.SetID("test")
+			require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, data))
+
+			encoded, err := EncodeEvent(evt, contentType)
+			require.NoError(t, err)
+			require.Equal(t, contentType, encoded.DataContentType())
+			require.NotEqual(t, evt.Data(), encoded.Data())
+			// the original event is not changed
+			require.Equal(t, cloudevents.ApplicationJSON, evt.DataContentType())
+
+			decoded, err := DecodeEvent(encoded)
+			require.NoError(t, err)
+			require.Equal(t, cloudevents.ApplicationJSON, decoded.DataContentType())
+
+			decodedData := map[string]any{}
+			require.NoError(t, decoded.DataAs(&decodedData))
+			require.Equal(t, data, decodedData)
+		})
+	}
+}
+
+func TestUnsupportedEncoding(t *testing.T) {
+	evt := cloudevents.NewEvent()
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, map[string]string{"a": "b"}))
+
+	_, err := EncodeEvent(evt, "application/unknown")
+	require.Error(t, err)
+
+	// the event data that is not JSON is not encoded
+	xmlEvt := cloudevents.NewEvent()
+	require.NoError(t, xmlEvt.SetData(cloudevents.ApplicationXML, []byte("<a/>")))
+	encoded, err := EncodeEvent(xmlEvt, ContentTypeGzipJSON)
+	require.NoError(t, err)
+	require.Equal(t, cloudevents.ApplicationXML, encoded.DataContentType())
+
+	// the event data with an unknown content type is not decoded
+	decoded, err := DecodeEvent(xmlEvt)
+	require.NoError(t, err)
+	require.Equal(t, xmlEvt.Data(), decoded.Data())
+
+	// the corrupted event data cannot be decoded
+	corrupted := cloudevents.NewEvent()
+	require.NoError(t, corrupted.SetData(ContentTypeGzipJSON, []byte("corrupted")))
+	_, err = DecodeEvent(corrupted)
+	require.Error(t, err)
+}
+
+func TestDecompressionLimit(t *testing.T) {
+	// the zeros are highly compressed, the compressed data is much smaller than the limit
+	data := make([]byte, MaxDecodedSize+1)
+
+	for _, encoding := range []Encoding{&gzipEncoding{}, &zstdEncoding{}} {
+		t.Run(encoding.ContentType(), func(t *testing.T) {
+			encoded, err := encoding.Encode(data)
+			require.NoError(t, err)
+			require.Less(t, len(encoded), MaxDecodedSize/100)
+
+			_, err = encoding.Decode(encoded)
+			require.ErrorContains(t, err, "exceeds the limit")
+
+			// the data within the limit is decoded
+			decoded, err := encoding.Decode(mustEncode(t, encoding, data[:MaxDecodedSize]))
+			require.NoError(t, err)
+			require.Len(t, decoded, MaxDecodedSize)
+		})
+	}
+}
+
+func mustEncode(t *testing.T, encoding Encoding, data []byte) []byte {
+	encoded, err := encoding.Encode(data)
+	require.NoError(t, err)
+	return encoded
+}
@@ -0,0 +1,292 @@
+package encryption
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/signing"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	// ContentTypeEncrypted represents the event data is encrypted, the content type of the plain data is saved to
+	// the plaincontenttype extension.
+	ContentTypeEncrypted = "application/encrypted"
+
+	// AlgorithmRSAOAEP256 wraps the data key with RSA-OAEP using SHA-256.
+	AlgorithmRSAOAEP256 = "RSA-OAEP-256"
+
+	// AlgorithmECDHES wraps the data key with AES-256-GCM using a key that is derived from an ephemeral ECDH key
+	// agreement, the P-256, P-384, P-521 and X25519 curves are supported.
+	AlgorithmECDHES = "ECDH-ES+A256GCM"
+)
+
+const dataKeySize = 32
+
+// EnvelopeEncryptor encrypts the event data with a random AES-256-GCM data key, and wraps the data key with the public
+// key of the recipient. The event ID and the plain content type are authenticated with the event data, the other
+// attributes and extensions are kept in clear, so the brokers can still route the events.
+type EnvelopeEncryptor struct {
+	keys signing.KeyStore
+}
+
+var _ options.EventEncryptor = &EnvelopeEncryptor{}
+
+// NewEnvelopeEncryptor returns an EnvelopeEncryptor, the public keys of the recipients are got from the key store,
+// the RSA, ECDSA and X25519 keys are supported.
+func NewEnvelopeEncryptor(keys signing.KeyStore) *EnvelopeEncryptor {
+	return &EnvelopeEncryptor{keys: keys}
+}
+
+// Encrypt encrypts the event data for the recipient. The resync requests and the heartbeats are not encrypted, they
+// only have the versions or hashes of the resources or the agent information, and may be broadcast to all of the
+// recipients.
+func (e *EnvelopeEncryptor) Encrypt(recipient string, evt *cloudevents.Event) error {
+	if len(evt.Data()) == 0 {
+		return nil
+	}
+
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return err
+	}
+	switch eventType.Action {
+	case types.ResyncRequestAction, types.MerkleResyncRequestAction, types.HeartbeatAction:
+		return nil
+	}
+
+	key, err := e.keys.PublicKey(recipient)
+	if err != nil {
+		return fmt.Errorf("failed to get the public key of %s, %v", recipient, err)
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return err
+	}
+
+	encryptedKey, err := wrapKey(key, dataKey)
+	if err != nil {
+		return fmt.Errorf("failed to wrap the data key for %s, %v", recipient, err)
+	}
+
+	plainContentType := evt.DataContentType()
+	data, err := seal(dataKey, evt.Data(), additionalData(evt.ID(), plainContentType))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the event %s, %v", evt.ID(), err)
+	}
+
+	if err := evt.SetData(ContentTypeEncrypted, data); err != nil {
+		return err
+	}
+	evt.SetExtension(types.ExtensionEncryptedKey, encryptedKey)
+	evt.SetExtension(types.ExtensionPlainContentType, plainContentType)
+	return nil
+}
+
+// EnvelopeDecryptor decrypts the event data that is encrypted by an EnvelopeEncryptor with the private key of the
+// recipient.
+type EnvelopeDecryptor struct {
+	key crypto.PrivateKey
+}
+
+var _ options.EventDecryptor = &EnvelopeDecryptor{}
+
+// NewEnvelopeDecryptor returns an EnvelopeDecryptor with the private key of the recipient, the RSA, ECDSA and X25519
+// keys are supported.
+func NewEnvelopeDecryptor(key crypto.PrivateKey) *EnvelopeDecryptor {
+	return &EnvelopeDecryptor{key: key}
+}
+
+// Decrypt decrypts the event data and restores the plain content type of the event.
+func (d *EnvelopeDecryptor) Decrypt(evt *cloudevents.Event) error {
+	extensions := evt.Extensions()
+	value, ok := extensions[types.ExtensionEncryptedKey]
+	if !ok {
+		return nil
+	}
+
+	encryptedKey, err := cloudeventstypes.ToString(value)
+	if err != nil {
+		return fmt.Errorf("failed to get the encrypted key of the event %s, %v", evt.ID(), err)
+	}
+
+	plainContentType, err := cloudeventstypes.ToString(extensions[types.ExtensionPlainContentType])
+	if err != nil {
+		return fmt.Errorf("failed to get the plain content type of the event %s, %v", evt.ID(), err)
+	}
+
+	dataKey, err := unwrapKey(d.key, encryptedKey)
+	if err != nil {
+		return fmt.Errorf("failed to unwrap the data key of the event %s, %v", evt.ID(), err)
+	}
+
+	data, err := open(dataKey, evt.Data(), additionalData(evt.ID(), plainContentType))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt the event %s, %v", evt.ID(), err)
+	}
+
+	evt.SetDataContentType(plainContentType)
+	evt.DataEncoded = data
+	evt.DataBase64 = !strings.HasPrefix(plainContentType, cloudevents.ApplicationJSON)
+	evt.SetExtension(types.ExtensionEncryptedKey, nil)
+	evt.SetExtension(types.ExtensionPlainContentType, nil)
+	return nil
+}
+
+// wrapKey wraps the data key with the public key, the wrapped key is formatted as <alg>.<ephemeral key>.<key>, the
+// ephemeral key is empty for RSA.
+func wrapKey(key crypto.PublicKey, dataKey []byte) (string, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, k, dataKey, nil)
+		if err != nil {
+			return "", err
+		}
+		return formatKey(AlgorithmRSAOAEP256, nil, wrapped), nil
+	case *ecdsa.PublicKey:
+		ecdhKey, err := k.ECDH()
+		if err != nil {
+			return "", err
+		}
+		return wrapKeyWithECDH(ecdhKey, dataKey)
+	case *ecdh.PublicKey:
+		return wrapKeyWithECDH(k, dataKey)
+	}
+	return "", fmt.Errorf("unsupported key type %T", key)
+}
+
+func wrapKeyWithECDH(key *ecdh.PublicKey, dataKey []byte) (string, error) {
+	ephemeralKey, err := key.Curve().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := ephemeralKey.ECDH(key)
+	if err != nil {
+		return "", err
+	}
+
+	epk := ephemeralKey.PublicKey().Bytes()
+	wrapped, err := seal(deriveKey(secret, epk), dataKey, []byte(AlgorithmECDHES))
+	if err != nil {
+		return "", err
+	}
+	return formatKey(AlgorithmECDHES, epk, wrapped), nil
+}
+
+func unwrapKey(key crypto.PrivateKey, encryptedKey string) ([]byte, error) {
+	parts := strings.Split(encryptedKey, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid encrypted key")
+	}
+
+	epk, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+
+	switch alg := parts[0]; alg {
+	case AlgorithmRSAOAEP256:
+		k, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("the algorithm %s is mismatched with the key type %T", alg, key)
+		}
+		return rsa.DecryptOAEP(sha256.New(), rand.Reader, k, wrapped, nil)
+	case AlgorithmECDHES:
+		var ecdhKey *ecdh.PrivateKey
+		switch k := key.(type) {
+		case *ecdsa.PrivateKey:
+			if ecdhKey, err = k.ECDH(); err != nil {
+				return nil, err
+			}
+		case *ecdh.PrivateKey:
+			ecdhKey = k
+		default:
+			return nil, fmt.Errorf("the algorithm %s is mismatched with the key type %T", alg, key)
+		}
+
+		ephemeralKey, err := ecdhKey.Curve().NewPublicKey(epk)
+		if err != nil {
+			return nil, err
+		}
+		secret, err := ecdhKey.ECDH(ephemeralKey)
+		if err != nil {
+			return nil, err
+		}
+		return open(deriveKey(secret, epk), wrapped, []byte(AlgorithmECDHES))
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %s", alg)
+	}
+}
+
+func formatKey(alg string, epk, wrapped []byte) string {
+	return fmt.Sprintf("%s.%s.%s", alg,
+		base64.RawURLEncoding.EncodeToString(epk), base64.RawURLEncoding.EncodeToString(wrapped))
+}
+
+// deriveKey derives a 256-bit key from the ECDH shared secret with a single round of the concat KDF (NIST SP 800-56A).
+func deriveKey(secret, epk []byte) []byte {
+	h := sha256.New()
+	_ = binary.Write(h, binary.BigEndian, uint32(1))
+	h.Write(secret)
+	h.Write([]byte(AlgorithmECDHES))
+	h.Write(epk)
+	return h.Sum(nil)
+}
+
+func additionalData(id, plainContentType string) []byte {
+	return []byte(id + "\n" + plainContentType)
+}
+
+// seal encrypts the data with AES-GCM, the random nonce is prepended to the ciphertext.
+func seal(key, data, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, additionalData), nil
+}
+
+func open(key, data, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("the encrypted data is too short")
+	}
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], additionalData)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
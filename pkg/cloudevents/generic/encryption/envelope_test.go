@@ -0,0 +1,165 @@
+package encryption
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/signing"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func newTestEvent(t *testing.T, action types.EventAction) cloudevents.Event {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: types.CloudEventsDataType{Group: "test", Version: "v1", Resource: "tests"},
+		SubResource:         types.SubResourceSpec,
+		Action:              action,
+	}
+	evt := types.NewEventBuilder("source1", eventType).
+		WithClusterName("cluster1").
+		WithResourceID("test").
+		WithResourceVersion(1).
+		NewEvent()
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, map[string]string{"secret": "test"}))
+	return evt
+}
+
+func TestEncryptAndDecrypt(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	x25519Key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name          string
+		publicKey     crypto.PublicKey
+		privateKey    crypto.PrivateKey
+		mutate        func(evt *cloudevents.Event)
+		expectedError bool
+	}{
+		{
+			name:       "rsa",
+			publicKey:  rsaKey.Public(),
+			privateKey: rsaKey,
+		},
+		{
+			name:       "ecdsa",
+			publicKey:  ecdsaKey.Public(),
+			privateKey: ecdsaKey,
+		},
+		{
+			name:       "x25519",
+			publicKey:  x25519Key.Public(),
+			privateKey: x25519Key,
+		},
+		{
+			name:          "wrong key",
+			publicKey:     ecdsaKey.Public(),
+			privateKey:    otherKey,
+			expectedError: true,
+		},
+		{
+			name:          "mismatched key type",
+			publicKey:     ecdsaKey.Public(),
+			privateKey:    rsaKey,
+			expectedError: true,
+		},
+		{
+			name:       "tampered data",
+			publicKey:  ecdsaKey.Public(),
+			privateKey: ecdsaKey,
+			mutate: func(evt *cloudevents.Event) {
+				evt.DataEncoded[len(evt.DataEncoded)-1] ^= 0xff
+			},
+			expectedError: true,
+		},
+		{
+			name:       "moved data",
+			publicKey:  ecdsaKey.Public(),
+			privateKey: ecdsaKey,
+			mutate: func(evt *cloudevents.Event) {
+				evt.SetID("another")
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			encryptor := NewEnvelopeEncryptor(signing.StaticKeyStore{"cluster1": c.publicKey})
+
+			plain := newTestEvent(t, "create_request")
+			evt := plain.Clone()
+			require.NoError(t, encryptor.Encrypt("cluster1", &evt))
+			require.Equal(t, ContentTypeEncrypted, evt.DataContentType())
+			require.NotContains(t, string(evt.Data()), "secret")
+
+			// the routing extensions are kept in clear
+			require.Equal(t, "cluster1", evt.Extensions()[types.ExtensionClusterName])
+			require.Equal(t, "test", evt.Extensions()[types.ExtensionResourceID])
+
+			if c.mutate != nil {
+				c.mutate(&evt)
+			}
+
+			err := NewEnvelopeDecryptor(c.privateKey).Decrypt(&evt)
+			if c.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, cloudevents.ApplicationJSON, evt.DataContentType())
+			require.Equal(t, plain.Data(), evt.Data())
+			require.NotContains(t, evt.Extensions(), types.ExtensionEncryptedKey)
+			require.NotContains(t, evt.Extensions(), types.ExtensionPlainContentType)
+		})
+	}
+}
+
+func TestEncryptSkipped(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	encryptor := NewEnvelopeEncryptor(signing.StaticKeyStore{"cluster1": key.Public()})
+
+	// the resync requests are not encrypted
+	evt := newTestEvent(t, types.ResyncRequestAction)
+	require.NoError(t, encryptor.Encrypt("cluster1", &evt))
+	require.Equal(t, cloudevents.ApplicationJSON, evt.DataContentType())
+
+	// the unencrypted events are not changed by the decryptor
+	require.NoError(t, NewEnvelopeDecryptor(key).Decrypt(&evt))
+	require.Equal(t, cloudevents.ApplicationJSON, evt.DataContentType())
+
+	// there is no key for the recipient
+	evt = newTestEvent(t, "create_request")
+	require.Error(t, encryptor.Encrypt("cluster2", &evt))
+}
+
+func TestLoadPrivateKeyFile(t *testing.T) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	data, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "tls.key")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: data}), 0600))
+
+	loaded, err := LoadPrivateKeyFile(path)
+	require.NoError(t, err)
+	require.True(t, key.Equal(loaded))
+}
@@ -0,0 +1,41 @@
+package encryption
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+// LoadPrivateKeyFile loads a PEM encoded PKCS8, PKCS1 or EC private key of a recipient from a file. Unlike the signing
+// keys, the X25519 keys are supported.
+func LoadPrivateKeyFile(path string) (crypto.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the private key file %s, %v", path, err)
+	}
+	return key, nil
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS8, PKCS1 or EC private key.
+func ParsePrivateKey(data []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data is found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+}
@@ -0,0 +1,309 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.opentelemetry.io/otel/trace"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	DefaultHandlerMaxRetries     = 5
+	DefaultHandlerRetryBaseDelay = 100 * time.Millisecond
+	DefaultHandlerRetryMaxDelay  = 1 * time.Minute
+)
+
+// handlerFailureFunc is called when a received event fails to be handled finally.
+type handlerFailureFunc func(ctx context.Context, evt cloudevents.Event, err error)
+
+// handlerRunner invokes the resource handlers with the received resources. If the retry is enabled, the failed
+// handlers are retried with a per-resource-ID rate limited queue, otherwise the failure is reported immediately.
+// The invocations of a resource are serialized, so a retry never runs concurrently with a newer event of the same
+// resource.
+type handlerRunner[T ResourceObject] struct {
+	sync.Mutex
+
+	// queue is nil if the retry is disabled
+	queue      workqueue.RateLimitingInterface
+	items      map[string]*handlerRetryItem[T]
+	keyLocks   *keyLocks
+	maxRetries int
+	onFailure  handlerFailureFunc
+	tracer     trace.Tracer
+	clientID   string
+}
+
+type handlerRetryItem[T ResourceObject] struct {
+	evt      cloudevents.Event
+	action   types.ResourceAction
+	obj      T
+	handlers []ResourceHandler[T]
+}
+
+func newHandlerRunner[T ResourceObject](client *baseClient, config *options.HandlerRetry) *handlerRunner[T] {
+	// the event that fails to be handled is sent to the dead letter sink by default
+	onFailure := func(ctx context.Context, evt cloudevents.Event, err error) {
+		client.deadLetter(ctx, evt, options.DeadLetterStageHandle, err)
+	}
+
+	if config == nil {
+		return &handlerRunner[T]{onFailure: onFailure, tracer: client.tracer, clientID: client.clientID}
+	}
+
+	maxRetries := config.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = DefaultHandlerMaxRetries
+	}
+
+	baseDelay := config.BaseDelay
+	if baseDelay <= 0 {
+		baseDelay = DefaultHandlerRetryBaseDelay
+	}
+
+	maxDelay := config.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultHandlerRetryMaxDelay
+	}
+
+	if config.OnFailure != nil {
+		onFailure = func(_ context.Context, evt cloudevents.Event, err error) {
+			config.OnFailure(evt, err)
+		}
+	}
+
+	return &handlerRunner[T]{
+		queue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+			fmt.Sprintf("%s-handler-retry", client.clientID),
+		),
+		items:      map[string]*handlerRetryItem[T]{},
+		keyLocks:   newKeyLocks(),
+		maxRetries: maxRetries,
+		onFailure:  onFailure,
+		tracer:     client.tracer,
+		clientID:   client.clientID,
+	}
+}
+
+// run processes the failed resources until the context is done.
+func (r *handlerRunner[T]) run(ctx context.Context) {
+	if r.queue == nil {
+		return
+	}
+	defer r.queue.ShutDown()
+
+	// the .Until will re-kick the runWorker one second after the runWorker completes
+	go wait.UntilWithContext(ctx, r.runWorker, time.Second)
+
+	// wait until we're told to stop
+	<-ctx.Done()
+}
+
+// handle invokes the handlers with the received resource. The failed handlers are retried with backoff if the retry
+// is enabled, otherwise the failure is reported immediately.
+func (r *handlerRunner[T]) handle(
+	ctx context.Context, evt cloudevents.Event, action types.ResourceAction, obj T, handlers ...ResourceHandler[T]) {
+	key := string(obj.GetUID())
+
+	// wait for the running retry of the same resource
+	unlock := r.lockKey(key)
+	defer unlock()
+
+	// the newer event supersedes the pending retry of the same resource
+	r.forget(key)
+
+	if receivedTime, ok := receivedTimeFrom(ctx); ok {
+		observeReceiveToHandleDuration(r.clientID, evt, receivedTime)
+	}
+
+	failed, err := r.invoke(ctx, evt, action, obj, handlers...)
+	if err == nil {
+		return
+	}
+
+	klog.Errorf("failed to handle event %s, %v", evt, err)
+	if r.queue == nil {
+		r.fail(ctx, evt, err)
+		return
+	}
+
+	increaseHandlerFailuresCounter(r.clientID, evt, handlerFailureReasonRetrying)
+
+	r.Lock()
+	r.items[key] = &handlerRetryItem[T]{evt: evt, action: action, obj: obj, handlers: failed}
+	r.Unlock()
+	r.queue.AddRateLimited(key)
+}
+
+func (r *handlerRunner[T]) forget(key string) {
+	if r.queue == nil {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.items[key]; ok {
+		delete(r.items, key)
+		r.queue.Forget(key)
+	}
+}
+
+func (r *handlerRunner[T]) runWorker(ctx context.Context) {
+	for r.processNextItem(ctx) {
+	}
+}
+
+func (r *handlerRunner[T]) processNextItem(ctx context.Context) bool {
+	key, quit := r.queue.Get()
+	if quit {
+		return false
+	}
+	defer r.queue.Done(key)
+
+	// the item is looked up with the key lock, so it's not superseded by a newer event while it's being retried
+	unlock := r.lockKey(key.(string))
+	defer unlock()
+
+	r.Lock()
+	item, ok := r.items[key.(string)]
+	r.Unlock()
+	if !ok {
+		// the item is superseded by a newer event
+		r.queue.Forget(key)
+		return true
+	}
+
+	// the retry continues the trace of the event
+	failed, err := r.invoke(ctx, item.evt, item.action, item.obj, item.handlers...)
+	if err == nil {
+		r.remove(key.(string), item)
+		r.queue.Forget(key)
+		return true
+	}
+
+	if r.queue.NumRequeues(key) < r.maxRetries {
+		klog.V(4).Infof("failed to handle event %s, retry it, %v", item.evt.ID(), err)
+		increaseHandlerFailuresCounter(r.clientID, item.evt, handlerFailureReasonRetrying)
+		r.Lock()
+		item.handlers = failed
+		r.Unlock()
+		r.queue.AddRateLimited(key)
+		return true
+	}
+
+	klog.Errorf("failed to handle event %s after %d retries, %v", item.evt, r.maxRetries, err)
+	r.remove(key.(string), item)
+	r.queue.Forget(key)
+	r.fail(ctx, item.evt, err)
+	return true
+}
+
+// fail reports the event that fails to be handled finally.
+func (r *handlerRunner[T]) fail(ctx context.Context, evt cloudevents.Event, err error) {
+	increaseHandlerFailuresCounter(r.clientID, evt, handlerFailureReasonFailed)
+	r.onFailure(ctx, evt, err)
+}
+
+// lockKey locks the invocations of the given resource, the returned func unlocks them. There is nothing to lock if
+// the retry is disabled.
+func (r *handlerRunner[T]) lockKey(key string) func() {
+	if r.keyLocks == nil {
+		return func() {}
+	}
+
+	return r.keyLocks.lock(key)
+}
+
+// remove removes the item of the given key if it's not superseded.
+func (r *handlerRunner[T]) remove(key string, item *handlerRetryItem[T]) {
+	r.Lock()
+	defer r.Unlock()
+
+	if current, ok := r.items[key]; ok && current == item {
+		delete(r.items, key)
+	}
+}
+
+// invoke invokes the handlers in a span.
+func (r *handlerRunner[T]) invoke(ctx context.Context, evt cloudevents.Event, action types.ResourceAction, obj T,
+	handlers ...ResourceHandler[T]) ([]ResourceHandler[T], error) {
+	_, span := startEventSpan(ctx, r.tracer, handleSpanName, trace.SpanKindInternal, evt)
+	defer span.End()
+	span.SetAttributes(resourceActionAttribute.String(string(action)))
+
+	addInflightHandlers(r.clientID, 1)
+	defer addInflightHandlers(r.clientID, -1)
+
+	failed, err := invoke(action, obj, handlers...)
+	recordSpanError(span, err)
+	return failed, err
+}
+
+// invoke invokes the handlers and returns the failed handlers with their aggregated errors.
+func invoke[T ResourceObject](
+	action types.ResourceAction, obj T, handlers ...ResourceHandler[T]) ([]ResourceHandler[T], error) {
+	failed := []ResourceHandler[T]{}
+	errs := []error{}
+	for _, handler := range handlers {
+		if err := handler(action, obj); err != nil {
+			failed = append(failed, handler)
+			errs = append(errs, err)
+		}
+	}
+
+	return failed, utilerrors.NewAggregate(errs)
+}
+
+// keyLocks is a set of mutexes that are keyed by strings, a mutex is removed once it's not used.
+type keyLocks struct {
+	sync.Mutex
+
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	sync.Mutex
+
+	// refs is the number of the holders and waiters of the lock
+	refs int
+}
+
+func newKeyLocks() *keyLocks {
+	return &keyLocks{locks: map[string]*keyLock{}}
+}
+
+// lock locks the mutex of the given key and returns a func to unlock it.
+func (l *keyLocks) lock(key string) func() {
+	l.Lock()
+	kl, ok := l.locks[key]
+	if !ok {
+		kl = &keyLock{}
+		l.locks[key] = kl
+	}
+	kl.refs++
+	l.Unlock()
+
+	kl.Lock()
+	return func() {
+		kl.Unlock()
+
+		l.Lock()
+		defer l.Unlock()
+		kl.refs--
+		if kl.refs == 0 {
+			delete(l.locks, key)
+		}
+	}
+}
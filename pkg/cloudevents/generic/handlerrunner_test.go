@@ -0,0 +1,197 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/deadletter"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type countingHandler struct {
+	sync.Mutex
+	failures int
+	calls    int
+	versions []string
+}
+
+func (h *countingHandler) handle(action types.ResourceAction, obj *mockResource) error {
+	h.Lock()
+	defer h.Unlock()
+
+	h.calls++
+	if h.calls <= h.failures {
+		return fmt.Errorf("failed to handle %s", obj.UID)
+	}
+
+	h.versions = append(h.versions, obj.ResourceVersion)
+	return nil
+}
+
+func (h *countingHandler) getCalls() int {
+	h.Lock()
+	defer h.Unlock()
+	return h.calls
+}
+
+func (h *countingHandler) getVersions() []string {
+	h.Lock()
+	defer h.Unlock()
+	return h.versions
+}
+
+func TestHandlerRunnerWithoutRetry(t *testing.T) {
+	sink := deadletter.NewRingBufferSink(10)
+	runner := newHandlerRunner[*mockResource](&baseClient{clientID: testAgentName, deadLetterSink: sink, tracer: newTracer(nil)}, nil)
+
+	handler := &countingHandler{failures: 1}
+	evt := cloudevents.NewEvent()
+	runner.handle(context.TODO(), evt, types.Added, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1"}, handler.handle)
+
+	require.Equal(t, 1, handler.getCalls())
+	require.Len(t, sink.List(), 1)
+	require.Equal(t, options.DeadLetterStageHandle, sink.List()[0].Stage)
+}
+
+func TestHandlerRunnerWithRetry(t *testing.T) {
+	cases := []struct {
+		name             string
+		failures         int
+		expectedCalls    int
+		expectedFailures int
+	}{
+		{
+			name:          "succeeded after retries",
+			failures:      2,
+			expectedCalls: 3,
+		},
+		{
+			name:             "retries are exhausted",
+			failures:         10,
+			expectedCalls:    4,
+			expectedFailures: 1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var mutex sync.Mutex
+			failedEvents := []cloudevents.Event{}
+			runner := newHandlerRunner[*mockResource](&baseClient{clientID: testAgentName, tracer: newTracer(nil)}, &options.HandlerRetry{
+				MaxRetries: 3,
+				BaseDelay:  time.Millisecond,
+				OnFailure: func(evt cloudevents.Event, err error) {
+					mutex.Lock()
+					defer mutex.Unlock()
+					failedEvents = append(failedEvents, evt)
+				},
+			})
+			go runner.run(ctx)
+
+			handler := &countingHandler{failures: c.failures}
+			evt := cloudevents.NewEvent()
+			runner.handle(ctx, evt, types.Added, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1"}, handler.handle)
+
+			require.Eventually(t, func() bool {
+				return handler.getCalls() == c.expectedCalls
+			}, 5*time.Second, 10*time.Millisecond)
+
+			require.Eventually(t, func() bool {
+				mutex.Lock()
+				defer mutex.Unlock()
+				return len(failedEvents) == c.expectedFailures
+			}, 5*time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestHandlerRunnerSupersede(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runner := newHandlerRunner[*mockResource](&baseClient{clientID: testAgentName, tracer: newTracer(nil)}, &options.HandlerRetry{
+		BaseDelay: time.Hour,
+	})
+	go runner.run(ctx)
+
+	handler := &countingHandler{failures: 1}
+	runner.handle(ctx, cloudevents.NewEvent(), types.Added, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1"}, handler.handle)
+	runner.handle(ctx, cloudevents.NewEvent(), types.Modified, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2"}, handler.handle)
+
+	require.Equal(t, []string{"2"}, handler.getVersions())
+
+	runner.Lock()
+	defer runner.Unlock()
+	require.Empty(t, runner.items)
+}
+
+func TestHandlerRunnerRetryRacesNewerEvent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runner := newHandlerRunner[*mockResource](&baseClient{clientID: testAgentName, tracer: newTracer(nil)}, &options.HandlerRetry{
+		BaseDelay: time.Millisecond,
+	})
+	go runner.run(ctx)
+
+	var mutex sync.Mutex
+	calls := 0
+	versions := []string{}
+	retryStarted := make(chan struct{})
+	releaseRetry := make(chan struct{})
+	handler := func(action types.ResourceAction, obj *mockResource) error {
+		if obj.ResourceVersion == "1" {
+			mutex.Lock()
+			calls++
+			first := calls == 1
+			mutex.Unlock()
+
+			if first {
+				return fmt.Errorf("failed to handle %s", obj.UID)
+			}
+
+			// block the retry until the newer event arrives
+			close(retryStarted)
+			<-releaseRetry
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		versions = append(versions, obj.ResourceVersion)
+		return nil
+	}
+
+	runner.handle(ctx, cloudevents.NewEvent(), types.Added, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1"}, handler)
+	<-retryStarted
+
+	handled := make(chan struct{})
+	go func() {
+		runner.handle(ctx, cloudevents.NewEvent(), types.Modified, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2"}, handler)
+		close(handled)
+	}()
+
+	// the newer event waits for the running retry
+	select {
+	case <-handled:
+		t.Fatal("the newer event is handled before the running retry completes")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(releaseRetry)
+	<-handled
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.Equal(t, []string{"1", "2"}, versions)
+}
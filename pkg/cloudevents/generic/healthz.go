@@ -0,0 +1,117 @@
+package generic
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HealthChecker is a named health check, it's compatible with the healthz.HealthChecker of k8s.io/apiserver, so it
+// can be installed to the /healthz or /readyz endpoints of a controller.
+type HealthChecker interface {
+	Name() string
+	Check(req *http.Request) error
+}
+
+// ClientStatus is the status of a source/agent client.
+type ClientStatus struct {
+	// ConnectionState is the current state of the client connection.
+	ConnectionState ConnectionState
+
+	// Subscribed is true if the client has subscribed to receive the events.
+	Subscribed bool
+
+	// SubscribedTime is the time when the client subscribed.
+	SubscribedTime time.Time
+
+	// LastReceivedTime is the time when the client received the last event, it's zero if the client has not received
+	// any event.
+	LastReceivedTime time.Time
+
+	// PendingResyncRequests is the number of the received resync requests that are held until the lister is synced.
+	PendingResyncRequests int
+
+	// PendingResyncSince is the time since when the resync requests are held, it's zero if there is no pending
+	// resync request.
+	PendingResyncSince time.Time
+
+	// PendingMerkleResyncs is the number of the merkle resync requests that are waiting for the first response of
+	// the agents, it's always zero for an agent.
+	PendingMerkleResyncs int
+}
+
+// ClientStatusGetter gets the status of a source/agent client.
+type ClientStatusGetter interface {
+	Status() ClientStatus
+}
+
+// HealthCheckOptions configures the conditions in which a client is unhealthy besides it's not connected or not
+// subscribed.
+type HealthCheckOptions struct {
+	// MaxReceiveSilence is the maximum duration since the client received the last event (or subscribed if it has not
+	// received any event). If it's less than or equal to zero, the received events are not checked.
+	MaxReceiveSilence time.Duration
+
+	// MaxResyncPending is the maximum duration that the received resync requests are held. If it's less than or equal
+	// to zero, the pending resync requests are not checked.
+	MaxResyncPending time.Duration
+}
+
+// ClientHealthChecker checks the health of a source/agent client.
+type ClientHealthChecker struct {
+	name    string
+	client  ClientStatusGetter
+	options HealthCheckOptions
+}
+
+var _ HealthChecker = &ClientHealthChecker{}
+
+// NewClientHealthChecker returns a ClientHealthChecker with the given name for a source/agent client.
+func NewClientHealthChecker(name string, client ClientStatusGetter, options HealthCheckOptions) *ClientHealthChecker {
+	return &ClientHealthChecker{
+		name:    name,
+		client:  client,
+		options: options,
+	}
+}
+
+func (c *ClientHealthChecker) Name() string {
+	return c.name
+}
+
+// Check returns an error if the client is not connected, not subscribed, has not received any event for the
+// MaxReceiveSilence or has held the resync requests for the MaxResyncPending.
+func (c *ClientHealthChecker) Check(_ *http.Request) error {
+	return CheckClientStatus(c.client.Status(), c.options, time.Now())
+}
+
+// CheckClientStatus checks the status of a client at the given time with the options.
+func CheckClientStatus(status ClientStatus, options HealthCheckOptions, now time.Time) error {
+	if status.ConnectionState != ConnectionStateReady {
+		return fmt.Errorf("the client is not connected, the connection state is %s", status.ConnectionState)
+	}
+
+	if !status.Subscribed {
+		return fmt.Errorf("the client is not subscribed")
+	}
+
+	if options.MaxReceiveSilence > 0 {
+		lastReceivedTime := status.LastReceivedTime
+		if lastReceivedTime.IsZero() {
+			lastReceivedTime = status.SubscribedTime
+		}
+
+		if silence := now.Sub(lastReceivedTime); silence > options.MaxReceiveSilence {
+			return fmt.Errorf("the client has not received any event for %v", silence.Truncate(time.Second))
+		}
+	}
+
+	if options.MaxResyncPending > 0 && status.PendingResyncRequests > 0 {
+		if pending := now.Sub(status.PendingResyncSince); pending > options.MaxResyncPending {
+			return fmt.Errorf("the client has held %d resync requests for %v",
+				status.PendingResyncRequests, pending.Truncate(time.Second))
+		}
+	}
+
+	return nil
+}
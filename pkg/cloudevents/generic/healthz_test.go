@@ -0,0 +1,126 @@
+package generic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/stretchr/testify/require"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestCheckClientStatus(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		name             string
+		status           ClientStatus
+		options          HealthCheckOptions
+		expectedErrorMsg string
+	}{
+		{
+			name:             "disconnected",
+			status:           ClientStatus{ConnectionState: ConnectionStateDisconnected},
+			expectedErrorMsg: "the client is not connected, the connection state is Disconnected",
+		},
+		{
+			name:             "not subscribed",
+			status:           ClientStatus{ConnectionState: ConnectionStateReady},
+			expectedErrorMsg: "the client is not subscribed",
+		},
+		{
+			name: "healthy",
+			status: ClientStatus{
+				ConnectionState: ConnectionStateReady,
+				Subscribed:      true,
+				SubscribedTime:  now.Add(-time.Hour),
+			},
+		},
+		{
+			name: "no event is received after subscribed",
+			status: ClientStatus{
+				ConnectionState: ConnectionStateReady,
+				Subscribed:      true,
+				SubscribedTime:  now.Add(-time.Hour),
+			},
+			options:          HealthCheckOptions{MaxReceiveSilence: time.Minute},
+			expectedErrorMsg: "the client has not received any event for 1h0m0s",
+		},
+		{
+			name: "event is received recently",
+			status: ClientStatus{
+				ConnectionState:  ConnectionStateReady,
+				Subscribed:       true,
+				SubscribedTime:   now.Add(-time.Hour),
+				LastReceivedTime: now.Add(-time.Second),
+			},
+			options: HealthCheckOptions{MaxReceiveSilence: time.Minute},
+		},
+		{
+			name: "resync requests are held too long",
+			status: ClientStatus{
+				ConnectionState:       ConnectionStateReady,
+				Subscribed:            true,
+				PendingResyncRequests: 2,
+				PendingResyncSince:    now.Add(-10 * time.Minute),
+			},
+			options:          HealthCheckOptions{MaxResyncPending: time.Minute},
+			expectedErrorMsg: "the client has held 2 resync requests for 10m0s",
+		},
+		{
+			name: "resync requests are pending",
+			status: ClientStatus{
+				ConnectionState:       ConnectionStateReady,
+				Subscribed:            true,
+				PendingResyncRequests: 2,
+				PendingResyncSince:    now.Add(-time.Second),
+			},
+			options: HealthCheckOptions{MaxResyncPending: time.Minute},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := CheckClientStatus(c.status, c.options, now)
+			if len(c.expectedErrorMsg) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, c.expectedErrorMsg)
+		})
+	}
+}
+
+func TestClientHealthChecker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	agentOptions := fake.NewAgentOptions(gochan.New(), nil, "cluster1", testAgentName)
+	agent, err := NewCloudEventAgentClient[*mockResource](ctx, agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	checker := NewClientHealthChecker("agent", agent, HealthCheckOptions{})
+	require.Equal(t, "agent", checker.Name())
+	require.EqualError(t, checker.Check(nil), "the client is not subscribed")
+
+	agent.Subscribe(ctx)
+	require.NoError(t, checker.Check(nil))
+	require.True(t, agent.Status().LastReceivedTime.IsZero())
+
+	// send an event to the agent
+	evt := types.NewEventBuilder(testSourceName, types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              types.ResyncRequestAction,
+	}).WithClusterName("cluster1").NewEvent()
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, map[string]string{}))
+	require.NoError(t, agent.cloudEventsClient.Send(ctx, evt))
+
+	require.Eventually(t, func() bool {
+		return !agent.Status().LastReceivedTime.IsZero()
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,298 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	// DefaultHeartbeatInterval is the default interval of an agent to publish its heartbeats.
+	DefaultHeartbeatInterval = 30 * time.Second
+
+	// DefaultHeartbeatLeaseDuration is the default duration of an agent lease that is renewed by its heartbeats.
+	DefaultHeartbeatLeaseDuration = 90 * time.Second
+
+	// DefaultHeartbeatEvictionGracePeriod is the default duration that an expired agent lease is kept before the
+	// agent is removed.
+	DefaultHeartbeatEvictionGracePeriod = 10 * time.Minute
+
+	sdkModulePath = "open-cluster-management.io/sdk-go"
+)
+
+// sdkVersion returns the version of the sdk-go module that the current binary is built with.
+var sdkVersion = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	if info.Main.Path == sdkModulePath {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != sdkModulePath {
+			continue
+		}
+		if dep.Replace != nil && len(dep.Replace.Version) != 0 {
+			return dep.Replace.Version
+		}
+		return dep.Version
+	}
+
+	return "unknown"
+})
+
+// runHeartbeat publishes the heartbeats of the agent to all sources periodically until the context is done, the
+// heartbeats are skipped while the client is disconnected.
+func (c *CloudEventAgentClient[T]) runHeartbeat(ctx context.Context, config *options.AgentHeartbeat) {
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if !c.isClientReady() {
+			klog.V(4).Infof("the cloudevents client is not ready, skip the heartbeat")
+			return
+		}
+
+		if err := c.publishHeartbeat(ctx); err != nil {
+			klog.Errorf("failed to publish the heartbeat, %v", err)
+		}
+	}, interval)
+}
+
+func (c *CloudEventAgentClient[T]) publishHeartbeat(ctx context.Context) error {
+	dataTypes := []string{}
+	for dataType := range c.codecs {
+		dataTypes = append(dataTypes, dataType.String())
+	}
+	sort.Strings(dataTypes)
+
+	evt := types.NewEventBuilder(c.agentID, types.HeartbeatEventType).
+		WithOriginalSource(types.SourceAll).
+		WithClusterName(c.clusterName).
+		NewEvent()
+	heartbeat := &payload.AgentHeartbeat{
+		AgentID:     c.agentID,
+		ClusterName: c.clusterName,
+		DataTypes:   dataTypes,
+		SDKVersion:  sdkVersion(),
+	}
+	if err := evt.SetData(cloudevents.ApplicationJSON, heartbeat); err != nil {
+		return fmt.Errorf("failed to set data to cloud event: %v", err)
+	}
+
+	if err := c.publish(ctx, evt); err != nil {
+		return err
+	}
+
+	increaseCloudEventsSentCounter(evt.Source(), c.clusterName, types.HeartbeatEventDataType.String())
+	return nil
+}
+
+// AgentLiveness is the liveness of an agent that is tracked with its heartbeats.
+type AgentLiveness struct {
+	// AgentHeartbeat is the last heartbeat of the agent.
+	payload.AgentHeartbeat
+
+	// LastSeen is the time when the last heartbeat of the agent is received.
+	LastSeen time.Time
+
+	// Alive is false if the lease of the agent expires.
+	Alive bool
+}
+
+// HeartbeatTracker tracks the liveness of the agents with their heartbeats on a source, the agents are identified by
+// their cluster names. An agent is removed if its lease is expired for longer than the eviction grace period, and
+// the tracking is stopped when the source client is stopped.
+type HeartbeatTracker struct {
+	sync.RWMutex
+
+	sourceID            string
+	leaseDuration       time.Duration
+	evictionGracePeriod time.Duration
+	onExpired           func(clusterName string, lastSeen time.Time)
+	leases              map[string]*agentLease
+	stopped             bool
+}
+
+type agentLease struct {
+	liveness AgentLiveness
+	timer    *time.Timer
+	// generation is increased when the lease is renewed, an expiry or eviction of an older generation is ignored
+	generation int64
+}
+
+func newHeartbeatTracker(ctx context.Context, sourceID string, config *options.HeartbeatLease) *HeartbeatTracker {
+	if config == nil {
+		return nil
+	}
+
+	leaseDuration := config.LeaseDuration
+	if leaseDuration <= 0 {
+		leaseDuration = DefaultHeartbeatLeaseDuration
+	}
+
+	evictionGracePeriod := config.EvictionGracePeriod
+	if evictionGracePeriod <= 0 {
+		evictionGracePeriod = DefaultHeartbeatEvictionGracePeriod
+	}
+
+	tracker := &HeartbeatTracker{
+		sourceID:            sourceID,
+		leaseDuration:       leaseDuration,
+		evictionGracePeriod: evictionGracePeriod,
+		onExpired:           config.OnExpired,
+		leases:              map[string]*agentLease{},
+	}
+
+	// stop the lease timers when the source client is stopped
+	go func() {
+		<-ctx.Done()
+		tracker.stop()
+	}()
+
+	return tracker
+}
+
+// LastSeen returns the time when the last heartbeat of the agent on the given cluster is received, it returns false
+// if there is no heartbeat from the cluster.
+func (t *HeartbeatTracker) LastSeen(clusterName string) (time.Time, bool) {
+	t.RLock()
+	defer t.RUnlock()
+
+	lease, ok := t.leases[clusterName]
+	if !ok {
+		return time.Time{}, false
+	}
+	return lease.liveness.LastSeen, true
+}
+
+// Agents returns the liveness of the agents that have sent heartbeats, they are sorted by their cluster names.
+func (t *HeartbeatTracker) Agents() []AgentLiveness {
+	t.RLock()
+	defer t.RUnlock()
+
+	agents := []AgentLiveness{}
+	for _, lease := range t.leases {
+		agents = append(agents, lease.liveness)
+	}
+	sort.Slice(agents, func(i, j int) bool {
+		return agents[i].ClusterName < agents[j].ClusterName
+	})
+	return agents
+}
+
+// renew renews the lease of the agent that sends the heartbeat event.
+func (t *HeartbeatTracker) renew(evt cloudevents.Event) error {
+	heartbeat, err := payload.DecodeHeartbeat(evt)
+	if err != nil {
+		return err
+	}
+
+	if len(heartbeat.ClusterName) == 0 {
+		clusterName, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionClusterName])
+		if err != nil {
+			return fmt.Errorf("the cluster name of the heartbeat %s is not found", evt.ID())
+		}
+		heartbeat.ClusterName = clusterName
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if t.stopped {
+		return nil
+	}
+
+	now := time.Now()
+	lease, ok := t.leases[heartbeat.ClusterName]
+	if !ok {
+		lease = &agentLease{}
+		t.leases[heartbeat.ClusterName] = lease
+	}
+	if lease.timer != nil {
+		lease.timer.Stop()
+	}
+
+	lease.liveness = AgentLiveness{AgentHeartbeat: *heartbeat, LastSeen: now, Alive: true}
+	lease.generation++
+
+	generation := lease.generation
+	lease.timer = time.AfterFunc(t.leaseDuration, func() {
+		t.expire(heartbeat.ClusterName, generation)
+	})
+
+	updateAgentHeartbeatMetrics(t.sourceID, heartbeat.ClusterName, now, true)
+	return nil
+}
+
+func (t *HeartbeatTracker) expire(clusterName string, generation int64) {
+	t.Lock()
+	lease, ok := t.leases[clusterName]
+	if t.stopped || !ok || lease.generation != generation {
+		// the lease is renewed or the tracker is stopped
+		t.Unlock()
+		return
+	}
+	lease.liveness.Alive = false
+	lastSeen := lease.liveness.LastSeen
+	lease.timer = time.AfterFunc(t.evictionGracePeriod, func() {
+		t.evict(clusterName, generation)
+	})
+	t.Unlock()
+
+	klog.Warningf("the lease of the agent on the cluster %s is expired, the last heartbeat is at %s",
+		clusterName, lastSeen.Format(time.RFC3339))
+	updateAgentHeartbeatMetrics(t.sourceID, clusterName, lastSeen, false)
+
+	if t.onExpired != nil {
+		t.onExpired(clusterName, lastSeen)
+	}
+}
+
+// evict removes the agent whose lease is expired for longer than the eviction grace period.
+func (t *HeartbeatTracker) evict(clusterName string, generation int64) {
+	t.Lock()
+	defer t.Unlock()
+
+	lease, ok := t.leases[clusterName]
+	if t.stopped || !ok || lease.generation != generation {
+		// the lease is renewed or the tracker is stopped
+		return
+	}
+	delete(t.leases, clusterName)
+
+	klog.V(4).Infof("the agent on the cluster %s is removed, the last heartbeat is at %s",
+		clusterName, lease.liveness.LastSeen.Format(time.RFC3339))
+	deleteAgentHeartbeatMetrics(t.sourceID, clusterName)
+}
+
+// stop stops the timers of all leases, the heartbeats are not tracked after the tracker is stopped.
+func (t *HeartbeatTracker) stop() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.stopped = true
+	for _, lease := range t.leases {
+		if lease.timer != nil {
+			lease.timer.Stop()
+		}
+	}
+}
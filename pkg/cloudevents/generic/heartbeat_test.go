@@ -0,0 +1,195 @@
+package generic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/stretchr/testify/require"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestAgentHeartbeat(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	agentOptions := fake.NewAgentOptions(gochan.New(), nil, "cluster1", testAgentName)
+	agentOptions.Heartbeat = &options.AgentHeartbeat{Interval: 10 * time.Millisecond}
+	agent, err := NewCloudEventAgentClient[*mockResource](ctx, agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	eventChan := make(chan cloudevents.Event, 10)
+	go func() {
+		_ = agent.cloudEventsClient.StartReceiver(ctx, func(event cloudevents.Event) {
+			eventChan <- event
+		})
+	}()
+
+	// the heartbeats are published periodically
+	for i := 0; i < 2; i++ {
+		select {
+		case evt := <-eventChan:
+			require.Equal(t, types.HeartbeatEventType.String(), evt.Type())
+			require.Equal(t, types.SourceAll, evt.Extensions()[types.ExtensionOriginalSource])
+
+			heartbeat, err := payload.DecodeHeartbeat(evt)
+			require.NoError(t, err)
+			require.Equal(t, testAgentName, heartbeat.AgentID)
+			require.Equal(t, "cluster1", heartbeat.ClusterName)
+			require.Equal(t, []string{mockEventDataType.String()}, heartbeat.DataTypes)
+			require.NotEmpty(t, heartbeat.SDKVersion)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout to wait for the heartbeat")
+		}
+	}
+}
+
+func TestHeartbeatTracker(t *testing.T) {
+	ResetCloudEventsMetrics()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expired := make(chan string, 1)
+	sourceOptions := fake.NewSourceOptions(gochan.New(), testSourceName)
+	sourceOptions.HeartbeatLease = &options.HeartbeatLease{
+		LeaseDuration: 100 * time.Millisecond,
+		OnExpired: func(clusterName string, lastSeen time.Time) {
+			expired <- clusterName
+		},
+	}
+	source, err := NewCloudEventSourceClient[*mockResource](ctx, sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	tracker := source.HeartbeatTracker()
+	require.NotNil(t, tracker)
+
+	_, ok := tracker.LastSeen("cluster1")
+	require.False(t, ok)
+
+	evt := types.NewEventBuilder(testAgentName, types.HeartbeatEventType).
+		WithOriginalSource(types.SourceAll).
+		WithClusterName("cluster1").
+		NewEvent()
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, &payload.AgentHeartbeat{
+		AgentID:     testAgentName,
+		ClusterName: "cluster1",
+		SDKVersion:  "v0.15.0",
+	}))
+	source.receive(ctx, evt)
+
+	lastSeen, ok := tracker.LastSeen("cluster1")
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now(), lastSeen, time.Second)
+
+	agents := tracker.Agents()
+	require.Len(t, agents, 1)
+	require.True(t, agents[0].Alive)
+	require.Equal(t, "v0.15.0", agents[0].SDKVersion)
+	require.Equal(t, 1.0, toFloat64Gauge(agentAliveMetric.WithLabelValues(testSourceName, "cluster1")))
+	require.Equal(t, float64(lastSeen.Unix()),
+		toFloat64Gauge(agentHeartbeatLastSeenMetric.WithLabelValues(testSourceName, "cluster1")))
+
+	// the lease expires without heartbeats
+	select {
+	case clusterName := <-expired:
+		require.Equal(t, "cluster1", clusterName)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout to wait for the lease expiry")
+	}
+
+	require.False(t, tracker.Agents()[0].Alive)
+	require.Equal(t, 0.0, toFloat64Gauge(agentAliveMetric.WithLabelValues(testSourceName, "cluster1")))
+
+	// the lease is renewed by a new heartbeat
+	source.receive(ctx, evt)
+	require.True(t, tracker.Agents()[0].Alive)
+}
+
+func TestHeartbeatTrackerEviction(t *testing.T) {
+	ResetCloudEventsMetrics()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceOptions := fake.NewSourceOptions(gochan.New(), testSourceName)
+	sourceOptions.HeartbeatLease = &options.HeartbeatLease{
+		LeaseDuration:       50 * time.Millisecond,
+		EvictionGracePeriod: 100 * time.Millisecond,
+	}
+	source, err := NewCloudEventSourceClient[*mockResource](ctx, sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	tracker := source.HeartbeatTracker()
+	source.receive(ctx, newHeartbeatEvent(t, "cluster1"))
+	_, ok := tracker.LastSeen("cluster1")
+	require.True(t, ok)
+
+	// the agent is removed after its lease is expired for the grace period
+	require.Eventually(t, func() bool {
+		_, ok := tracker.LastSeen("cluster1")
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Empty(t, tracker.Agents())
+	require.False(t, agentAliveMetric.DeleteLabelValues(testSourceName, "cluster1"))
+	require.False(t, agentHeartbeatLastSeenMetric.DeleteLabelValues(testSourceName, "cluster1"))
+}
+
+func TestHeartbeatTrackerStop(t *testing.T) {
+	ResetCloudEventsMetrics()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expired := make(chan string, 1)
+	sourceOptions := fake.NewSourceOptions(gochan.New(), testSourceName)
+	sourceOptions.HeartbeatLease = &options.HeartbeatLease{
+		LeaseDuration: 100 * time.Millisecond,
+		OnExpired: func(clusterName string, lastSeen time.Time) {
+			expired <- clusterName
+		},
+	}
+	source, err := NewCloudEventSourceClient[*mockResource](ctx, sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	tracker := source.HeartbeatTracker()
+	source.receive(ctx, newHeartbeatEvent(t, "cluster1"))
+
+	// the lease timers are stopped with the source client
+	cancel()
+	require.Eventually(t, func() bool {
+		tracker.RLock()
+		defer tracker.RUnlock()
+		return tracker.stopped
+	}, 5*time.Second, 10*time.Millisecond)
+
+	select {
+	case clusterName := <-expired:
+		t.Fatalf("unexpected lease expiry of %s", clusterName)
+	case <-time.After(300 * time.Millisecond):
+	}
+	require.True(t, tracker.Agents()[0].Alive)
+
+	// the heartbeats are not tracked after the tracker is stopped
+	require.NoError(t, tracker.renew(newHeartbeatEvent(t, "cluster2")))
+	_, ok := tracker.LastSeen("cluster2")
+	require.False(t, ok)
+}
+
+func newHeartbeatEvent(t *testing.T, clusterName string) cloudevents.Event {
+	evt := types.NewEventBuilder(testAgentName, types.HeartbeatEventType).
+		WithOriginalSource(types.SourceAll).
+		WithClusterName(clusterName).
+		NewEvent()
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, &payload.AgentHeartbeat{
+		AgentID:     testAgentName,
+		ClusterName: clusterName,
+	}))
+	return evt
+}
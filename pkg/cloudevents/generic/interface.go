@@ -38,6 +38,25 @@ type Lister[T ResourceObject] interface {
 	List(options types.ListOptions) ([]T, error)
 }
 
+// SyncedLister is a Lister that knows whether its cache has been synced. If the lister of a source/agent client
+// implements it, the resync requests that are received before the cache is synced are queued, and they will be
+// answered after the cache is synced.
+type SyncedLister[T ResourceObject] interface {
+	Lister[T]
+
+	// HasSynced returns true if the cache of the lister has been synced.
+	HasSynced() bool
+}
+
+// SourcesLister is a Lister that knows the sources of its resources. If the lister of an agent client implements it,
+// the agent client loads its known sources from the lister, so the agent still knows its sources after it restarts.
+type SourcesLister[T ResourceObject] interface {
+	Lister[T]
+
+	// ListSources returns the sources of the resources that are maintained by the agent on the given cluster.
+	ListSources(clusterName string) ([]string, error)
+}
+
 type Codec[T ResourceObject] interface {
 	// EventDataType indicates which type of the event data the codec is used for.
 	EventDataType() types.CloudEventsDataType
@@ -52,13 +71,24 @@ type Codec[T ResourceObject] interface {
 	Decode(event *cloudevents.Event) (T, error)
 }
 
+// VersionedCodec is a Codec that supports several versions of its data type. The codec is used for each of the
+// supported versions, it encodes/decodes a resource object to/from a cloudevent with the version of the event type.
+type VersionedCodec[T ResourceObject] interface {
+	Codec[T]
+
+	// SupportedVersions returns the versions of the data type that are supported by the codec, besides the version
+	// of its EventDataType.
+	SupportedVersions() []string
+}
+
 type CloudEventsClient[T ResourceObject] interface {
 	// Resync the resources of one source/agent by sending resync request.
 	// The second parameter is used to specify cluster name/source ID for a source/agent.
 	//   - A source sends the resource status resync request to a cluster with the given cluster name.
 	//     If setting this parameter to `types.ClusterAll`, the source will broadcast the resync request to all clusters.
 	//   - An agent sends the resources spec resync request to a source with the given source ID.
-	//     If setting this parameter to `types.SourceAll`, the agent will broadcast the resync request to all sources.
+	//     If setting this parameter to `types.SourceAll`, the agent will send the resync request to each of its known
+	//     sources, or broadcast the resync request to all sources if it does not know any source yet.
 	Resync(context.Context, string) error
 
 	// Publish the resources spec/status event to the broker.
@@ -70,5 +100,26 @@ type CloudEventsClient[T ResourceObject] interface {
 
 	// ReconnectedChan returns a chan which indicates the source/agent client is reconnected.
 	// The source/agent client callers should consider sending a resync request when receiving this signal.
+	// The client does not wait for the signal to be received, the signals that are not received in time are coalesced.
 	ReconnectedChan() <-chan struct{}
 }
+
+// BatchPublisher publishes the resources in batch. The source/agent clients of this package implement it, the callers
+// type-assert a CloudEventsClient to it to publish the resources in batch.
+type BatchPublisher[T ResourceObject] interface {
+	// PublishBatch publishes the resources spec/status events to the broker in batch, the resources are grouped by
+	// their clusters/sources. It returns the errors of the resources in the order of the given resources, the error
+	// of a resource is nil if the resource is published.
+	PublishBatch(ctx context.Context, eventType types.CloudEventsType, objs []T) []error
+}
+
+// ConnectionStateObserver observes the state of a source/agent client connection. The source/agent clients of this
+// package implement it, the callers type-assert a CloudEventsClient to it to observe the connection state.
+type ConnectionStateObserver interface {
+	// ConnectionState returns the current state of the source/agent client connection.
+	ConnectionState() ConnectionState
+
+	// OnConnectionStateChange subscribes the changes of the source/agent client connection state, the handler is
+	// called in order with the changes until the context is done. The handler does not block the client.
+	OnConnectionStateChange(ctx context.Context, handler ConnectionStateHandler)
+}
@@ -0,0 +1,67 @@
+package generic
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// knownSources tracks the sources that an agent has received resources or resync requests from, so the agent can
+// resync each of its sources individually instead of broadcasting the resync request to all sources.
+type knownSources struct {
+	sync.RWMutex
+
+	clusterName string
+	sources     sets.Set[string]
+	// listSources is nil if the lister is not a SourcesLister
+	listSources func(clusterName string) ([]string, error)
+}
+
+func newKnownSources[T ResourceObject](clusterName string, lister Lister[T]) *knownSources {
+	s := &knownSources{
+		clusterName: clusterName,
+		sources:     sets.New[string](),
+	}
+	if sourcesLister, ok := lister.(SourcesLister[T]); ok {
+		s.listSources = sourcesLister.ListSources
+	}
+	return s
+}
+
+// add records a source, the broadcast source is ignored.
+func (s *knownSources) add(source string) {
+	if len(source) == 0 || source == types.SourceAll {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.sources.Has(source) {
+		klog.V(4).Infof("the agent on the cluster %s knows a new source %s", s.clusterName, source)
+		s.sources.Insert(source)
+	}
+}
+
+// list returns the sorted known sources, the sources of the resources that are listed by the lister are merged
+// into the known sources firstly.
+func (s *knownSources) list() []string {
+	if s.listSources != nil {
+		sources, err := s.listSources(s.clusterName)
+		if err != nil {
+			klog.Warningf("failed to list the sources from the lister, %v", err)
+		}
+
+		for _, source := range sources {
+			s.add(source)
+		}
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	return sets.List(s.sources)
+}
@@ -0,0 +1,98 @@
+package generic
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/stretchr/testify/require"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type mockSourcesLister struct {
+	*mockResourceLister
+	sources []string
+}
+
+func (l *mockSourcesLister) ListSources(clusterName string) ([]string, error) {
+	return l.sources, nil
+}
+
+func TestAgentResyncKnownSources(t *testing.T) {
+	cases := []struct {
+		name                  string
+		listedSources         []string
+		receivedSources       []string
+		expectedKnownSources  []string
+		expectedResyncSources []string
+	}{
+		{
+			name:                  "no known sources",
+			expectedResyncSources: []string{types.SourceAll},
+		},
+		{
+			name:                  "known sources from the lister and the received resources",
+			listedSources:         []string{"source1", "source2"},
+			receivedSources:       []string{"source2", "source3"},
+			expectedKnownSources:  []string{"source1", "source2", "source3"},
+			expectedResyncSources: []string{"source1", "source2", "source3"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			lister := &mockSourcesLister{mockResourceLister: newMockResourceLister(), sources: c.listedSources}
+			agent, err := NewCloudEventAgentClient[*mockResource](ctx,
+				fake.NewAgentOptions(gochan.New(), nil, "cluster1", testAgentName), lister, statusHash, newMockResourceCodec())
+			require.NoError(t, err)
+
+			// receive the resources from the sources
+			eventType := types.CloudEventsType{
+				CloudEventsDataType: mockEventDataType,
+				SubResource:         types.SubResourceSpec,
+				Action:              "test_create_request",
+			}
+			for _, source := range c.receivedSources {
+				evt, err := newMockResourceCodec().Encode(source, eventType, &mockResource{
+					UID: kubetypes.UID(source), ResourceVersion: "1", Namespace: "cluster1"})
+				require.NoError(t, err)
+				agent.receive(ctx, *evt, func(action types.ResourceAction, obj *mockResource) error { return nil })
+			}
+
+			require.ElementsMatch(t, c.expectedKnownSources, agent.KnownSources())
+
+			originalSources := []string{}
+			mutex := &sync.Mutex{}
+			go func() {
+				_ = agent.cloudEventsClient.StartReceiver(ctx, func(event cloudevents.Event) {
+					mutex.Lock()
+					defer mutex.Unlock()
+					originalSource, _ := cloudeventstypes.ToString(event.Extensions()[types.ExtensionOriginalSource])
+					originalSources = append(originalSources, originalSource)
+				})
+			}()
+
+			require.NoError(t, agent.Resync(ctx, types.SourceAll))
+			require.Eventually(t, func() bool {
+				mutex.Lock()
+				defer mutex.Unlock()
+				return len(originalSources) == len(c.expectedResyncSources)
+			}, 5*time.Second, 10*time.Millisecond)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			require.ElementsMatch(t, c.expectedResyncSources, originalSources)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package generic
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+)
+
+// DefaultMerkleResyncTimeout is the default duration that a source waits for the first merkle resync response.
+const DefaultMerkleResyncTimeout = 30 * time.Second
+
+// merkleResyncTracker tracks the merkle resync requests that are waiting for the first response of the agents. An
+// agent that does not support the merkle resync ignores the request, so the source falls back to the full status
+// resync if the agent does not respond in time. The tracking is stopped when the source client is stopped.
+type merkleResyncTracker struct {
+	sync.Mutex
+
+	depth   int
+	timeout time.Duration
+	pending map[string]*time.Timer
+	stopped bool
+}
+
+func newMerkleResyncTracker(ctx context.Context, config *options.MerkleResync) *merkleResyncTracker {
+	if config == nil {
+		return nil
+	}
+
+	depth := config.Depth
+	if depth <= 0 {
+		depth = payload.DefaultMerkleTreeDepth
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultMerkleResyncTimeout
+	}
+
+	tracker := &merkleResyncTracker{
+		depth:   depth,
+		timeout: timeout,
+		pending: map[string]*time.Timer{},
+	}
+
+	// stop the timers when the source client is stopped
+	go func() {
+		<-ctx.Done()
+		tracker.stop()
+	}()
+
+	return tracker
+}
+
+// track starts waiting for the response of the given key, the fallback is called if there is no response in time.
+func (t *merkleResyncTracker) track(key string, fallback func()) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.stopped {
+		return
+	}
+
+	if timer, ok := t.pending[key]; ok {
+		timer.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(t.timeout, func() {
+		t.Lock()
+		current, ok := t.pending[key]
+		if t.stopped || !ok || current != timer {
+			// the request is responded or superseded, or the tracker is stopped
+			t.Unlock()
+			return
+		}
+		delete(t.pending, key)
+		t.Unlock()
+
+		fallback()
+	})
+	t.pending[key] = timer
+}
+
+// pendingRequests returns the number of the requests that are waiting for the response.
+func (t *merkleResyncTracker) pendingRequests() int {
+	if t == nil {
+		return 0
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	return len(t.pending)
+}
+
+// responded stops waiting for the response of the given key.
+func (t *merkleResyncTracker) responded(key string) {
+	if t == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if timer, ok := t.pending[key]; ok {
+		timer.Stop()
+		delete(t.pending, key)
+	}
+}
+
+// stop stops the timers of all pending requests, the fallbacks are not called after the tracker is stopped.
+func (t *merkleResyncTracker) stop() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.stopped = true
+	for key, timer := range t.pending {
+		timer.Stop()
+		delete(t.pending, key)
+	}
+}
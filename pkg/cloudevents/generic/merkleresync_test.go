@@ -0,0 +1,183 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/stretchr/testify/require"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type eventRecorder struct {
+	sync.Mutex
+	events []cloudevents.Event
+}
+
+func (r *eventRecorder) record(evt cloudevents.Event) {
+	r.Lock()
+	defer r.Unlock()
+	r.events = append(r.events, evt)
+}
+
+// flush returns the recorded events after the in-flight events are received.
+func (r *eventRecorder) flush() []cloudevents.Event {
+	time.Sleep(300 * time.Millisecond)
+
+	r.Lock()
+	defer r.Unlock()
+	events := r.events
+	r.events = nil
+	return events
+}
+
+func newMockResources(n int, changed ...int) []*mockResource {
+	resources := []*mockResource{}
+	for i := 0; i < n; i++ {
+		resources = append(resources, &mockResource{
+			UID:             kubetypes.UID(fmt.Sprintf("test%d", i)),
+			ResourceVersion: "1",
+			Status:          fmt.Sprintf("status%d", i),
+		})
+	}
+
+	for _, i := range changed {
+		resources[i].Status = fmt.Sprintf("status%d-changed", i)
+	}
+
+	return resources
+}
+
+func TestMerkleResync(t *testing.T) {
+	cases := []struct {
+		name                 string
+		changed              []int
+		expectedStatusEvents int
+	}{
+		{
+			name: "status is not changed",
+		},
+		{
+			name:                 "status is changed",
+			changed:              []int{3, 42},
+			expectedStatusEvents: 2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sourceOptions := fake.NewSourceOptions(gochan.New(), testSourceName)
+			sourceOptions.MerkleResync = &options.MerkleResync{Depth: 2}
+			source, err := NewCloudEventSourceClient[*mockResource](
+				ctx, sourceOptions, newMockResourceLister(newMockResources(100)...), statusHash, newMockResourceCodec())
+			require.NoError(t, err)
+
+			agentOptions := fake.NewAgentOptions(gochan.New(), nil, "cluster1", testAgentName)
+			agent, err := NewCloudEventAgentClient[*mockResource](
+				ctx, agentOptions, newMockResourceLister(newMockResources(100, c.changed...)...), statusHash, newMockResourceCodec())
+			require.NoError(t, err)
+
+			sourceEvents, agentEvents := &eventRecorder{}, &eventRecorder{}
+			go func() {
+				_ = source.cloudEventsClient.StartReceiver(ctx, sourceEvents.record)
+			}()
+			go func() {
+				_ = agent.cloudEventsClient.StartReceiver(ctx, agentEvents.record)
+			}()
+
+			require.NoError(t, source.Resync(ctx, "cluster1"))
+
+			// exchange the events between the source and the agent until there are no more events
+			statusEvents, exchangedEvents := 0, 0
+			for round := 0; round < 10; round++ {
+				requests := sourceEvents.flush()
+				for _, evt := range requests {
+					agent.receive(ctx, evt)
+				}
+
+				responses := agentEvents.flush()
+				for _, evt := range responses {
+					eventType, err := types.ParseCloudEventsType(evt.Type())
+					require.NoError(t, err)
+					if eventType.Action == types.ResyncResponseAction {
+						statusEvents++
+						continue
+					}
+					source.receive(ctx, evt)
+				}
+
+				exchangedEvents += len(requests) + len(responses)
+				if len(requests) == 0 && len(responses) == 0 {
+					break
+				}
+			}
+
+			require.Equal(t, c.expectedStatusEvents, statusEvents)
+			if len(c.changed) == 0 {
+				// one request and one response
+				require.Equal(t, 2, exchangedEvents)
+			}
+		})
+	}
+}
+
+func TestMerkleResyncFallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceOptions := fake.NewSourceOptions(gochan.New(), testSourceName)
+	sourceOptions.MerkleResync = &options.MerkleResync{Timeout: 100 * time.Millisecond}
+	source, err := NewCloudEventSourceClient[*mockResource](
+		ctx, sourceOptions, newMockResourceLister(newMockResources(10)...), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	sourceEvents := &eventRecorder{}
+	go func() {
+		_ = source.cloudEventsClient.StartReceiver(ctx, sourceEvents.record)
+	}()
+
+	// the fallback is not canceled with the context of the resync
+	resyncCtx, resyncCancel := context.WithCancel(ctx)
+	require.NoError(t, source.Resync(resyncCtx, "cluster1"))
+	resyncCancel()
+
+	// the agent does not respond, the full status resync request is sent after the timeout
+	actions := []types.EventAction{}
+	for _, evt := range sourceEvents.flush() {
+		eventType, err := types.ParseCloudEventsType(evt.Type())
+		require.NoError(t, err)
+		actions = append(actions, eventType.Action)
+	}
+	require.ElementsMatch(t, []types.EventAction{types.MerkleResyncRequestAction, types.ResyncRequestAction}, actions)
+}
+
+func TestMerkleResyncTrackerStop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	tracker := newMerkleResyncTracker(ctx, &options.MerkleResync{Timeout: 100 * time.Millisecond})
+	fallbacks := make(chan string, 2)
+	tracker.track("cluster1", func() { fallbacks <- "cluster1" })
+	require.Equal(t, 1, tracker.pendingRequests())
+
+	// the timers are stopped when the source client is stopped
+	cancel()
+	require.Eventually(t, func() bool { return tracker.pendingRequests() == 0 }, time.Second, 10*time.Millisecond)
+
+	// the requests are not tracked after the tracker is stopped
+	tracker.track("cluster2", func() { fallbacks <- "cluster2" })
+	require.Equal(t, 0, tracker.pendingRequests())
+
+	time.Sleep(300 * time.Millisecond)
+	require.Empty(t, fallbacks)
+}
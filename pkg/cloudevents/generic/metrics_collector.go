@@ -3,7 +3,10 @@ package generic
 import (
 	"time"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/prometheus/client_golang/prometheus"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
 // Subsystem used to define the metrics:
@@ -21,6 +24,10 @@ const (
 	metricsClientIDLabel   = "client_id"
 	metricsWorkActionLabel = "action"
 	metricsWorkCodeLabel   = "code"
+	metricsReasonLabel     = "reason"
+	metricsDirectionLabel  = "direction"
+	metricsPriorityLabel   = "priority"
+	metricsQueueLabel      = "queue"
 )
 
 // cloudeventsMetricsLabels - Array of labels added to cloudevents metrics:
@@ -35,6 +42,43 @@ var cloudeventsClientMetricsLabels = []string{
 	metricsClientIDLabel, // client_id
 }
 
+// cloudeventsClientDataTypeMetricsLabels - Array of labels added to cloudevents client metrics of a data type:
+var cloudeventsClientDataTypeMetricsLabels = []string{
+	metricsClientIDLabel, // client_id
+	metricsDataTypeLabel, // data type, e.g. manifests, manifestbundles
+}
+
+// cloudeventsFailureMetricsLabels - Array of labels added to cloudevents failure metrics:
+var cloudeventsFailureMetricsLabels = []string{
+	metricsClientIDLabel, // client_id
+	metricsDataTypeLabel, // data type, e.g. manifests, manifestbundles
+	metricsReasonLabel,   // reason, e.g. decode, send
+}
+
+// cloudeventsSizeMetricsLabels - Array of labels added to cloudevents size metrics:
+var cloudeventsSizeMetricsLabels = []string{
+	metricsClientIDLabel,  // client_id
+	metricsDataTypeLabel,  // data type, e.g. manifests, manifestbundles
+	metricsDirectionLabel, // direction, sent or received
+}
+
+// rateLimiterMetricsLabels - Array of labels added to cloudevents rate limiter metrics:
+var rateLimiterMetricsLabels = []string{
+	metricsClientIDLabel, // client_id
+	metricsPriorityLabel, // priority, e.g. change, resync, broadcast
+}
+
+// receivedQueueMetricsLabels - Array of labels added to received resources queue metrics:
+var receivedQueueMetricsLabels = []string{
+	metricsQueueLabel, // queue
+}
+
+// heartbeatMetricsLabels - Array of labels added to agent heartbeat metrics:
+var heartbeatMetricsLabels = []string{
+	metricsSourceLabel,  // source
+	metricsClusterLabel, // cluster
+}
+
 // workMetricsLabels - Array of labels added to manifestwork metrics:
 var workMetricsLabels = []string{
 	metricsWorkActionLabel, // action
@@ -48,7 +92,50 @@ const (
 	specResyncDurationMetric   = "spec_resync_duration_seconds"
 	statusResyncDurationMetric = "status_resync_duration_seconds"
 	clientReconnectedCounter   = "client_reconnected_total"
+	outboxDepthGauge           = "outbox_depth"
+	outboxDroppedCounter       = "outbox_dropped_total"
 	workProcessedCounter       = "processed_total"
+	agentHeartbeatLastSeen     = "agent_heartbeat_last_seen_timestamp_seconds"
+	agentAliveGauge            = "agent_alive"
+	dedupCacheHitsCounter      = "dedup_cache_hits_total"
+	dedupCacheMissesCounter    = "dedup_cache_misses_total"
+	publishDurationMetric      = "publish_duration_seconds"
+	rateLimiterWaitMetric      = "rate_limiter_wait_duration_seconds"
+	eventSizeMetric            = "event_size_bytes"
+	receiveToHandleMetric      = "receive_to_handle_duration_seconds"
+	decodeFailuresCounter      = "decode_failures_total"
+	handlerFailuresCounter     = "handler_failures_total"
+	publishFailuresCounter     = "publish_failures_total"
+	inflightHandlersGauge      = "inflight_handlers"
+	receivedQueueDepthGauge    = "received_queue_depth"
+)
+
+// Directions of the event size metric:
+const (
+	eventDirectionSent     = "sent"
+	eventDirectionReceived = "received"
+)
+
+// Reasons of the handler failures metric:
+const (
+	// handlerFailureReasonRetrying represents the failed handlers will be retried.
+	handlerFailureReasonRetrying = "retrying"
+	// handlerFailureReasonFailed represents the failed handlers will not be retried.
+	handlerFailureReasonFailed = "failed"
+)
+
+// Reasons of the publish failures metric:
+const (
+	// publishFailureReasonPrepare represents the event fails to be converted, encoded, encrypted, signed or queued.
+	publishFailureReasonPrepare = "prepare"
+	// publishFailureReasonRateLimit represents the rate limiter wait is canceled.
+	publishFailureReasonRateLimit = "rate_limit"
+	// publishFailureReasonContext represents the sending context fails to be derived.
+	publishFailureReasonContext = "context"
+	// publishFailureReasonNotReady represents the client is not ready.
+	publishFailureReasonNotReady = "not_ready"
+	// publishFailureReasonSend represents the event is not delivered by the transport.
+	publishFailureReasonSend = "send"
 )
 
 // The cloudevents received counter metric is a counter with a base metric name of 'received_total'
@@ -158,6 +245,90 @@ var clientReconnectedCounterMetric = prometheus.NewCounterVec(
 	cloudeventsClientMetricsLabels,
 )
 
+// The cloudevents outbox depth metric is a gauge with a base metric name of 'outbox_depth'
+// and a help string of 'The number of events queued in the outbox of the CloudEvents client.'
+// For example, 2 events are queued in the outbox of the CloudEvents client with client_id=client1 would result in the
+// following metrics:
+// cloudevents_outbox_depth{client_id="client1"} 2
+var outboxDepthGaugeMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Subsystem: cloudeventsMetricsSubsystem,
+		Name:      outboxDepthGauge,
+		Help:      "The number of events queued in the outbox of the CloudEvents client.",
+	},
+	cloudeventsClientMetricsLabels,
+)
+
+// The cloudevents outbox dropped counter metric is a counter with a base metric name of 'outbox_dropped_total'
+// and a help string of 'The total number of events dropped due to the outbox of the CloudEvents client is full.'
+// For example, 2 events are dropped by the CloudEvents client with client_id=client1 would result in the following
+// metrics:
+// cloudevents_outbox_dropped_total{client_id="client1"} 2
+var outboxDroppedCounterMetric = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Subsystem: cloudeventsMetricsSubsystem,
+		Name:      outboxDroppedCounter,
+		Help:      "The total number of events dropped due to the outbox of the CloudEvents client is full.",
+	},
+	cloudeventsClientMetricsLabels,
+)
+
+// The cloudevents dedup cache hits counter metric is a counter with a base metric name of 'dedup_cache_hits_total'
+// and a help string of 'The total number of duplicated events dropped by the CloudEvents client.'
+// For example, 2 duplicated events are dropped by the CloudEvents client with client_id=client1 would result in the
+// following metrics:
+// cloudevents_dedup_cache_hits_total{client_id="client1"} 2
+var dedupCacheHitsCounterMetric = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Subsystem: cloudeventsMetricsSubsystem,
+		Name:      dedupCacheHitsCounter,
+		Help:      "The total number of duplicated events dropped by the CloudEvents client.",
+	},
+	cloudeventsClientMetricsLabels,
+)
+
+// The cloudevents dedup cache misses counter metric is a counter with a base metric name of 'dedup_cache_misses_total'
+// and a help string of 'The total number of received events that are not duplicated for the CloudEvents client.'
+// For example, 2 events that are not duplicated are received by the CloudEvents client with client_id=client1 would
+// result in the following metrics:
+// cloudevents_dedup_cache_misses_total{client_id="client1"} 2
+var dedupCacheMissesCounterMetric = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Subsystem: cloudeventsMetricsSubsystem,
+		Name:      dedupCacheMissesCounter,
+		Help:      "The total number of received events that are not duplicated for the CloudEvents client.",
+	},
+	cloudeventsClientMetricsLabels,
+)
+
+// The agent heartbeat last seen metric is a gauge with a base metric name of
+// 'agent_heartbeat_last_seen_timestamp_seconds' and a help string of 'The unix time of the last received heartbeat
+// of the agent.'
+// For example, the last heartbeat of the agent on cluster1 is received by source1 at 1700000000 would result in the
+// following metrics:
+// cloudevents_agent_heartbeat_last_seen_timestamp_seconds{source="source1",cluster="cluster1"} 1700000000
+var agentHeartbeatLastSeenMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Subsystem: cloudeventsMetricsSubsystem,
+		Name:      agentHeartbeatLastSeen,
+		Help:      "The unix time of the last received heartbeat of the agent.",
+	},
+	heartbeatMetricsLabels,
+)
+
+// The agent alive metric is a gauge with a base metric name of 'agent_alive' and a help string of 'Whether the lease
+// of the agent is renewed by its heartbeats, 1 is alive and 0 is expired.'
+// For example, the lease of the agent on cluster1 is expired on source1 would result in the following metrics:
+// cloudevents_agent_alive{source="source1",cluster="cluster1"} 0
+var agentAliveMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Subsystem: cloudeventsMetricsSubsystem,
+		Name:      agentAliveGauge,
+		Help:      "Whether the lease of the agent is renewed by its heartbeats, 1 is alive and 0 is expired.",
+	},
+	heartbeatMetricsLabels,
+)
+
 var workProcessedCounterMetric = prometheus.NewCounterVec(
 	prometheus.CounterOpts{
 		Subsystem: manifestworkMetricsSubsystem,
@@ -167,6 +338,145 @@ var workProcessedCounterMetric = prometheus.NewCounterVec(
 	workMetricsLabels,
 )
 
+// The cloudevents publish duration metric is a histogram with a base metric name of 'publish_duration_seconds',
+// it observes the duration from waiting for the rate limiter to sending an event.
+// For example, 2 events of the manifestbundles type are published by the CloudEvents client with client_id=client1, one
+// taking 0.02s and the other taking 0.3s, would result in the following metrics:
+// cloudevents_publish_duration_seconds_bucket{client_id="client1",type="io.open-cluster-management.works.v1alpha1.manifestbundles",le="0.025"} 1
+// cloudevents_publish_duration_seconds_bucket{client_id="client1",type="io.open-cluster-management.works.v1alpha1.manifestbundles",le="0.5"} 2
+// cloudevents_publish_duration_seconds_sum{client_id="client1",type="io.open-cluster-management.works.v1alpha1.manifestbundles"} 0.32
+// cloudevents_publish_duration_seconds_count{client_id="client1",type="io.open-cluster-management.works.v1alpha1.manifestbundles"} 2
+var eventPublishDurationMetric = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Subsystem: cloudeventsMetricsSubsystem,
+		Name:      publishDurationMetric,
+		Help:      "The duration of publishing a CloudEvent in seconds, including the rate limiter wait.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	cloudeventsClientDataTypeMetricsLabels,
+)
+
+// The cloudevents rate limiter wait duration metric is a histogram with a base metric name of
+// 'rate_limiter_wait_duration_seconds', it observes the duration that an event waits for the client-side rate limiter.
+// For example, an event of the change priority waits 0.2s for the rate limiter of the CloudEvents client with
+// client_id=client1 would result in the following metrics:
+// cloudevents_rate_limiter_wait_duration_seconds_bucket{client_id="client1",priority="change",le="0.25"} 1
+// cloudevents_rate_limiter_wait_duration_seconds_sum{client_id="client1",priority="change"} 0.2
+// cloudevents_rate_limiter_wait_duration_seconds_count{client_id="client1",priority="change"} 1
+var rateLimiterWaitDurationMetric = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Subsystem: cloudeventsMetricsSubsystem,
+		Name:      rateLimiterWaitMetric,
+		Help:      "The duration of waiting for the client-side rate limiter in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	rateLimiterMetricsLabels,
+)
+
+// The cloudevents event size metric is a histogram with a base metric name of 'event_size_bytes', it observes the
+// size of the data of the sent and received events.
+// For example, an event of the manifestbundles type with 2000 bytes data is sent by the CloudEvents client with
+// client_id=client1 would result in the following metrics:
+// cloudevents_event_size_bytes_bucket{client_id="client1",type="io.open-cluster-management.works.v1alpha1.manifestbundles",direction="sent",le="4096"} 1
+// cloudevents_event_size_bytes_sum{client_id="client1",type="io.open-cluster-management.works.v1alpha1.manifestbundles",direction="sent"} 2000
+// cloudevents_event_size_bytes_count{client_id="client1",type="io.open-cluster-management.works.v1alpha1.manifestbundles",direction="sent"} 1
+var eventSizeHistogramMetric = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Subsystem: cloudeventsMetricsSubsystem,
+		Name:      eventSizeMetric,
+		Help:      "The size of the data of the sent and received CloudEvents in bytes.",
+		// 256B, 1KiB, 4KiB, 16KiB, 64KiB, 256KiB, 1MiB, 4MiB
+		Buckets: prometheus.ExponentialBuckets(256, 4, 8),
+	},
+	cloudeventsSizeMetricsLabels,
+)
+
+// The cloudevents receive to handle duration metric is a histogram with a base metric name of
+// 'receive_to_handle_duration_seconds', it observes the duration from receiving an event to the time right before the
+// resource handlers are invoked with the resource of the event, so it excludes the time spent in the handlers.
+// For example, a resource of the manifestbundles type is handled 0.1s after its event is received by the CloudEvents
+// client with client_id=client1 would result in the following metrics:
+// cloudevents_receive_to_handle_duration_seconds_bucket{client_id="client1",type="io.open-cluster-management.works.v1alpha1.manifestbundles",le="0.1"} 1
+// cloudevents_receive_to_handle_duration_seconds_sum{client_id="client1",type="io.open-cluster-management.works.v1alpha1.manifestbundles"} 0.1
+// cloudevents_receive_to_handle_duration_seconds_count{client_id="client1",type="io.open-cluster-management.works.v1alpha1.manifestbundles"} 1
+var receiveToHandleDurationMetric = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Subsystem: cloudeventsMetricsSubsystem,
+		Name:      receiveToHandleMetric,
+		Help:      "The duration from receiving a CloudEvent to handling its resource in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	cloudeventsClientDataTypeMetricsLabels,
+)
+
+// The cloudevents decode failures counter metric is a counter with a base metric name of 'decode_failures_total', the
+// reason is the stage that the received event fails in, e.g. parse, codec, decode, verify and decrypt.
+// For example, 2 received events of the manifestbundles type fail to be decoded by the CloudEvents client with
+// client_id=client1 would result in the following metrics:
+// cloudevents_decode_failures_total{client_id="client1",type="io.open-cluster-management.works.v1alpha1.manifestbundles",reason="decode"} 2
+var decodeFailuresCounterMetric = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Subsystem: cloudeventsMetricsSubsystem,
+		Name:      decodeFailuresCounter,
+		Help:      "The total number of received CloudEvents that fail to be decoded.",
+	},
+	cloudeventsFailureMetricsLabels,
+)
+
+// The cloudevents handler failures counter metric is a counter with a base metric name of 'handler_failures_total',
+// the reason is retrying if the failed handlers will be retried, otherwise it's failed.
+// For example, a resource of the manifestbundles type fails to be handled by the CloudEvents client with
+// client_id=client1 and it's retried would result in the following metrics:
+// cloudevents_handler_failures_total{client_id="client1",type="io.open-cluster-management.works.v1alpha1.manifestbundles",reason="retrying"} 1
+var handlerFailuresCounterMetric = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Subsystem: cloudeventsMetricsSubsystem,
+		Name:      handlerFailuresCounter,
+		Help:      "The total number of received resources that fail to be handled.",
+	},
+	cloudeventsFailureMetricsLabels,
+)
+
+// The cloudevents publish failures counter metric is a counter with a base metric name of 'publish_failures_total',
+// the reason is the step that the event fails in, e.g. prepare, rate_limit, context, not_ready and send.
+// For example, an event of the manifestbundles type fails to be sent by the CloudEvents client with
+// client_id=client1 would result in the following metrics:
+// cloudevents_publish_failures_total{client_id="client1",type="io.open-cluster-management.works.v1alpha1.manifestbundles",reason="send"} 1
+var publishFailuresCounterMetric = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Subsystem: cloudeventsMetricsSubsystem,
+		Name:      publishFailuresCounter,
+		Help:      "The total number of CloudEvents that fail to be published.",
+	},
+	cloudeventsFailureMetricsLabels,
+)
+
+// The cloudevents in-flight handlers metric is a gauge with a base metric name of 'inflight_handlers'.
+// For example, 2 resources are being handled by the CloudEvents client with client_id=client1 would result in the
+// following metrics:
+// cloudevents_inflight_handlers{client_id="client1"} 2
+var inflightHandlersGaugeMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Subsystem: cloudeventsMetricsSubsystem,
+		Name:      inflightHandlersGauge,
+		Help:      "The number of received resources that are being handled by the CloudEvents client.",
+	},
+	cloudeventsClientMetricsLabels,
+)
+
+// The received queue depth metric is a gauge with a base metric name of 'received_queue_depth'.
+// For example, 2 received resources are waiting to be processed in the queue local-watcher-store would result in the
+// following metrics:
+// resources_received_queue_depth{queue="local-watcher-store"} 2
+var receivedQueueDepthGaugeMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Subsystem: resourcesMetricsSubsystem,
+		Name:      receivedQueueDepthGauge,
+		Help:      "The number of received resources that are waiting to be processed by the watcher store.",
+	},
+	receivedQueueMetricsLabels,
+)
+
 // Register the metrics:
 func RegisterCloudEventsMetrics(register prometheus.Registerer) {
 	register.MustRegister(cloudeventsReceivedCounterMetric)
@@ -174,6 +484,21 @@ func RegisterCloudEventsMetrics(register prometheus.Registerer) {
 	register.MustRegister(resourceSpecResyncDurationMetric)
 	register.MustRegister(resourceStatusResyncDurationMetric)
 	register.MustRegister(clientReconnectedCounterMetric)
+	register.MustRegister(outboxDepthGaugeMetric)
+	register.MustRegister(outboxDroppedCounterMetric)
+	register.MustRegister(agentHeartbeatLastSeenMetric)
+	register.MustRegister(agentAliveMetric)
+	register.MustRegister(dedupCacheHitsCounterMetric)
+	register.MustRegister(dedupCacheMissesCounterMetric)
+	register.MustRegister(eventPublishDurationMetric)
+	register.MustRegister(rateLimiterWaitDurationMetric)
+	register.MustRegister(eventSizeHistogramMetric)
+	register.MustRegister(receiveToHandleDurationMetric)
+	register.MustRegister(decodeFailuresCounterMetric)
+	register.MustRegister(handlerFailuresCounterMetric)
+	register.MustRegister(publishFailuresCounterMetric)
+	register.MustRegister(inflightHandlersGaugeMetric)
+	register.MustRegister(receivedQueueDepthGaugeMetric)
 	register.MustRegister(workProcessedCounterMetric)
 }
 
@@ -181,9 +506,24 @@ func RegisterCloudEventsMetrics(register prometheus.Registerer) {
 func UnregisterCloudEventsMetrics(register prometheus.Registerer) {
 	register.Unregister(cloudeventsReceivedCounterMetric)
 	register.Unregister(cloudeventsSentCounterMetric)
-	register.Unregister(resourceStatusResyncDurationMetric)
+	register.Unregister(resourceSpecResyncDurationMetric)
 	register.Unregister(resourceStatusResyncDurationMetric)
 	register.Unregister(clientReconnectedCounterMetric)
+	register.Unregister(outboxDepthGaugeMetric)
+	register.Unregister(outboxDroppedCounterMetric)
+	register.Unregister(agentHeartbeatLastSeenMetric)
+	register.Unregister(agentAliveMetric)
+	register.Unregister(dedupCacheHitsCounterMetric)
+	register.Unregister(dedupCacheMissesCounterMetric)
+	register.Unregister(eventPublishDurationMetric)
+	register.Unregister(rateLimiterWaitDurationMetric)
+	register.Unregister(eventSizeHistogramMetric)
+	register.Unregister(receiveToHandleDurationMetric)
+	register.Unregister(decodeFailuresCounterMetric)
+	register.Unregister(handlerFailuresCounterMetric)
+	register.Unregister(publishFailuresCounterMetric)
+	register.Unregister(inflightHandlersGaugeMetric)
+	register.Unregister(receivedQueueDepthGaugeMetric)
 	register.Unregister(workProcessedCounterMetric)
 }
 
@@ -194,6 +534,21 @@ func ResetCloudEventsMetrics() {
 	resourceSpecResyncDurationMetric.Reset()
 	resourceStatusResyncDurationMetric.Reset()
 	clientReconnectedCounterMetric.Reset()
+	outboxDepthGaugeMetric.Reset()
+	outboxDroppedCounterMetric.Reset()
+	agentHeartbeatLastSeenMetric.Reset()
+	agentAliveMetric.Reset()
+	dedupCacheHitsCounterMetric.Reset()
+	dedupCacheMissesCounterMetric.Reset()
+	eventPublishDurationMetric.Reset()
+	rateLimiterWaitDurationMetric.Reset()
+	eventSizeHistogramMetric.Reset()
+	receiveToHandleDurationMetric.Reset()
+	decodeFailuresCounterMetric.Reset()
+	handlerFailuresCounterMetric.Reset()
+	publishFailuresCounterMetric.Reset()
+	inflightHandlersGaugeMetric.Reset()
+	receivedQueueDepthGaugeMetric.Reset()
 	workProcessedCounterMetric.Reset()
 }
 
@@ -247,6 +602,62 @@ func increaseClientReconnectedCounter(clientID string) {
 	clientReconnectedCounterMetric.With(labels).Inc()
 }
 
+// updateOutboxDepthMetric updates the outbox depth metric:
+func updateOutboxDepthMetric(clientID string, depth int) {
+	labels := prometheus.Labels{
+		metricsClientIDLabel: clientID,
+	}
+	outboxDepthGaugeMetric.With(labels).Set(float64(depth))
+}
+
+// increaseOutboxDroppedCounter increases the outbox dropped counter metric:
+func increaseOutboxDroppedCounter(clientID string) {
+	labels := prometheus.Labels{
+		metricsClientIDLabel: clientID,
+	}
+	outboxDroppedCounterMetric.With(labels).Inc()
+}
+
+// updateAgentHeartbeatMetrics updates the agent heartbeat last seen and alive metrics:
+func updateAgentHeartbeatMetrics(source, cluster string, lastSeen time.Time, alive bool) {
+	labels := prometheus.Labels{
+		metricsSourceLabel:  source,
+		metricsClusterLabel: cluster,
+	}
+	agentHeartbeatLastSeenMetric.With(labels).Set(float64(lastSeen.Unix()))
+	if alive {
+		agentAliveMetric.With(labels).Set(1)
+		return
+	}
+	agentAliveMetric.With(labels).Set(0)
+}
+
+// deleteAgentHeartbeatMetrics deletes the agent heartbeat last seen and alive metrics of a removed agent:
+func deleteAgentHeartbeatMetrics(source, cluster string) {
+	labels := prometheus.Labels{
+		metricsSourceLabel:  source,
+		metricsClusterLabel: cluster,
+	}
+	agentHeartbeatLastSeenMetric.Delete(labels)
+	agentAliveMetric.Delete(labels)
+}
+
+// increaseDedupCacheHitsCounter increases the dedup cache hits counter metric:
+func increaseDedupCacheHitsCounter(clientID string) {
+	labels := prometheus.Labels{
+		metricsClientIDLabel: clientID,
+	}
+	dedupCacheHitsCounterMetric.With(labels).Inc()
+}
+
+// increaseDedupCacheMissesCounter increases the dedup cache misses counter metric:
+func increaseDedupCacheMissesCounter(clientID string) {
+	labels := prometheus.Labels{
+		metricsClientIDLabel: clientID,
+	}
+	dedupCacheMissesCounterMetric.With(labels).Inc()
+}
+
 // IncreaseWorkProcessedCounter increases the work processed counter metric:
 func IncreaseWorkProcessedCounter(action, code string) {
 	labels := prometheus.Labels{
@@ -255,3 +666,95 @@ func IncreaseWorkProcessedCounter(action, code string) {
 	}
 	workProcessedCounterMetric.With(labels).Inc()
 }
+
+// observePublishDuration observes the publish duration metric:
+func observePublishDuration(clientID string, evt cloudevents.Event, startTime time.Time) {
+	labels := prometheus.Labels{
+		metricsClientIDLabel: clientID,
+		metricsDataTypeLabel: dataTypeOf(evt),
+	}
+	eventPublishDurationMetric.With(labels).Observe(time.Since(startTime).Seconds())
+}
+
+// observeRateLimiterWaitDuration observes the rate limiter wait duration metric:
+func observeRateLimiterWaitDuration(clientID string, priority eventPriority, duration time.Duration) {
+	labels := prometheus.Labels{
+		metricsClientIDLabel: clientID,
+		metricsPriorityLabel: priority.String(),
+	}
+	rateLimiterWaitDurationMetric.With(labels).Observe(duration.Seconds())
+}
+
+// observeEventSize observes the event size metric:
+func observeEventSize(clientID string, evt cloudevents.Event, direction string) {
+	labels := prometheus.Labels{
+		metricsClientIDLabel:  clientID,
+		metricsDataTypeLabel:  dataTypeOf(evt),
+		metricsDirectionLabel: direction,
+	}
+	eventSizeHistogramMetric.With(labels).Observe(float64(len(evt.Data())))
+}
+
+// observeReceiveToHandleDuration observes the receive to handle duration metric:
+func observeReceiveToHandleDuration(clientID string, evt cloudevents.Event, receivedTime time.Time) {
+	labels := prometheus.Labels{
+		metricsClientIDLabel: clientID,
+		metricsDataTypeLabel: dataTypeOf(evt),
+	}
+	receiveToHandleDurationMetric.With(labels).Observe(time.Since(receivedTime).Seconds())
+}
+
+// increaseDecodeFailuresCounter increases the decode failures counter metric:
+func increaseDecodeFailuresCounter(clientID string, evt cloudevents.Event, reason string) {
+	labels := prometheus.Labels{
+		metricsClientIDLabel: clientID,
+		metricsDataTypeLabel: dataTypeOf(evt),
+		metricsReasonLabel:   reason,
+	}
+	decodeFailuresCounterMetric.With(labels).Inc()
+}
+
+// increaseHandlerFailuresCounter increases the handler failures counter metric:
+func increaseHandlerFailuresCounter(clientID string, evt cloudevents.Event, reason string) {
+	labels := prometheus.Labels{
+		metricsClientIDLabel: clientID,
+		metricsDataTypeLabel: dataTypeOf(evt),
+		metricsReasonLabel:   reason,
+	}
+	handlerFailuresCounterMetric.With(labels).Inc()
+}
+
+// increasePublishFailuresCounter increases the publish failures counter metric:
+func increasePublishFailuresCounter(clientID string, evt cloudevents.Event, reason string) {
+	labels := prometheus.Labels{
+		metricsClientIDLabel: clientID,
+		metricsDataTypeLabel: dataTypeOf(evt),
+		metricsReasonLabel:   reason,
+	}
+	publishFailuresCounterMetric.With(labels).Inc()
+}
+
+// addInflightHandlers adds the delta to the in-flight handlers metric:
+func addInflightHandlers(clientID string, delta int) {
+	labels := prometheus.Labels{
+		metricsClientIDLabel: clientID,
+	}
+	inflightHandlersGaugeMetric.With(labels).Add(float64(delta))
+}
+
+// UpdateReceivedQueueDepthMetric updates the received queue depth metric:
+func UpdateReceivedQueueDepthMetric(queue string, depth int) {
+	labels := prometheus.Labels{
+		metricsQueueLabel: queue,
+	}
+	receivedQueueDepthGaugeMetric.With(labels).Set(float64(depth))
+}
+
+// dataTypeOf returns the data type of an event for the metrics, it's empty if the type of the event is unknown.
+func dataTypeOf(evt cloudevents.Event) string {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return ""
+	}
+	return eventType.CloudEventsDataType.String()
+}
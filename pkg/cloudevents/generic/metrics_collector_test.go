@@ -12,6 +12,7 @@ import (
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
 	kubetypes "k8s.io/apimachinery/pkg/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
@@ -168,6 +169,19 @@ func toFloat64Counter(c prometheus.Counter) float64 {
 	panic(fmt.Errorf("collected a non-counter metric: %s", pb))
 }
 
+// toFloat64Gauge returns the value of a gauge metric
+func toFloat64Gauge(g prometheus.Gauge) float64 {
+	pb := &dto.Metric{}
+	if err := g.Write(pb); err != nil {
+		panic(fmt.Errorf("metric write failed, err=%v", err))
+	}
+
+	if pb.Gauge != nil {
+		return pb.Gauge.GetValue()
+	}
+	panic(fmt.Errorf("collected a non-gauge metric: %s", pb))
+}
+
 func TestResyncMetrics(t *testing.T) {
 	cases := []struct {
 		name        string
@@ -305,3 +319,92 @@ func toFloat64HistCountAndSum(h prometheus.Observer) (uint64, float64) {
 	}
 	panic(fmt.Errorf("collected a non-histogram metric: %s", pb))
 }
+
+func TestPublishAndReceiveMetrics(t *testing.T) {
+	// reset metrics
+	ResetCloudEventsMetrics()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sendReceiver := gochan.New()
+	sourceOptions := fake.NewSourceOptions(sendReceiver, "source1")
+	source, err := NewCloudEventSourceClient[*mockResource](
+		ctx, sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	agentOptions := fake.NewAgentOptions(sendReceiver, nil, "cluster1", testAgentName)
+	agent, err := NewCloudEventAgentClient[*mockResource](
+		ctx, agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+	agent.Subscribe(ctx, func(action types.ResourceAction, obj *mockResource) error {
+		time.Sleep(500 * time.Millisecond)
+		return fmt.Errorf("failed to handle %s", obj.UID)
+	})
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	err = source.Publish(ctx, eventType, &mockResource{Namespace: "cluster1", UID: "test1", ResourceVersion: "1"})
+	require.NoError(t, err)
+
+	// wait 1 second for agent receive the resource
+	time.Sleep(time.Second)
+
+	count, _ := toFloat64HistCountAndSum(eventPublishDurationMetric.WithLabelValues("source1", mockEventDataType.String()))
+	require.Equal(t, uint64(1), count)
+	count, _ = toFloat64HistCountAndSum(rateLimiterWaitDurationMetric.WithLabelValues("source1", "change"))
+	require.Equal(t, uint64(1), count)
+	count, _ = toFloat64HistCountAndSum(
+		eventSizeHistogramMetric.WithLabelValues("source1", mockEventDataType.String(), eventDirectionSent))
+	require.Equal(t, uint64(1), count)
+	count, _ = toFloat64HistCountAndSum(
+		eventSizeHistogramMetric.WithLabelValues(testAgentName, mockEventDataType.String(), eventDirectionReceived))
+	require.Equal(t, uint64(1), count)
+	count, sum := toFloat64HistCountAndSum(
+		receiveToHandleDurationMetric.WithLabelValues(testAgentName, mockEventDataType.String()))
+	require.Equal(t, uint64(1), count)
+	// the time spent in the handler is not observed
+	require.Less(t, sum, 0.5)
+
+	// the retry is disabled, the failure is reported immediately
+	handlerFailures := handlerFailuresCounterMetric.WithLabelValues(
+		testAgentName, mockEventDataType.String(), handlerFailureReasonFailed)
+	require.Equal(t, 1.0, toFloat64Counter(handlerFailures))
+	require.Equal(t, 0.0, toFloat64Gauge(inflightHandlersGaugeMetric.WithLabelValues(testAgentName)))
+}
+
+func TestFailureMetrics(t *testing.T) {
+	// reset metrics
+	ResetCloudEventsMetrics()
+	client := &baseClient{clientID: "client1"}
+
+	evt := types.NewEventBuilder("source1", types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}).NewEvent()
+
+	client.deadLetter(context.TODO(), evt, options.DeadLetterStageDecode, fmt.Errorf("failed to decode"))
+	// the handle failures are not decode failures
+	client.deadLetter(context.TODO(), evt, options.DeadLetterStageHandle, fmt.Errorf("failed to handle"))
+	decodeFailures := decodeFailuresCounterMetric.WithLabelValues("client1", mockEventDataType.String(), "decode")
+	require.Equal(t, 1.0, toFloat64Counter(decodeFailures))
+	handleFailures := decodeFailuresCounterMetric.WithLabelValues("client1", mockEventDataType.String(), "handle")
+	require.Equal(t, 0.0, toFloat64Counter(handleFailures))
+
+	// the client is not ready
+	require.Error(t, client.sendWithContext(context.TODO(), evt))
+	publishFailures := publishFailuresCounterMetric.WithLabelValues(
+		"client1", mockEventDataType.String(), publishFailureReasonNotReady)
+	require.Equal(t, 1.0, toFloat64Counter(publishFailures))
+
+	// the event type is unknown
+	heartbeat := types.NewEventBuilder("agent1", types.HeartbeatEventType).NewEvent()
+	require.Equal(t, types.HeartbeatEventDataType.String(), dataTypeOf(heartbeat))
+	require.Empty(t, dataTypeOf(cloudevents.NewEvent()))
+
+	UpdateReceivedQueueDepthMetric("test-queue", 3)
+	require.Equal(t, 3.0, toFloat64Gauge(receivedQueueDepthGaugeMetric.WithLabelValues("test-queue")))
+}
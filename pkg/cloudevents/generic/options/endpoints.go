@@ -0,0 +1,173 @@
+package options
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultSwitchBackInterval is the default interval to probe the primary endpoint when a client is connected to
+	// a standby endpoint.
+	DefaultSwitchBackInterval = 30 * time.Second
+
+	// defaultProbeTimeout is the timeout to probe an endpoint.
+	defaultProbeTimeout = 5 * time.Second
+)
+
+// EndpointSelectionPolicy is the policy to select an endpoint from a list of endpoints to connect.
+type EndpointSelectionPolicy string
+
+const (
+	// EndpointSelectionFailover connects to the endpoints in their configured order, the first endpoint is the
+	// primary. If the client is connected to a standby endpoint, the primary endpoint is probed periodically and the
+	// client switches back to the primary endpoint once it's healthy.
+	EndpointSelectionFailover EndpointSelectionPolicy = "Failover"
+
+	// EndpointSelectionRoundRobin connects to the next endpoint of the list when the current endpoint fails, the
+	// client stays on the endpoint that it's connected to.
+	EndpointSelectionRoundRobin EndpointSelectionPolicy = "RoundRobin"
+)
+
+// EndpointSelector selects an endpoint from a list of endpoints for a transport to connect, the endpoint that fails
+// to be connected is skipped in the next connection attempt.
+type EndpointSelector struct {
+	sync.Mutex
+
+	endpoints          []string
+	policy             EndpointSelectionPolicy
+	switchBackInterval time.Duration
+	current            int
+	watching           bool
+	probe              func(endpoint string) error
+}
+
+// NewEndpointSelector returns an EndpointSelector with the given endpoints, the endpoints must not be empty. If the
+// policy is empty, the EndpointSelectionFailover is used. If the switch back interval is less than or equal to zero,
+// the DefaultSwitchBackInterval is used.
+func NewEndpointSelector(endpoints []string, policy EndpointSelectionPolicy,
+	switchBackInterval time.Duration) (*EndpointSelector, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("at least one endpoint is required")
+	}
+
+	switch policy {
+	case "":
+		policy = EndpointSelectionFailover
+	case EndpointSelectionFailover, EndpointSelectionRoundRobin:
+	default:
+		return nil, fmt.Errorf("unsupported endpoint selection policy %s", policy)
+	}
+
+	if switchBackInterval <= 0 {
+		switchBackInterval = DefaultSwitchBackInterval
+	}
+
+	return &EndpointSelector{
+		endpoints:          endpoints,
+		policy:             policy,
+		switchBackInterval: switchBackInterval,
+		probe:              probeTCP,
+	}, nil
+}
+
+// Endpoints returns all of the endpoints.
+func (s *EndpointSelector) Endpoints() []string {
+	return s.endpoints
+}
+
+// Current returns the endpoint to connect.
+func (s *EndpointSelector) Current() string {
+	s.Lock()
+	defer s.Unlock()
+	return s.endpoints[s.current]
+}
+
+// Failed records the endpoint fails to be connected or its connection is broken, the next endpoint of the list
+// will be connected next time.
+func (s *EndpointSelector) Failed(endpoint string) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.endpoints[s.current] != endpoint {
+		// the endpoint is already switched
+		return
+	}
+
+	s.current = (s.current + 1) % len(s.endpoints)
+	klog.Warningf("the endpoint %s is failed, switch to the endpoint %s", endpoint, s.endpoints[s.current])
+}
+
+// WatchPrimary probes the primary endpoint periodically if the current endpoint is a standby endpoint of the
+// failover policy. Once the primary endpoint is healthy, the primary endpoint is selected and the switchBack is
+// called to reconnect the client. Only one watcher is running at the same time.
+func (s *EndpointSelector) WatchPrimary(ctx context.Context, switchBack func()) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.policy != EndpointSelectionFailover || s.current == 0 || s.watching {
+		return
+	}
+
+	s.watching = true
+	go func() {
+		defer func() {
+			s.Lock()
+			s.watching = false
+			s.Unlock()
+		}()
+
+		ticker := time.NewTicker(s.switchBackInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if s.Current() == s.endpoints[0] {
+					// the client is reconnected to the primary endpoint
+					return
+				}
+
+				if s.switchBackToPrimary() {
+					switchBack()
+					return
+				}
+			}
+		}
+	}()
+}
+
+// switchBackToPrimary selects the primary endpoint if it's healthy, it returns false if the primary endpoint is
+// unhealthy or already selected.
+func (s *EndpointSelector) switchBackToPrimary() bool {
+	primary := s.endpoints[0]
+	if err := s.probe(primary); err != nil {
+		klog.V(4).Infof("the primary endpoint %s is unhealthy, %v", primary, err)
+		return false
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.current == 0 {
+		return false
+	}
+
+	klog.Infof("the primary endpoint %s is healthy, switch back from the endpoint %s", primary, s.endpoints[s.current])
+	s.current = 0
+	return true
+}
+
+func probeTCP(endpoint string) error {
+	conn, err := net.DialTimeout("tcp", endpoint, defaultProbeTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,153 @@
+package options
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestNewEndpointSelector(t *testing.T) {
+	cases := []struct {
+		name             string
+		endpoints        []string
+		policy           EndpointSelectionPolicy
+		expectedPolicy   EndpointSelectionPolicy
+		expectedErrorMsg string
+	}{
+		{
+			name:             "no endpoints",
+			expectedErrorMsg: "at least one endpoint is required",
+		},
+		{
+			name:             "unsupported policy",
+			endpoints:        []string{"a", "b"},
+			policy:           "Random",
+			expectedErrorMsg: "unsupported endpoint selection policy Random",
+		},
+		{
+			name:           "default policy",
+			endpoints:      []string{"a", "b"},
+			expectedPolicy: EndpointSelectionFailover,
+		},
+		{
+			name:           "round robin policy",
+			endpoints:      []string{"a", "b"},
+			policy:         EndpointSelectionRoundRobin,
+			expectedPolicy: EndpointSelectionRoundRobin,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			selector, err := NewEndpointSelector(c.endpoints, c.policy, 0)
+			if len(c.expectedErrorMsg) != 0 {
+				if err == nil || err.Error() != c.expectedErrorMsg {
+					t.Errorf("expected error %q, but got %v", c.expectedErrorMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if selector.policy != c.expectedPolicy {
+				t.Errorf("expected policy %s, but got %s", c.expectedPolicy, selector.policy)
+			}
+			if selector.switchBackInterval != DefaultSwitchBackInterval {
+				t.Errorf("expected switch back interval %v, but got %v", DefaultSwitchBackInterval, selector.switchBackInterval)
+			}
+			if selector.Current() != c.endpoints[0] {
+				t.Errorf("expected the first endpoint is selected, but got %s", selector.Current())
+			}
+		})
+	}
+}
+
+func TestEndpointFailed(t *testing.T) {
+	selector, err := NewEndpointSelector([]string{"a", "b", "c"}, EndpointSelectionRoundRobin, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	selector.Failed("a")
+	if selector.Current() != "b" {
+		t.Errorf("expected endpoint b, but got %s", selector.Current())
+	}
+
+	// the endpoint is already switched
+	selector.Failed("a")
+	if selector.Current() != "b" {
+		t.Errorf("expected endpoint b, but got %s", selector.Current())
+	}
+
+	selector.Failed("b")
+	selector.Failed("c")
+	if selector.Current() != "a" {
+		t.Errorf("expected endpoint a, but got %s", selector.Current())
+	}
+}
+
+func TestWatchPrimary(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	primaryHealthy := make(chan struct{})
+	selector, err := NewEndpointSelector([]string{"primary", "standby"}, EndpointSelectionFailover, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	selector.probe = func(endpoint string) error {
+		select {
+		case <-primaryHealthy:
+			return nil
+		default:
+			return fmt.Errorf("%s is unavailable", endpoint)
+		}
+	}
+
+	switchedBack := make(chan struct{})
+	switchBack := func() {
+		close(switchedBack)
+	}
+
+	// the client is on the primary endpoint, there is nothing to watch
+	selector.WatchPrimary(ctx, switchBack)
+	if selector.watching {
+		t.Errorf("expected no watcher on the primary endpoint")
+	}
+
+	selector.Failed("primary")
+	selector.WatchPrimary(ctx, switchBack)
+	// only one watcher is running
+	selector.WatchPrimary(ctx, switchBack)
+
+	time.Sleep(50 * time.Millisecond)
+	if selector.Current() != "standby" {
+		t.Errorf("expected endpoint standby, but got %s", selector.Current())
+	}
+
+	close(primaryHealthy)
+	select {
+	case <-switchedBack:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout to switch back to the primary endpoint")
+	}
+
+	if selector.Current() != "primary" {
+		t.Errorf("expected endpoint primary, but got %s", selector.Current())
+	}
+}
+
+func TestWatchPrimaryWithRoundRobin(t *testing.T) {
+	selector, err := NewEndpointSelector([]string{"a", "b"}, EndpointSelectionRoundRobin, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	selector.Failed("a")
+	selector.WatchPrimary(context.TODO(), func() {})
+	if selector.watching {
+		t.Errorf("expected no watcher for the round robin policy")
+	}
+}
@@ -27,12 +27,24 @@ type GRPCOptions struct {
 	ClientCertFile string
 	ClientKeyFile  string
 	TokenFile      string
+
+	// Endpoints selects a server from multiple servers to connect, if it's set, the URL is ignored.
+	Endpoints *options.EndpointSelector
 }
 
 // GRPCConfig holds the information needed to build connect to gRPC server as a given user.
 type GRPCConfig struct {
 	// URL is the address of the gRPC server (host:port).
 	URL string `json:"url" yaml:"url"`
+	// URLs are the addresses of the standby gRPC servers (host:port), they are connected after the URL with the
+	// EndpointSelectionPolicy when the current server is unavailable.
+	URLs []string `json:"urls,omitempty" yaml:"urls,omitempty"`
+	// EndpointSelectionPolicy is the policy to select a server when there are multiple servers, it's Failover or
+	// RoundRobin, by default is Failover.
+	EndpointSelectionPolicy options.EndpointSelectionPolicy `json:"endpointSelectionPolicy,omitempty" yaml:"endpointSelectionPolicy,omitempty"`
+	// SwitchBackInterval is the interval to probe the primary server when the client is connected to a standby server
+	// with the Failover policy, by default is 30s.
+	SwitchBackInterval *time.Duration `json:"switchBackInterval,omitempty" yaml:"switchBackInterval,omitempty"`
 	// CAFile is the file path to a cert file for the gRPC server certificate authority.
 	CAFile string `json:"caFile,omitempty" yaml:"caFile,omitempty"`
 	// ClientCertFile is the file path to a client cert file for TLS.
@@ -55,7 +67,11 @@ func BuildGRPCOptionsFromFlags(configPath string) (*GRPCOptions, error) {
 		return nil, err
 	}
 
-	if config.URL == "" {
+	urls := config.URLs
+	if config.URL != "" {
+		urls = append([]string{config.URL}, urls...)
+	}
+	if len(urls) == 0 {
 		return nil, fmt.Errorf("url is required")
 	}
 
@@ -70,12 +86,27 @@ func BuildGRPCOptionsFromFlags(configPath string) (*GRPCOptions, error) {
 		return nil, fmt.Errorf("setting tokenFile requires caFile")
 	}
 
+	// the server is selected from multiple servers if there are standby servers
+	var endpoints *options.EndpointSelector
+	if len(urls) > 1 {
+		switchBackInterval := options.DefaultSwitchBackInterval
+		if config.SwitchBackInterval != nil {
+			switchBackInterval = *config.SwitchBackInterval
+		}
+
+		endpoints, err = options.NewEndpointSelector(urls, config.EndpointSelectionPolicy, switchBackInterval)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &GRPCOptions{
-		URL:            config.URL,
+		URL:            urls[0],
 		CAFile:         config.CAFile,
 		ClientCertFile: config.ClientCertFile,
 		ClientKeyFile:  config.ClientKeyFile,
 		TokenFile:      config.TokenFile,
+		Endpoints:      endpoints,
 	}, nil
 }
 
@@ -84,6 +115,11 @@ func NewGRPCOptions() *GRPCOptions {
 }
 
 func (o *GRPCOptions) GetGRPCClientConn() (*grpc.ClientConn, error) {
+	url := o.URL
+	if o.Endpoints != nil {
+		url = o.Endpoints.Current()
+	}
+
 	if len(o.CAFile) != 0 {
 		certPool, err := x509.SystemCertPool()
 		if err != nil {
@@ -137,18 +173,18 @@ func (o *GRPCOptions) GetGRPCClientConn() (*grpc.ClientConn, error) {
 		}
 
 		// Establish a connection to the gRPC server.
-		conn, err := grpc.Dial(o.URL, diaOpts...)
+		conn, err := grpc.Dial(url, diaOpts...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to connect to grpc server %s, %v", o.URL, err)
+			return nil, fmt.Errorf("failed to connect to grpc server %s, %v", url, err)
 		}
 
 		return conn, nil
 	}
 
 	// Insecure connection option; should not be used in production.
-	conn, err := grpc.Dial(o.URL, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(url, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to grpc server %s, %v", o.URL, err)
+		return nil, fmt.Errorf("failed to connect to grpc server %s, %v", url, err)
 	}
 
 	return conn, nil
@@ -176,6 +212,10 @@ func (o *GRPCOptions) GetCloudEventsProtocol(ctx context.Context, errorHandler f
 				// For a connected grpc client, if the connections is down, the grpc client connection state will be
 				// changed from Ready to Idle.
 				if connState == connectivity.TransientFailure || connState == connectivity.Idle {
+					if o.Endpoints != nil {
+						// connect to the next server in the reconnection
+						o.Endpoints.Failed(conn.Target())
+					}
 					errorHandler(fmt.Errorf("grpc connection is disconnected (state=%s)", connState))
 					ticker.Stop()
 					conn.Close()
@@ -187,5 +227,17 @@ func (o *GRPCOptions) GetCloudEventsProtocol(ctx context.Context, errorHandler f
 
 	opts := []protocol.Option{}
 	opts = append(opts, clientOpts...)
-	return protocol.NewProtocol(conn, opts...)
+	p, err := protocol.NewProtocol(conn, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Endpoints != nil {
+		// reconnect the client to the primary server once it's healthy again
+		o.Endpoints.WatchPrimary(ctx, func() {
+			errorHandler(fmt.Errorf("switch back to the primary grpc server %s", o.Endpoints.Current()))
+		})
+	}
+
+	return p, nil
 }
@@ -35,6 +35,11 @@ func TestBuildGRPCOptionsFromFlags(t *testing.T) {
 			config:           "{\"url\":\"test\",\"tokenFile\":\"test\"}",
 			expectedErrorMsg: "setting tokenFile requires caFile",
 		},
+		{
+			name:             "unsupported endpoint selection policy",
+			config:           "{\"url\":\"test\",\"urls\":[\"standby\"],\"endpointSelectionPolicy\":\"Random\"}",
+			expectedErrorMsg: "unsupported endpoint selection policy Random",
+		},
 		{
 			name:   "customized options",
 			config: "{\"url\":\"test\"}",
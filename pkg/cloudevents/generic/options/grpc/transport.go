@@ -0,0 +1,45 @@
+package grpc
+
+import (
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/constants"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+func init() {
+	options.RegisterTransport(constants.ConfigTypeGRPC, func(configPath string) (options.TransportConfig, error) {
+		grpcOptions, err := BuildGRPCOptionsFromFlags(configPath)
+		if err != nil {
+			return nil, err
+		}
+		return grpcOptions, nil
+	})
+}
+
+// maxInflightEvents is the maximum number of the events that are published at the same time, the gRPC calls are
+// multiplexed on one connection, so an event is published without waiting for the previous calls to return.
+const maxInflightEvents = 10
+
+var _ options.PipelinedSender = &gRPCSourceOptions{}
+var _ options.PipelinedSender = &grpcAgentOptions{}
+
+var _ options.TransportConfig = &GRPCOptions{}
+
+// ServerAddress returns the URL of the gRPC server.
+func (o *GRPCOptions) ServerAddress() string {
+	return o.URL
+}
+
+// SourceOptions builds the cloudevents source options based on gRPC, the client ID is not used.
+func (o *GRPCOptions) SourceOptions(clientID, sourceID string) (*options.CloudEventsSourceOptions, error) {
+	return NewSourceOptions(o, sourceID), nil
+}
+
+// AgentOptions builds the cloudevents agent options based on gRPC.
+func (o *GRPCOptions) AgentOptions(clusterName, clientID string) (*options.CloudEventsAgentOptions, error) {
+	return NewAgentOptions(o, clusterName, clientID), nil
+}
+
+// MaxInflightEvents returns the maximum number of the events that are published at the same time.
+func (o *GRPCOptions) MaxInflightEvents() int {
+	return maxInflightEvents
+}
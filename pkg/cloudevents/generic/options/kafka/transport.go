@@ -0,0 +1,51 @@
+package kafka
+
+import (
+	"fmt"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/constants"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+func init() {
+	options.RegisterTransport(constants.ConfigTypeKafka, func(configPath string) (options.TransportConfig, error) {
+		kafkaOptions, err := BuildKafkaOptionsFromFlags(configPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(kafkaOptions.ServerAddress()) == 0 {
+			return nil, fmt.Errorf("failed to get kafka bootstrap.servers from configMap")
+		}
+		return kafkaOptions, nil
+	})
+}
+
+var _ options.TransportConfig = &KafkaOptions{}
+
+// ServerAddress returns the bootstrap servers of the Kafka cluster.
+func (o *KafkaOptions) ServerAddress() string {
+	server, ok := o.ConfigMap["bootstrap.servers"].(string)
+	if !ok {
+		return ""
+	}
+	return server
+}
+
+// SourceOptions builds the cloudevents source options based on Kafka, the client ID is not used.
+func (o *KafkaOptions) SourceOptions(clientID, sourceID string) (*options.CloudEventsSourceOptions, error) {
+	sourceOptions := NewSourceOptions(o, sourceID)
+	if sourceOptions == nil {
+		return nil, fmt.Errorf("kafka is not enabled, try adding -tags=kafka to build")
+	}
+	return sourceOptions, nil
+}
+
+// AgentOptions builds the cloudevents agent options based on Kafka.
+func (o *KafkaOptions) AgentOptions(clusterName, clientID string) (*options.CloudEventsAgentOptions, error) {
+	agentOptions := NewAgentOptions(o, clusterName, clientID)
+	if agentOptions == nil {
+		return nil, fmt.Errorf("kafka is not enabled, try adding -tags=kafka to build")
+	}
+	return agentOptions, nil
+}
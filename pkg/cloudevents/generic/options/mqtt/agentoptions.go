@@ -8,6 +8,7 @@ import (
 	cloudeventsmqtt "github.com/cloudevents/sdk-go/protocol/mqtt_paho/v2"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cloudeventscontext "github.com/cloudevents/sdk-go/v2/context"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
 	"github.com/eclipse/paho.golang/paho"
 	"k8s.io/klog/v2"
 
@@ -57,8 +58,9 @@ func (o *mqttAgentOptions) WithContext(ctx context.Context, evtCtx cloudevents.E
 		return nil, err
 	}
 
-	// agent request to sync resource spec from all sources
-	if eventType.Action == types.ResyncRequestAction && originalSource == types.SourceAll {
+	// agent request to sync resource spec from all sources or publishes its heartbeat to all sources
+	if (eventType.Action == types.ResyncRequestAction || eventType.Action == types.HeartbeatAction) &&
+		originalSource == types.SourceAll {
 		if len(o.Topics.AgentBroadcast) == 0 {
 			klog.Warningf("the agent broadcast topic not set, fall back to the agent events topic")
 
@@ -76,6 +78,16 @@ func (o *mqttAgentOptions) WithContext(ctx context.Context, evtCtx cloudevents.E
 		return nil, err
 	}
 
+	// the agent events topic does not specify a source, the agent publishes the events to the topic of the
+	// original source of the event, e.g. the spec resync request of one known source
+	if topicSource == "+" {
+		source, err := cloudeventstypes.ToString(originalSource)
+		if err != nil {
+			return nil, err
+		}
+		topicSource = source
+	}
+
 	// agent publishes status events or spec resync events
 	eventsTopic := replaceLast(o.Topics.AgentEvents, "+", o.clusterName)
 	eventsTopic = replaceLast(eventsTopic, "+", topicSource)
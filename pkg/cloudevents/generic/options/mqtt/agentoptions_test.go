@@ -104,6 +104,23 @@ func TestAgentContext(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "heartbeat",
+			ctx:  context.TODO(),
+			event: func() cloudevents.Event {
+				evt := cloudevents.NewEvent()
+				evt.SetType(types.HeartbeatEventType.String())
+				evt.SetExtension("originalsource", types.SourceAll)
+				evt.SetExtension("clustername", "cluster1")
+				return evt
+			}(),
+			expectedTopic: "clusters/cluster1/agentbroadcast",
+			assertError: func(err error) {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+			},
+		},
 		{
 			name: "send status no original source",
 			ctx:  context.TODO(),
@@ -173,3 +190,46 @@ func TestAgentContext(t *testing.T) {
 		})
 	}
 }
+
+func TestAgentContextWithWildcardSource(t *testing.T) {
+	file, err := clienttesting.WriteToTempFile("mqtt-config-test-", []byte(`
+brokerHost: test
+topics:
+  sourceEvents: sources/+/clusters/+/sourceevents
+  agentEvents: sources/+/clusters/+/agentevents
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	options, err := BuildMQTTOptionsFromFlags(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agentOptions := &mqttAgentOptions{
+		MQTTOptions: *options,
+		clusterName: "cluster1",
+	}
+
+	// the spec resync request of one known source is published to the topic of the source
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ResyncRequestAction,
+	}
+	evt := cloudevents.NewEvent()
+	evt.SetType(eventType.String())
+	evt.SetExtension("originalsource", "hub2")
+	evt.SetExtension("clustername", "cluster1")
+
+	ctx, err := agentOptions.WithContext(context.TODO(), evt.Context)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if topic := cloudeventscontext.TopicFrom(ctx); topic != "sources/hub2/clusters/cluster1/agentevents" {
+		t.Errorf("unexpected topic %s", topic)
+	}
+}
@@ -35,14 +35,37 @@ type MQTTDialer struct {
 	BrokerHost string
 	Timeout    time.Duration
 
+	// Endpoints selects a broker from multiple brokers to connect, if it's set, the BrokerHost is ignored.
+	Endpoints *options.EndpointSelector
+
 	conn net.Conn
 }
 
 func (d *MQTTDialer) Dial() (net.Conn, error) {
+	if d.Endpoints == nil {
+		return d.dial(d.BrokerHost)
+	}
+
+	// try each broker once from the current one
+	errs := []error{}
+	for range d.Endpoints.Endpoints() {
+		brokerHost := d.Endpoints.Current()
+		conn, err := d.dial(brokerHost)
+		if err == nil {
+			return conn, nil
+		}
+
+		errs = append(errs, err)
+		d.Endpoints.Failed(brokerHost)
+	}
+	return nil, errors.NewAggregate(errs)
+}
+
+func (d *MQTTDialer) dial(brokerHost string) (net.Conn, error) {
 	if d.TLSConfig != nil {
-		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: d.Timeout}, "tcp", d.BrokerHost, d.TLSConfig)
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: d.Timeout}, "tcp", brokerHost, d.TLSConfig)
 		if err != nil {
-			return nil, fmt.Errorf("failed to connect to MQTT broker %s, %v", d.BrokerHost, err)
+			return nil, fmt.Errorf("failed to connect to MQTT broker %s, %v", brokerHost, err)
 		}
 
 		// ensure parallel writes are thread-Safe
@@ -50,9 +73,9 @@ func (d *MQTTDialer) Dial() (net.Conn, error) {
 		return d.conn, nil
 	}
 
-	conn, err := net.DialTimeout("tcp", d.BrokerHost, d.Timeout)
+	conn, err := net.DialTimeout("tcp", brokerHost, d.Timeout)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to MQTT broker %s, %v", d.BrokerHost, err)
+		return nil, fmt.Errorf("failed to connect to MQTT broker %s, %v", brokerHost, err)
 	}
 
 	// ensure parallel writes are thread-Safe
@@ -84,6 +107,18 @@ type MQTTConfig struct {
 	// BrokerHost is the host of the MQTT broker (hostname:port).
 	BrokerHost string `json:"brokerHost" yaml:"brokerHost"`
 
+	// BrokerHosts are the hosts of the standby MQTT brokers (hostname:port), they are connected after the BrokerHost
+	// with the EndpointSelectionPolicy when the current broker is unavailable.
+	BrokerHosts []string `json:"brokerHosts,omitempty" yaml:"brokerHosts,omitempty"`
+
+	// EndpointSelectionPolicy is the policy to select a broker when there are multiple brokers, it's Failover or
+	// RoundRobin, by default is Failover.
+	EndpointSelectionPolicy options.EndpointSelectionPolicy `json:"endpointSelectionPolicy,omitempty" yaml:"endpointSelectionPolicy,omitempty"`
+
+	// SwitchBackInterval is the interval to probe the primary broker when the client is connected to a standby broker
+	// with the Failover policy, by default is 30s
+	SwitchBackInterval *time.Duration `json:"switchBackInterval,omitempty" yaml:"switchBackInterval,omitempty"`
+
 	// Username is the username for basic authentication to connect the MQTT broker.
 	Username string `json:"username,omitempty" yaml:"username,omitempty"`
 	// Password is the password for basic authentication to connect the MQTT broker.
@@ -123,7 +158,11 @@ func BuildMQTTOptionsFromFlags(configPath string) (*MQTTOptions, error) {
 		return nil, err
 	}
 
-	if config.BrokerHost == "" {
+	brokerHosts := config.BrokerHosts
+	if config.BrokerHost != "" {
+		brokerHosts = append([]string{config.BrokerHost}, brokerHosts...)
+	}
+	if len(brokerHosts) == 0 {
 		return nil, fmt.Errorf("brokerHost is required")
 	}
 
@@ -136,6 +175,20 @@ func BuildMQTTOptionsFromFlags(configPath string) (*MQTTOptions, error) {
 		return nil, err
 	}
 
+	// the broker is selected from multiple brokers if there are standby brokers
+	var endpoints *options.EndpointSelector
+	if len(brokerHosts) > 1 {
+		switchBackInterval := options.DefaultSwitchBackInterval
+		if config.SwitchBackInterval != nil {
+			switchBackInterval = *config.SwitchBackInterval
+		}
+
+		endpoints, err = options.NewEndpointSelector(brokerHosts, config.EndpointSelectionPolicy, switchBackInterval)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	options := &MQTTOptions{
 		Username:  config.Username,
 		Password:  config.Password,
@@ -176,9 +229,10 @@ func BuildMQTTOptionsFromFlags(configPath string) (*MQTTOptions, error) {
 		}
 
 		options.Dialer = &MQTTDialer{
-			BrokerHost: config.BrokerHost,
+			BrokerHost: brokerHosts[0],
 			TLSConfig:  tlsConfig,
 			Timeout:    dialTimeout,
+			Endpoints:  endpoints,
 		}
 
 		// start a goroutine to periodically refresh client certificates for this connection
@@ -187,8 +241,9 @@ func BuildMQTTOptionsFromFlags(configPath string) (*MQTTOptions, error) {
 	}
 
 	options.Dialer = &MQTTDialer{
-		BrokerHost: config.BrokerHost,
+		BrokerHost: brokerHosts[0],
 		Timeout:    dialTimeout,
+		Endpoints:  endpoints,
 	}
 	return options, nil
 }
@@ -232,7 +287,19 @@ func (o *MQTTOptions) GetCloudEventsProtocol(
 
 	opts := []cloudeventsmqtt.Option{cloudeventsmqtt.WithConnect(o.GetMQTTConnectOption(clientID))}
 	opts = append(opts, clientOpts...)
-	return cloudeventsmqtt.New(ctx, config, opts...)
+	protocol, err := cloudeventsmqtt.New(ctx, config, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Dialer.Endpoints != nil {
+		// reconnect the client to the primary broker once it's healthy again
+		o.Dialer.Endpoints.WatchPrimary(ctx, func() {
+			errorHandler(fmt.Errorf("switch back to the primary MQTT broker %s", o.Dialer.Endpoints.Current()))
+		})
+	}
+
+	return protocol, nil
 }
 
 func validateTopics(topics *types.Topics) error {
@@ -3,6 +3,7 @@ package mqtt
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"os"
 	"strings"
@@ -348,6 +349,52 @@ func TestConnectionTimeout(t *testing.T) {
 	}
 }
 
+func TestBrokerFailover(t *testing.T) {
+	ln := newLocalListener(t)
+	defer ln.Close()
+
+	// the primary broker is unavailable
+	unavailable := newLocalListener(t)
+	unavailableHost := unavailable.Addr().String()
+	unavailable.Close()
+
+	config := fmt.Sprintf(`
+brokerHost: %s
+brokerHosts:
+- %s
+topics:
+  sourceEvents: sources/hub1/clusters/+/sourceevents
+  agentEvents: sources/hub1/clusters/+/agentevents
+`, unavailableHost, ln.Addr().String())
+	file, err := clienttesting.WriteToTempFile("mqtt-config-test-", []byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	options, err := BuildMQTTOptionsFromFlags(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if options.Dialer.BrokerHost != unavailableHost {
+		t.Errorf("expected primary broker %s, but got %s", unavailableHost, options.Dialer.BrokerHost)
+	}
+	if options.Dialer.Endpoints == nil {
+		t.Fatal("expected endpoint selector")
+	}
+
+	conn, err := options.Dialer.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if options.Dialer.Endpoints.Current() != ln.Addr().String() {
+		t.Errorf("expected standby broker %s, but got %s", ln.Addr().String(), options.Dialer.Endpoints.Current())
+	}
+}
+
 func newLocalListener(t *testing.T) net.Listener {
 	ln, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
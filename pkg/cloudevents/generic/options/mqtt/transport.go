@@ -0,0 +1,46 @@
+package mqtt
+
+import (
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/constants"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+func init() {
+	options.RegisterTransport(constants.ConfigTypeMQTT, func(configPath string) (options.TransportConfig, error) {
+		mqttOptions, err := BuildMQTTOptionsFromFlags(configPath)
+		if err != nil {
+			return nil, err
+		}
+		return mqttOptions, nil
+	})
+}
+
+// maxInflightEvents is the maximum number of the events that are published at the same time, the MQTT client tracks
+// the in-flight messages by their packet IDs, so a message is published without waiting for the previous messages to
+// be acknowledged.
+const maxInflightEvents = 10
+
+var _ options.PipelinedSender = &mqttSourceOptions{}
+var _ options.PipelinedSender = &mqttAgentOptions{}
+
+var _ options.TransportConfig = &MQTTOptions{}
+
+// ServerAddress returns the host of the MQTT broker.
+func (o *MQTTOptions) ServerAddress() string {
+	return o.Dialer.BrokerHost
+}
+
+// SourceOptions builds the cloudevents source options based on MQTT.
+func (o *MQTTOptions) SourceOptions(clientID, sourceID string) (*options.CloudEventsSourceOptions, error) {
+	return NewSourceOptions(o, clientID, sourceID), nil
+}
+
+// AgentOptions builds the cloudevents agent options based on MQTT.
+func (o *MQTTOptions) AgentOptions(clusterName, clientID string) (*options.CloudEventsAgentOptions, error) {
+	return NewAgentOptions(o, clusterName, clientID), nil
+}
+
+// MaxInflightEvents returns the maximum number of the events that are published at the same time.
+func (o *MQTTOptions) MaxInflightEvents() int {
+	return maxInflightEvents
+}
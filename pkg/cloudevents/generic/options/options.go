@@ -2,9 +2,13 @@ package options
 
 import (
 	"context"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/protocol"
+	"go.opentelemetry.io/otel/trace"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
 // CloudEventsOptions provides cloudevents clients to send/receive cloudevents based on different event protocol.
@@ -35,6 +39,14 @@ type CloudEventsProtocol interface {
 	protocol.Closer
 }
 
+// PipelinedSender is implemented by a CloudEventsOptions whose transport can send multiple events concurrently
+// without waiting for the previous events to be delivered. The source/agent client sends the events of a batch in a
+// pipeline if its CloudEventsOptions implements it, otherwise the events are sent one by one.
+type PipelinedSender interface {
+	// MaxInflightEvents returns the maximum number of the events that are being sent at the same time.
+	MaxInflightEvents() int
+}
+
 // EventRateLimit for limiting the event sending rate.
 type EventRateLimit struct {
 	// QPS indicates the maximum QPS to send the event.
@@ -44,6 +56,285 @@ type EventRateLimit struct {
 	// Maximum burst for throttle.
 	// If it's less than or equal to zero, the DefaultBurst (100) will be used.
 	Burst int
+
+	// PriorityAndFairness enables sending the events by their priorities when the sending rate is limited, the
+	// changes of the resources are sent before the resync requests and responses, and the resync requests and
+	// responses are sent before the events that are broadcast to all clusters/sources. The events that have the
+	// same priority are sent to their clusters/sources in turn, so a cluster/source that has a large number of
+	// events does not starve the others. The QPS and Burst are still the ceiling of the sending rate.
+	// If it's false, the events are sent in the order they're published.
+	PriorityAndFairness bool
+}
+
+// DeadLetterStage represents the stage in which a received event failed to be processed.
+type DeadLetterStage string
+
+const (
+	// DeadLetterStageParse represents the type of the event cannot be parsed.
+	DeadLetterStageParse DeadLetterStage = "parse"
+
+	// DeadLetterStageCodec represents there is no codec registered for the data type of the event.
+	DeadLetterStageCodec DeadLetterStage = "codec"
+
+	// DeadLetterStageDecode represents the event cannot be decoded to a resource object by the codec.
+	DeadLetterStageDecode DeadLetterStage = "decode"
+
+	// DeadLetterStageVerify represents the event is not signed or its signature cannot be verified.
+	DeadLetterStageVerify DeadLetterStage = "verify"
+
+	// DeadLetterStageDecrypt represents the encrypted event data cannot be decrypted.
+	DeadLetterStageDecrypt DeadLetterStage = "decrypt"
+
+	// DeadLetterStageHandle represents the resource handler failed to handle the decoded resource object.
+	DeadLetterStageHandle DeadLetterStage = "handle"
+)
+
+// DeadLetter represents a received event that failed to be processed.
+type DeadLetter struct {
+	// ClientID is the ID of the source/agent client that received the event.
+	ClientID string `json:"clientID"`
+
+	// Stage is the stage in which the event failed to be processed.
+	Stage DeadLetterStage `json:"stage"`
+
+	// Reason describes why the event failed to be processed.
+	Reason string `json:"reason"`
+
+	// Timestamp is the time when the event failed to be processed.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Event is the raw received event.
+	Event cloudevents.Event `json:"event"`
+}
+
+// DeadLetterSink receives the events that failed to be parsed, decoded or handled by a source/agent client, so
+// they can be inspected and replayed later.
+type DeadLetterSink interface {
+	// Put saves a dead letter to the sink.
+	Put(ctx context.Context, letter DeadLetter) error
+}
+
+// EventSigner signs the events that are sent by a source/agent client.
+type EventSigner interface {
+	// Sign signs the event and saves the signature to the event.
+	Sign(evt *cloudevents.Event) error
+}
+
+// EventVerifier verifies the signatures of the events that are received by a source/agent client.
+type EventVerifier interface {
+	// Verify returns an error if the event is not signed or its signature is invalid.
+	Verify(evt cloudevents.Event) error
+}
+
+// EventEncryptor encrypts the data of the events that are sent by a source/agent client.
+type EventEncryptor interface {
+	// Encrypt encrypts the event data for the recipient of the event, the recipient of a source is a cluster and the
+	// recipient of an agent is a source.
+	Encrypt(recipient string, evt *cloudevents.Event) error
+}
+
+// EventDecryptor decrypts the data of the events that are received by a source/agent client.
+type EventDecryptor interface {
+	// Decrypt decrypts the event data, the event is not changed if its data is not encrypted.
+	Decrypt(evt *cloudevents.Event) error
+}
+
+// EventConverter converts the events between the versions of their cloud events data types, so the sources and
+// agents that support different versions of a data type can talk with each other.
+type EventConverter interface {
+	// ConvertibleVersions returns the versions that the events of the given data type can be converted to.
+	ConvertibleVersions(dataType types.CloudEventsDataType) []string
+
+	// Convert converts the event to the given version of its data type.
+	Convert(evt cloudevents.Event, version string) (cloudevents.Event, error)
+}
+
+// HandlerRetry configures retrying the resource handlers with an exponential backoff when they fail to handle a
+// received resource. The retries are tracked per resource ID, a newer event of a resource supersedes the pending
+// retry of the resource.
+type HandlerRetry struct {
+	// MaxRetries is the maximum number of retries for a failed resource.
+	// If it's less than or equal to zero, the DefaultHandlerMaxRetries (5) will be used.
+	MaxRetries int
+
+	// BaseDelay is the delay of the first retry, the delay is doubled for each subsequent retry.
+	// If it's less than or equal to zero, the DefaultHandlerRetryBaseDelay (100ms) will be used.
+	BaseDelay time.Duration
+
+	// MaxDelay is the maximum delay between two retries.
+	// If it's less than or equal to zero, the DefaultHandlerRetryMaxDelay (1min) will be used.
+	MaxDelay time.Duration
+
+	// OnFailure is called with the received event and the last error when the retries of a resource are exhausted.
+	// If it's not set, the event will be sent to the DeadLetterSink.
+	OnFailure func(evt cloudevents.Event, err error)
+}
+
+// EventOutbox configures a bounded outbox that queues the events which are published while the client is
+// disconnected. The queued events are coalesced by their resource ID, only the latest event of one resource is kept,
+// and they are sent in order after the client is reconnected.
+type EventOutbox struct {
+	// MaxSize indicates the maximum number of the events that can be queued in the outbox.
+	// If it's less than or equal to zero, the DefaultOutboxMaxSize (1000) will be used.
+	MaxSize int
+
+	// MaxRetries indicates the maximum number of the retries to send a queued event, the event is dropped from the
+	// outbox if it still fails to be sent, so it does not block the events that are queued behind it.
+	// If it's less than or equal to zero, the DefaultOutboxMaxRetries (5) will be used.
+	MaxRetries int
+}
+
+// EventDispatch configures a worker pool that processes the received events concurrently. The events are sharded
+// to the workers by their resource ID (or by their cluster name if the resource ID is absent), so the events of one
+// resource are processed in order while the events of different resources are processed concurrently.
+type EventDispatch struct {
+	// Workers indicates the number of the workers that process the received events.
+	// If it's less than or equal to zero, the DefaultDispatchWorkers (10) will be used.
+	Workers int
+
+	// MaxInFlight indicates the maximum number of the received events that are being processed or waiting to be
+	// processed, receiving is blocked when the limit is reached.
+	// If it's less than or equal to zero, the DefaultDispatchMaxInFlight (100) will be used.
+	MaxInFlight int
+}
+
+// ResyncChunk configures splitting a large resync request into several events, so that the size of each event does
+// not exceed the message size limit of the broker. The receiver reassembles the chunks of a resync request before
+// responding it.
+type ResyncChunk struct {
+	// Size indicates the maximum number of the resources that are carried by one resync request event.
+	// If it's less than or equal to zero, the DefaultResyncChunkSize (1000) will be used.
+	Size int
+
+	// Timeout indicates how long the receiver waits for the remaining chunks of a resync request, the received chunks
+	// are dropped after the timeout.
+	// If it's less than or equal to zero, the DefaultResyncChunkTimeout (1min) will be used.
+	Timeout time.Duration
+
+	// MaxChunks indicates the maximum number of the chunks of a received resync request, the chunks of a resync
+	// request that has more chunks are rejected.
+	// If it's less than or equal to zero, the DefaultResyncChunkMaxChunks (1000) will be used.
+	MaxChunks int
+
+	// MaxPendingRequests indicates the maximum number of the received resync requests whose chunks are not completed,
+	// the chunks of the other resync requests are rejected until the pending requests are completed or timed out.
+	// If it's less than or equal to zero, the DefaultResyncChunkMaxPendingRequests (100) will be used.
+	MaxPendingRequests int
+}
+
+// EventDeduplication configures dropping the duplicated received events, e.g. the events that are redelivered by the
+// broker or resent after a reconnection. An event is identified by its source and ID, a status event is also
+// identified by its resource ID and status update sequence ID.
+type EventDeduplication struct {
+	// TTL indicates how long a received event is remembered.
+	// If it's less than or equal to zero, the DefaultDeduplicationTTL (10min) will be used.
+	TTL time.Duration
+
+	// MaxSize indicates the maximum number of the remembered events, the oldest event is forgotten when the limit is
+	// reached.
+	// If it's less than or equal to zero, the DefaultDeduplicationMaxSize (10000) will be used.
+	MaxSize int
+}
+
+// MerkleResync configures resyncing the resources status with merkle trees. The source and the agent build merkle
+// trees over the resource IDs and status hashes, and only exchange the mismatched subtrees, so a resync costs a few
+// small events when the status is not changed.
+type MerkleResync struct {
+	// Depth indicates the depth of the merkle tree, the fanout of the tree is 16.
+	// If it's less than or equal to zero, the DefaultMerkleTreeDepth (2) will be used.
+	Depth int
+
+	// Timeout indicates how long the source waits for the first merkle resync response of an agent, if the agent does
+	// not respond in time, the source falls back to the full status resync for the agent.
+	// If it's less than or equal to zero, the DefaultMerkleResyncTimeout (30s) will be used.
+	Timeout time.Duration
+}
+
+// EventEncoding configures compressing the event data. The encodings are negotiated with the peers, a client
+// advertises the content types that it accepts in its resync requests, and the event data that is sent to a peer is
+// encoded with the first preferred content type that is accepted by the peer. The event data is kept as JSON if the
+// peer's accepted content types are unknown.
+type EventEncoding struct {
+	// ContentTypes are the preferred content types of the event data in order, e.g. application/json+zstd.
+	ContentTypes []string
+
+	// DataTypeContentTypes overrides the preferred content types for the specified cloud events data types, the key
+	// is a cloud events data type, e.g. io.open-cluster-management.works.v1alpha1.manifestbundles.
+	DataTypeContentTypes map[string][]string
+}
+
+// ReconnectPolicy configures how a source/agent client reconnects after its connection is broken. The reconnection
+// attempts are delayed with an exponential backoff, the backoff is reset if the client is not disconnected for the
+// ResetInterval.
+type ReconnectPolicy struct {
+	// BaseDelay is the delay of the first reconnection attempt, the delay is multiplied by the Factor for each
+	// subsequent attempt.
+	// If it's less than or equal to zero, the DefaultReconnectBaseDelay (5s) will be used.
+	BaseDelay time.Duration
+
+	// MaxDelay is the maximum delay between two reconnection attempts.
+	// If it's less than or equal to zero, the DefaultReconnectMaxDelay (1min) will be used.
+	MaxDelay time.Duration
+
+	// Factor is the multiplier of the delay for each subsequent attempt.
+	// If it's less than or equal to one, the DefaultReconnectFactor (5.0) will be used.
+	Factor float64
+
+	// Jitter adds a random delay of up to Jitter*delay to each attempt.
+	// If it's less than or equal to zero, the DefaultReconnectJitter (1.0) will be used.
+	Jitter float64
+
+	// ResetInterval is how long the client must stay connected before the backoff is reset.
+	// If it's less than or equal to zero, the DefaultReconnectResetInterval (10min) will be used.
+	ResetInterval time.Duration
+
+	// MaxAttempts is the maximum number of the consecutive failed reconnection attempts, the client is closed when the
+	// attempts are exhausted.
+	// If it's less than or equal to zero, the client reconnects until its context is canceled.
+	MaxAttempts int
+
+	// CircuitBreaker pauses the reconnection attempts after consecutive failures. If it's not set, the reconnection is
+	// only delayed by the backoff.
+	CircuitBreaker *ReconnectCircuitBreaker
+}
+
+// ReconnectCircuitBreaker opens the circuit when a client fails to reconnect for FailureThreshold times in a row, no
+// reconnection is attempted while the circuit is open. After the OpenDuration, one reconnection is attempted, the
+// circuit is closed if it succeeds, otherwise the circuit is opened again.
+type ReconnectCircuitBreaker struct {
+	// FailureThreshold is the number of the consecutive failed reconnection attempts to open the circuit.
+	// If it's less than or equal to zero, the DefaultCircuitBreakerFailureThreshold (5) will be used.
+	FailureThreshold int
+
+	// OpenDuration is how long the circuit stays open.
+	// If it's less than or equal to zero, the DefaultCircuitBreakerOpenDuration (5min) will be used.
+	OpenDuration time.Duration
+}
+
+// AgentHeartbeat configures an agent to publish the heartbeat events periodically, so the sources can tell whether
+// the agent is alive when there is no resource status update.
+type AgentHeartbeat struct {
+	// Interval is the interval to publish the heartbeat events.
+	// If it's less than or equal to zero, the DefaultHeartbeatInterval (30s) will be used.
+	Interval time.Duration
+}
+
+// HeartbeatLease configures a source to track the heartbeats of the agents. The lease of an agent is renewed when a
+// heartbeat of the agent is received, and expires if there is no heartbeat in the LeaseDuration.
+type HeartbeatLease struct {
+	// LeaseDuration is how long the lease of an agent lasts after its last heartbeat, it should be longer than the
+	// heartbeat interval of the agents.
+	// If it's less than or equal to zero, the DefaultHeartbeatLeaseDuration (90s) will be used.
+	LeaseDuration time.Duration
+
+	// OnExpired is called with the cluster name of the agent and the time of its last heartbeat when the lease of an
+	// agent expires.
+	OnExpired func(clusterName string, lastSeen time.Time)
+
+	// EvictionGracePeriod is how long an expired lease of an agent is kept, the agent is removed from the tracked
+	// agents and its heartbeat metrics are deleted if there is no heartbeat in the grace period.
+	// If it's less than or equal to zero, the DefaultHeartbeatEvictionGracePeriod (10m) will be used.
+	EvictionGracePeriod time.Duration
 }
 
 // CloudEventsSourceOptions provides the required options to build a source CloudEventsClient
@@ -58,6 +349,72 @@ type CloudEventsSourceOptions struct {
 
 	// EventRateLimit limits the event sending rate.
 	EventRateLimit EventRateLimit
+
+	// Outbox enables queueing the events while the client is disconnected. If it's not set, publishing an event
+	// fails immediately when the client is disconnected.
+	Outbox *EventOutbox
+
+	// DeadLetterSink receives the events that failed to be processed. If it's not set, the failed events are only
+	// logged.
+	DeadLetterSink DeadLetterSink
+
+	// HandlerRetry enables retrying the failed resource handlers. If it's not set, a failed resource is not retried
+	// until the next resync.
+	HandlerRetry *HandlerRetry
+
+	// Dispatch enables processing the received events with a worker pool. If it's not set, the received events are
+	// handled by the cloudevents receiver directly.
+	Dispatch *EventDispatch
+
+	// ResyncChunk enables splitting the resync requests into chunks. If it's not set, a resync request is sent with
+	// one event, the chunked resync requests from the peers are always reassembled with the DefaultResyncChunkTimeout.
+	ResyncChunk *ResyncChunk
+
+	// MerkleResync enables resyncing the resources status of a cluster with merkle trees. If it's not set, or the
+	// resources status of all clusters is resynced, the status hashes of all resources are sent to the agents.
+	MerkleResync *MerkleResync
+
+	// Encoding enables encoding the event data with the negotiated content types. If it's not set, the event data is
+	// sent as JSON, the encoded event data from the peers is always decoded.
+	Encoding *EventEncoding
+
+	// Signer signs the sent events. If it's not set, the sent events are not signed.
+	Signer EventSigner
+
+	// Verifier verifies the received events, the events that are not signed or have invalid signatures are rejected
+	// and sent to the DeadLetterSink. If it's not set, the received events are not verified.
+	Verifier EventVerifier
+
+	// Encryptor encrypts the data of the sent events with the keys of the clusters, so the brokers cannot read the
+	// resources. The routing extensions are kept in clear. If it's not set, the event data is not encrypted.
+	Encryptor EventEncryptor
+
+	// Decryptor decrypts the encrypted data of the received events. If it's not set, the encrypted event data cannot
+	// be decoded.
+	Decryptor EventDecryptor
+
+	// TracerProvider provides the tracer to start the spans of publishing, receiving, resyncing and handling the
+	// events, the trace context is propagated with the cloud events distributed tracing extension. If it's not set,
+	// a no-op tracer is used.
+	TracerProvider trace.TracerProvider
+
+	// Reconnect configures the backoff and the limits of reconnecting the client. If it's not set, the client
+	// reconnects with the DelayFn until its context is canceled.
+	Reconnect *ReconnectPolicy
+
+	// HeartbeatLease enables tracking the heartbeats of the agents. If it's not set, the received heartbeats are
+	// ignored.
+	HeartbeatLease *HeartbeatLease
+
+	// Deduplication enables dropping the duplicated received events. If it's not set, the duplicated events are
+	// handled repeatedly.
+	Deduplication *EventDeduplication
+
+	// Conversion enables negotiating the versions of the cloud events data types with the peers, the supported
+	// versions are exchanged in the resync requests, the events are converted to the newest version that is supported
+	// by both sides. If it's not set, the events are sent with the versions of their data types, and the received
+	// events are decoded only if there are codecs for the versions of their data types.
+	Conversion EventConverter
 }
 
 // CloudEventsAgentOptions provides the required options to build an agent CloudEventsClient
@@ -74,4 +431,65 @@ type CloudEventsAgentOptions struct {
 
 	// EventRateLimit limits the event sending rate.
 	EventRateLimit EventRateLimit
+
+	// Outbox enables queueing the events while the client is disconnected. If it's not set, publishing an event
+	// fails immediately when the client is disconnected.
+	Outbox *EventOutbox
+
+	// DeadLetterSink receives the events that failed to be processed. If it's not set, the failed events are only
+	// logged.
+	DeadLetterSink DeadLetterSink
+
+	// HandlerRetry enables retrying the failed resource handlers. If it's not set, a failed resource is not retried
+	// until the next resync.
+	HandlerRetry *HandlerRetry
+
+	// Dispatch enables processing the received events with a worker pool. If it's not set, the received events are
+	// handled by the cloudevents receiver directly.
+	Dispatch *EventDispatch
+
+	// ResyncChunk enables splitting the resync requests into chunks. If it's not set, a resync request is sent with
+	// one event, the chunked resync requests from the peers are always reassembled with the DefaultResyncChunkTimeout.
+	ResyncChunk *ResyncChunk
+
+	// Encoding enables encoding the event data with the negotiated content types. If it's not set, the event data is
+	// sent as JSON, the encoded event data from the peers is always decoded.
+	Encoding *EventEncoding
+
+	// Signer signs the sent events. If it's not set, the sent events are not signed.
+	Signer EventSigner
+
+	// Verifier verifies the received events, the events that are not signed or have invalid signatures are rejected
+	// and sent to the DeadLetterSink. If it's not set, the received events are not verified.
+	Verifier EventVerifier
+
+	// Encryptor encrypts the data of the sent events with the keys of the sources, so the brokers cannot read the
+	// resource status. The routing extensions are kept in clear. If it's not set, the event data is not encrypted.
+	Encryptor EventEncryptor
+
+	// Decryptor decrypts the encrypted data of the received events with the key of the cluster. If it's not set, the
+	// encrypted event data cannot be decoded.
+	Decryptor EventDecryptor
+
+	// TracerProvider provides the tracer to start the spans of publishing, receiving, resyncing and handling the
+	// events, the trace context is propagated with the cloud events distributed tracing extension. If it's not set,
+	// a no-op tracer is used.
+	TracerProvider trace.TracerProvider
+
+	// Reconnect configures the backoff and the limits of reconnecting the client. If it's not set, the client
+	// reconnects with the DelayFn until its context is canceled.
+	Reconnect *ReconnectPolicy
+
+	// Heartbeat enables publishing the heartbeat events to all sources. If it's not set, no heartbeat is published.
+	Heartbeat *AgentHeartbeat
+
+	// Deduplication enables dropping the duplicated received events. If it's not set, the duplicated events are
+	// handled repeatedly.
+	Deduplication *EventDeduplication
+
+	// Conversion enables negotiating the versions of the cloud events data types with the peers, the supported
+	// versions are exchanged in the resync requests, the events are converted to the newest version that is supported
+	// by both sides. If it's not set, the events are sent with the versions of their data types, and the received
+	// events are decoded only if there are codecs for the versions of their data types.
+	Conversion EventConverter
 }
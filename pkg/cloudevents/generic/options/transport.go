@@ -0,0 +1,64 @@
+package options
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// TransportConfig is the configuration of a cloudevents transport, it builds the cloudevents source/agent options
+// that are based on the transport.
+type TransportConfig interface {
+	// ServerAddress returns the address of the server that the transport connects to, e.g. the MQTT broker host.
+	ServerAddress() string
+
+	// SourceOptions builds the cloudevents source options with the given client ID and source ID.
+	SourceOptions(clientID, sourceID string) (*CloudEventsSourceOptions, error)
+
+	// AgentOptions builds the cloudevents agent options with the given cluster name and client ID.
+	AgentOptions(clusterName, clientID string) (*CloudEventsAgentOptions, error)
+}
+
+// TransportFactory loads a TransportConfig from a configuration file.
+type TransportFactory func(configPath string) (TransportConfig, error)
+
+var (
+	transportsLock sync.RWMutex
+	transports     = map[string]TransportFactory{}
+)
+
+// RegisterTransport registers a transport with its name, the name is used as the configuration type to load the
+// transport configuration. A transport package usually registers itself in its init function, the transport with
+// the same name will be replaced.
+func RegisterTransport(name string, factory TransportFactory) {
+	transportsLock.Lock()
+	defer transportsLock.Unlock()
+
+	transports[name] = factory
+}
+
+// LoadTransportConfig loads the configuration of the given transport from a configuration file.
+func LoadTransportConfig(name, configPath string) (TransportConfig, error) {
+	transportsLock.RLock()
+	factory, ok := transports[name]
+	transportsLock.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unsupported config type %s", name)
+	}
+
+	return factory(configPath)
+}
+
+// Transports returns the names of the registered transports.
+func Transports() []string {
+	transportsLock.RLock()
+	defer transportsLock.RUnlock()
+
+	names := []string{}
+	for name := range transports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
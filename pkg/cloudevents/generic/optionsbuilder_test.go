@@ -7,8 +7,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
 	"k8s.io/apimachinery/pkg/api/equality"
 
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
@@ -103,3 +106,57 @@ func assertOptions(t *testing.T, c buildingCloudEventsOptionTestCase) {
 		t.Errorf("the results %v\n does not contain the original options %v\n", string(optionsRaw), string(expectedRaw))
 	}
 }
+
+type fakeTransportConfig struct {
+	server string
+}
+
+func (c *fakeTransportConfig) ServerAddress() string {
+	return c.server
+}
+
+func (c *fakeTransportConfig) SourceOptions(clientID, sourceID string) (*options.CloudEventsSourceOptions, error) {
+	return fake.NewSourceOptions(gochan.New(), sourceID), nil
+}
+
+func (c *fakeTransportConfig) AgentOptions(clusterName, clientID string) (*options.CloudEventsAgentOptions, error) {
+	return fake.NewAgentOptions(gochan.New(), nil, clusterName, clientID), nil
+}
+
+func TestRegisterTransport(t *testing.T) {
+	options.RegisterTransport("fake", func(configPath string) (options.TransportConfig, error) {
+		return &fakeTransportConfig{server: configPath}, nil
+	})
+
+	server, config, err := NewConfigLoader("fake", "fake-server").LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server != "fake-server" {
+		t.Errorf("unexpected server %s", server)
+	}
+
+	sourceOptions, err := BuildCloudEventsSourceOptions(config, "client", sourceId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sourceOptions.SourceID != sourceId {
+		t.Errorf("unexpected source options %v", sourceOptions)
+	}
+
+	agentOptions, err := BuildCloudEventsAgentOptions(config, "cluster1", "client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agentOptions.ClusterName != "cluster1" || agentOptions.AgentID != "client" {
+		t.Errorf("unexpected agent options %v", agentOptions)
+	}
+
+	if _, _, err := NewConfigLoader("unknown", "").LoadConfig(); err == nil {
+		t.Errorf("expected error, but failed")
+	}
+
+	if _, err := BuildCloudEventsSourceOptions(nil, "client", sourceId); err == nil {
+		t.Errorf("expected error, but failed")
+	}
+}
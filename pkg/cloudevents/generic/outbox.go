@@ -0,0 +1,168 @@
+package generic
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// DefaultOutboxMaxSize is the default maximum number of the events that can be queued in an outbox.
+const DefaultOutboxMaxSize = 1000
+
+// DefaultOutboxMaxRetries is the default maximum number of the retries to send a queued event.
+const DefaultOutboxMaxRetries = 5
+
+// outbox is a bounded FIFO queue that keeps the events which are published while the client is disconnected.
+// The events are coalesced by their key, a newer event replaces the queued one with the same key and is moved to
+// the tail of the queue.
+type outbox struct {
+	sync.Mutex
+
+	clientID   string
+	maxSize    int
+	maxRetries int
+	queue      *list.List
+	elements   map[string]*list.Element
+	draining   bool
+}
+
+type outboxItem struct {
+	key   string
+	event cloudevents.Event
+}
+
+func newOutbox(clientID string, config *options.EventOutbox) *outbox {
+	if config == nil {
+		return nil
+	}
+
+	maxSize := config.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultOutboxMaxSize
+	}
+
+	maxRetries := config.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = DefaultOutboxMaxRetries
+	}
+
+	return &outbox{
+		clientID:   clientID,
+		maxSize:    maxSize,
+		maxRetries: maxRetries,
+		queue:      list.New(),
+		elements:   map[string]*list.Element{},
+	}
+}
+
+// addIfPending queues the given event if the client is not ready or there are pending events in the outbox, this
+// guarantees the events are sent in order. It returns true if the event is queued.
+func (o *outbox) addIfPending(evt cloudevents.Event, ready bool) (bool, error) {
+	o.Lock()
+	defer o.Unlock()
+
+	if ready && o.queue.Len() == 0 {
+		return false, nil
+	}
+
+	return true, o.addLocked(evt)
+}
+
+func (o *outbox) addLocked(evt cloudevents.Event) error {
+	key := outboxKey(evt)
+	if last, ok := o.elements[key]; ok {
+		o.queue.Remove(last)
+	} else if o.queue.Len() >= o.maxSize {
+		increaseOutboxDroppedCounter(o.clientID)
+		return fmt.Errorf("the outbox is full (size=%d), drop the event %s", o.maxSize, evt.ID())
+	}
+
+	o.elements[key] = o.queue.PushBack(&outboxItem{key: key, event: evt})
+	updateOutboxDepthMetric(o.clientID, o.queue.Len())
+	return nil
+}
+
+// front returns the element at the head of the queue without removing it.
+func (o *outbox) front() (*list.Element, bool) {
+	o.Lock()
+	defer o.Unlock()
+
+	elem := o.queue.Front()
+	if elem == nil {
+		return nil, false
+	}
+
+	return elem, true
+}
+
+// remove removes the given element from the queue if it's not replaced by a newer event.
+func (o *outbox) remove(elem *list.Element) {
+	o.Lock()
+	defer o.Unlock()
+
+	item := elem.Value.(*outboxItem)
+	if current, ok := o.elements[item.key]; ok && current == elem {
+		delete(o.elements, item.key)
+	}
+
+	o.queue.Remove(elem)
+	updateOutboxDepthMetric(o.clientID, o.queue.Len())
+}
+
+// startDraining marks the outbox is draining, it returns false if the outbox is being drained by others.
+func (o *outbox) startDraining() bool {
+	o.Lock()
+	defer o.Unlock()
+
+	if o.draining {
+		return false
+	}
+
+	o.draining = true
+	return true
+}
+
+func (o *outbox) stopDraining() {
+	o.Lock()
+	defer o.Unlock()
+	o.draining = false
+}
+
+func (o *outbox) len() int {
+	o.Lock()
+	defer o.Unlock()
+	return o.queue.Len()
+}
+
+// outboxKey returns the key of an event, the resource events are keyed by their resource ID and subresource, other
+// events (e.g. resync requests) are keyed by their type and target. The chunks of a chunked resync request are keyed
+// by their chunk ID and index, so they're not coalesced with each other.
+func outboxKey(evt cloudevents.Event) string {
+	extensions := evt.Context.GetExtensions()
+
+	resourceID, err := cloudeventstypes.ToString(extensions[types.ExtensionResourceID])
+	if err == nil && len(resourceID) != 0 {
+		subResource := ""
+		if eventType, err := types.ParseCloudEventsType(evt.Type()); err == nil {
+			subResource = string(eventType.SubResource)
+		}
+		return fmt.Sprintf("%s/%s", resourceID, subResource)
+	}
+
+	clusterName, _ := cloudeventstypes.ToString(extensions[types.ExtensionClusterName])
+	originalSource, _ := cloudeventstypes.ToString(extensions[types.ExtensionOriginalSource])
+	key := fmt.Sprintf("%s/%s/%s", evt.Type(), clusterName, originalSource)
+
+	if chunkID, err := cloudeventstypes.ToString(extensions[types.ExtensionChunkID]); err == nil {
+		chunkIndex, _ := cloudeventstypes.ToInteger(extensions[types.ExtensionChunkIndex])
+		key = fmt.Sprintf("%s/%s/%d", key, chunkID, chunkIndex)
+	}
+
+	return key
+}
@@ -0,0 +1,297 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/stretchr/testify/require"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestOutbox(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_update_request",
+	}
+
+	newEvent := func(resourceID string, resourceVersion int64) cloudevents.Event {
+		return types.NewEventBuilder(testSourceName, eventType).
+			WithResourceID(resourceID).
+			WithResourceVersion(resourceVersion).
+			WithClusterName("cluster1").
+			NewEvent()
+	}
+
+	cases := []struct {
+		name            string
+		maxSize         int
+		events          []cloudevents.Event
+		expectedErrs    int
+		expectedVersion []int64
+	}{
+		{
+			name:            "keep the events in order",
+			events:          []cloudevents.Event{newEvent("r1", 1), newEvent("r2", 1), newEvent("r3", 1)},
+			expectedVersion: []int64{1, 1, 1},
+		},
+		{
+			name:            "coalesce the events with the same resource id",
+			events:          []cloudevents.Event{newEvent("r1", 1), newEvent("r2", 1), newEvent("r1", 2)},
+			expectedVersion: []int64{1, 2},
+		},
+		{
+			name:            "drop the events when the outbox is full",
+			maxSize:         2,
+			events:          []cloudevents.Event{newEvent("r1", 1), newEvent("r2", 1), newEvent("r3", 1), newEvent("r2", 2)},
+			expectedErrs:    1,
+			expectedVersion: []int64{1, 2},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ResetCloudEventsMetrics()
+
+			o := newOutbox(testSourceName, &options.EventOutbox{MaxSize: c.maxSize})
+			errs := 0
+			for _, evt := range c.events {
+				queued, err := o.addIfPending(evt, false)
+				if err != nil {
+					errs++
+					continue
+				}
+				require.True(t, queued)
+			}
+			require.Equal(t, c.expectedErrs, errs)
+			require.Equal(t, len(c.expectedVersion), o.len())
+			require.Equal(t, float64(c.expectedErrs), toFloat64Counter(outboxDroppedCounterMetric.WithLabelValues(testSourceName)))
+
+			versions := []int64{}
+			for {
+				elem, ok := o.front()
+				if !ok {
+					break
+				}
+
+				version, err := elem.Value.(*outboxItem).event.Context.GetExtension(types.ExtensionResourceVersion)
+				require.NoError(t, err)
+				versions = append(versions, int64(version.(int32)))
+				o.remove(elem)
+			}
+			require.Equal(t, c.expectedVersion, versions)
+		})
+	}
+}
+
+func TestOutboxWithResyncChunks(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              types.ResyncRequestAction,
+	}
+
+	o := newOutbox(testSourceName, &options.EventOutbox{})
+	for i := 0; i < 3; i++ {
+		evt := types.NewEventBuilder(testSourceName, eventType).
+			WithClusterName("cluster1").
+			WithChunk("chunk1", i, 3).
+			NewEvent()
+		queued, err := o.addIfPending(evt, false)
+		require.NoError(t, err)
+		require.True(t, queued)
+	}
+
+	// the chunks of a resync request are not coalesced
+	require.Equal(t, 3, o.len())
+	for i := 0; i < 3; i++ {
+		elem, ok := o.front()
+		require.True(t, ok)
+
+		chunk, err := payload.GetResyncChunk(elem.Value.(*outboxItem).event)
+		require.NoError(t, err)
+		require.Equal(t, i, chunk.Index)
+		o.remove(elem)
+	}
+
+	// the resync requests without chunks are still coalesced
+	for i := 0; i < 2; i++ {
+		_, err := o.addIfPending(types.NewEventBuilder(testSourceName, eventType).WithClusterName("cluster1").NewEvent(), false)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, o.len())
+}
+
+func TestPublishWithOutbox(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceOptions := fake.NewSourceOptions(gochan.New(), testSourceName)
+	sourceOptions.Outbox = &options.EventOutbox{}
+	source, err := NewCloudEventSourceClient[*mockResource](ctx, sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_update_request",
+	}
+
+	// mimic the client is disconnected
+	source.setClientReady(false)
+
+	require.NoError(t, source.Publish(ctx, eventType, &mockResource{UID: kubetypes.UID("r1"), ResourceVersion: "1", Namespace: "cluster1"}))
+	require.NoError(t, source.Publish(ctx, eventType, &mockResource{UID: kubetypes.UID("r2"), ResourceVersion: "1", Namespace: "cluster1"}))
+	require.NoError(t, source.Publish(ctx, eventType, &mockResource{UID: kubetypes.UID("r1"), ResourceVersion: "2", Namespace: "cluster1"}))
+	require.Equal(t, 2, source.outbox.len())
+
+	eventChan := make(chan cloudevents.Event)
+	go func() {
+		_ = source.cloudEventsClient.StartReceiver(ctx, func(event cloudevents.Event) {
+			eventChan <- event
+		})
+	}()
+
+	// mimic the client is reconnected
+	source.setClientReady(true)
+	go source.drainOutbox(ctx)
+
+	// the events are received concurrently, only check the latest resource versions
+	received := map[string]any{}
+	for i := 0; i < 2; i++ {
+		evt := <-eventChan
+		received[evt.Extensions()[types.ExtensionResourceID].(string)] = evt.Extensions()[types.ExtensionResourceVersion]
+	}
+	require.Equal(t, map[string]any{"r1": "2", "r2": "1"}, received)
+	require.Eventually(t, func() bool { return source.outbox.len() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+// failingCloudEventsClient fails to send the first events
+type failingCloudEventsClient struct {
+	cloudevents.Client
+	failures int
+}
+
+func (c *failingCloudEventsClient) Send(ctx context.Context, evt cloudevents.Event) cloudevents.Result {
+	if c.failures > 0 {
+		c.failures--
+		return fmt.Errorf("failed to send the event")
+	}
+	return c.Client.Send(ctx, evt)
+}
+
+func TestDrainOutboxRetry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backoff := outboxRetryBackoff
+	outboxRetryBackoff.Duration = 10 * time.Millisecond
+	defer func() { outboxRetryBackoff = backoff }()
+
+	sourceOptions := fake.NewSourceOptions(gochan.New(), testSourceName)
+	sourceOptions.Outbox = &options.EventOutbox{}
+	source, err := NewCloudEventSourceClient[*mockResource](ctx, sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_update_request",
+	}
+
+	// mimic the client is disconnected
+	source.setClientReady(false)
+	require.NoError(t, source.Publish(ctx, eventType, &mockResource{UID: kubetypes.UID("r1"), ResourceVersion: "1", Namespace: "cluster1"}))
+	require.Equal(t, 1, source.outbox.len())
+
+	eventChan := make(chan cloudevents.Event)
+	receiver := source.cloudEventsClient
+	go func() {
+		_ = receiver.StartReceiver(ctx, func(event cloudevents.Event) {
+			eventChan <- event
+		})
+	}()
+
+	// mimic the client is reconnected, but the first send fails
+	source.cloudEventsClient = &failingCloudEventsClient{Client: receiver, failures: 1}
+	source.setClientReady(true)
+	go source.drainOutbox(ctx)
+
+	// the queued event is resent without another publish or reconnection
+	select {
+	case evt := <-eventChan:
+		require.Equal(t, "r1", evt.Extensions()[types.ExtensionResourceID])
+	case <-time.After(5 * time.Second):
+		t.Fatal("the queued event is not resent")
+	}
+	require.Eventually(t, func() bool { return source.outbox.len() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+// poisonCloudEventsClient always fails to send the events of the poison resource
+type poisonCloudEventsClient struct {
+	cloudevents.Client
+}
+
+func (c *poisonCloudEventsClient) Send(ctx context.Context, evt cloudevents.Event) cloudevents.Result {
+	if evt.Extensions()[types.ExtensionResourceID] == "poison" {
+		return fmt.Errorf("the event is rejected")
+	}
+	return c.Client.Send(ctx, evt)
+}
+
+func TestDrainOutboxDropPoisonEvent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backoff := outboxRetryBackoff
+	outboxRetryBackoff.Duration = 10 * time.Millisecond
+	defer func() { outboxRetryBackoff = backoff }()
+
+	sourceOptions := fake.NewSourceOptions(gochan.New(), testSourceName)
+	sourceOptions.Outbox = &options.EventOutbox{MaxRetries: 2}
+	source, err := NewCloudEventSourceClient[*mockResource](ctx, sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_update_request",
+	}
+
+	// mimic the client is disconnected, a poison event is queued before a good one
+	source.setClientReady(false)
+	require.NoError(t, source.Publish(ctx, eventType, &mockResource{UID: kubetypes.UID("poison"), ResourceVersion: "1", Namespace: "cluster1"}))
+	require.NoError(t, source.Publish(ctx, eventType, &mockResource{UID: kubetypes.UID("r1"), ResourceVersion: "1", Namespace: "cluster1"}))
+	require.Equal(t, 2, source.outbox.len())
+
+	eventChan := make(chan cloudevents.Event)
+	receiver := source.cloudEventsClient
+	go func() {
+		_ = receiver.StartReceiver(ctx, func(event cloudevents.Event) {
+			eventChan <- event
+		})
+	}()
+
+	// mimic the client is reconnected
+	source.cloudEventsClient = &poisonCloudEventsClient{Client: receiver}
+	source.setClientReady(true)
+	go source.drainOutbox(ctx)
+
+	// the poison event is dropped after the max retries, the good event behind it is sent
+	select {
+	case evt := <-eventChan:
+		require.Equal(t, "r1", evt.Extensions()[types.ExtensionResourceID])
+	case <-time.After(5 * time.Second):
+		t.Fatal("the event behind the poison event is not sent")
+	}
+	require.Eventually(t, func() bool { return source.outbox.len() == 0 }, time.Second, 10*time.Millisecond)
+}
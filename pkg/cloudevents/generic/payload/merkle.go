@@ -0,0 +1,176 @@
+package payload
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+const (
+	// DefaultMerkleTreeDepth is the default depth of a merkle tree, a tree with depth 2 has 256 leaf nodes.
+	DefaultMerkleTreeDepth = 2
+
+	// MaxMerkleTreeDepth is the maximum depth of a merkle tree.
+	MaxMerkleTreeDepth = 4
+
+	merkleTreeFanout = "0123456789abcdef"
+)
+
+// MerkleNode represents a node of a merkle tree.
+type MerkleNode struct {
+	// Path is the path of the node, it is a hex prefix of the SHA256 hash of the resource IDs that are under the node.
+	// The path of the root node is empty.
+	Path string `json:"path"`
+
+	// Hash is the hash of the node, the hash of a node that has no resources is empty.
+	Hash string `json:"hash"`
+
+	// StatusHashes are the status hashes of the resources under a leaf node, it is only set for the leaf nodes
+	// in a merkle resync request.
+	StatusHashes []ResourceStatusHash `json:"statusHashes,omitempty"`
+}
+
+// MerkleNodeList represents the nodes of a merkle tree that are exchanged in a merkle resync request or response.
+//   - A merkle resync request carries the nodes of the requester, the responder compares them with its own nodes.
+//   - A merkle resync response carries the child nodes of the responder for the mismatched nodes.
+type MerkleNodeList struct {
+	// Depth is the depth of the merkle tree, both sides must build their trees with the same depth.
+	Depth int `json:"depth"`
+
+	Nodes []MerkleNode `json:"nodes"`
+}
+
+// MerkleTree is a merkle tree over the (resource ID, status hash) of the resources. The resources are bucketed by the
+// hex prefix of the SHA256 hash of their IDs, each level of the tree consumes one hex character, so the fanout of the
+// tree is 16 and the leaf nodes are at the given depth.
+type MerkleTree struct {
+	depth  int
+	hashes map[string]string
+	leaves map[string][]ResourceStatusHash
+}
+
+// NewMerkleTree builds a merkle tree with the given depth from the resource status hashes.
+func NewMerkleTree(depth int, statusHashes []ResourceStatusHash) *MerkleTree {
+	if depth <= 0 {
+		depth = DefaultMerkleTreeDepth
+	}
+	if depth > MaxMerkleTreeDepth {
+		depth = MaxMerkleTreeDepth
+	}
+
+	tree := &MerkleTree{
+		depth:  depth,
+		hashes: map[string]string{},
+		leaves: map[string][]ResourceStatusHash{},
+	}
+
+	for _, statusHash := range statusHashes {
+		path := MerkleLeafPath(statusHash.ResourceID, depth)
+		tree.leaves[path] = append(tree.leaves[path], statusHash)
+	}
+
+	paths := map[string]bool{}
+	for path, leaf := range tree.leaves {
+		sort.Slice(leaf, func(i, j int) bool { return leaf[i].ResourceID < leaf[j].ResourceID })
+
+		h := sha256.New()
+		for _, statusHash := range leaf {
+			fmt.Fprintf(h, "%s:%s\n", statusHash.ResourceID, statusHash.StatusHash)
+		}
+		tree.hashes[path] = fmt.Sprintf("%x", h.Sum(nil))
+		paths[path[:depth-1]] = true
+	}
+
+	// calculate the hashes of the non-empty nodes from the bottom up
+	for level := depth - 1; level >= 0; level-- {
+		parents := map[string]bool{}
+		for path := range paths {
+			h := sha256.New()
+			for _, child := range tree.Children(path) {
+				fmt.Fprintf(h, "%s\n", child.Hash)
+			}
+			tree.hashes[path] = fmt.Sprintf("%x", h.Sum(nil))
+			if level > 0 {
+				parents[path[:level-1]] = true
+			}
+		}
+		paths = parents
+	}
+
+	return tree
+}
+
+// MerkleLeafPath returns the path of the leaf node that the resource belongs to.
+func MerkleLeafPath(resourceID string, depth int) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(resourceID)))[:depth]
+}
+
+// Depth returns the depth of the tree.
+func (t *MerkleTree) Depth() int {
+	return t.depth
+}
+
+// Root returns the root node of the tree.
+func (t *MerkleTree) Root() MerkleNode {
+	return t.Node("")
+}
+
+// Node returns the node of the given path, the status hashes are set if the node is a leaf node.
+func (t *MerkleTree) Node(path string) MerkleNode {
+	node := MerkleNode{Path: path, Hash: t.hashes[path]}
+	if t.IsLeaf(path) {
+		node.StatusHashes = t.leaves[path]
+	}
+	return node
+}
+
+// Hash returns the hash of the node of the given path.
+func (t *MerkleTree) Hash(path string) string {
+	return t.hashes[path]
+}
+
+// IsLeaf returns true if the node of the given path is a leaf node.
+func (t *MerkleTree) IsLeaf(path string) bool {
+	return len(path) == t.depth
+}
+
+// Children returns the child nodes of the node of the given path without status hashes.
+func (t *MerkleTree) Children(path string) []MerkleNode {
+	if t.IsLeaf(path) {
+		return nil
+	}
+
+	children := make([]MerkleNode, 0, len(merkleTreeFanout))
+	for _, c := range merkleTreeFanout {
+		childPath := path + string(c)
+		children = append(children, MerkleNode{Path: childPath, Hash: t.hashes[childPath]})
+	}
+	return children
+}
+
+// Contains returns true if the resource belongs to the node of the given path.
+func (t *MerkleTree) Contains(path, resourceID string) bool {
+	return strings.HasPrefix(MerkleLeafPath(resourceID, t.depth), path)
+}
+
+func DecodeMerkleResyncRequest(evt cloudevents.Event) (*MerkleNodeList, error) {
+	nodes := &MerkleNodeList{}
+	data := evt.Data()
+	if err := json.Unmarshal(data, nodes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal merkle resync request payload %s, %v", string(data), err)
+	}
+	return nodes, nil
+}
+
+func DecodeMerkleResyncResponse(evt cloudevents.Event) (*MerkleNodeList, error) {
+	nodes := &MerkleNodeList{}
+	data := evt.Data()
+	if err := json.Unmarshal(data, nodes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal merkle resync response payload %s, %v", string(data), err)
+	}
+	return nodes, nil
+}
@@ -0,0 +1,62 @@
+package payload
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newStatusHashes(n int) []ResourceStatusHash {
+	hashes := []ResourceStatusHash{}
+	for i := 0; i < n; i++ {
+		hashes = append(hashes, ResourceStatusHash{ResourceID: fmt.Sprintf("resource-%d", i), StatusHash: fmt.Sprintf("%d", i)})
+	}
+	return hashes
+}
+
+func TestMerkleTree(t *testing.T) {
+	empty := NewMerkleTree(2, nil)
+	if empty.Root().Hash != "" {
+		t.Errorf("expected empty root hash, but got %s", empty.Root().Hash)
+	}
+
+	hashes := newStatusHashes(100)
+	tree := NewMerkleTree(2, hashes)
+
+	// the order of the resources does not affect the tree
+	reversed := []ResourceStatusHash{}
+	for i := len(hashes) - 1; i >= 0; i-- {
+		reversed = append(reversed, hashes[i])
+	}
+	if NewMerkleTree(2, reversed).Root().Hash != tree.Root().Hash {
+		t.Errorf("expected the same root hash for the same resources")
+	}
+
+	// only the nodes on the path of the changed resource are changed
+	changed := newStatusHashes(100)
+	changed[10].StatusHash = "changed"
+	changedTree := NewMerkleTree(2, changed)
+	if changedTree.Root().Hash == tree.Root().Hash {
+		t.Errorf("expected different root hashes")
+	}
+
+	leafPath := MerkleLeafPath(changed[10].ResourceID, 2)
+	mismatched := []string{}
+	for _, child := range changedTree.Children("") {
+		if child.Hash != tree.Hash(child.Path) {
+			mismatched = append(mismatched, child.Path)
+		}
+	}
+	if len(mismatched) != 1 || mismatched[0] != leafPath[:1] {
+		t.Errorf("expected mismatched child %s, but got %v", leafPath[:1], mismatched)
+	}
+
+	leaf := changedTree.Node(leafPath)
+	if !changedTree.IsLeaf(leafPath) || len(leaf.StatusHashes) == 0 {
+		t.Errorf("expected leaf node with status hashes, but got %v", leaf)
+	}
+	for _, statusHash := range leaf.StatusHashes {
+		if !changedTree.Contains(leafPath, statusHash.ResourceID) {
+			t.Errorf("unexpected resource %s in leaf %s", statusHash.ResourceID, leafPath)
+		}
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
 type ResourceVersion struct {
@@ -46,3 +49,131 @@ func DecodeStatusResyncRequest(evt cloudevents.Event) (*ResourceStatusHashList,
 	}
 	return hashes, nil
 }
+
+// AgentHeartbeat is the payload of an agent heartbeat event.
+type AgentHeartbeat struct {
+	// AgentID is the ID of the agent.
+	AgentID string `json:"agentID"`
+
+	// ClusterName is the name of the managed cluster on which the agent runs.
+	ClusterName string `json:"clusterName"`
+
+	// DataTypes are the cloud events data types that are supported by the agent.
+	DataTypes []string `json:"dataTypes,omitempty"`
+
+	// SDKVersion is the version of the sdk-go that the agent is built with.
+	SDKVersion string `json:"sdkVersion,omitempty"`
+}
+
+func DecodeHeartbeat(evt cloudevents.Event) (*AgentHeartbeat, error) {
+	heartbeat := &AgentHeartbeat{}
+	data := evt.Data()
+	if err := json.Unmarshal(data, heartbeat); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal heartbeat payload %s, %v", string(data), err)
+	}
+	return heartbeat, nil
+}
+
+// ResyncChunk identifies a chunk of a resync request that is split into several events.
+type ResyncChunk struct {
+	// ID is shared by all of the chunks of one resync request.
+	ID string
+
+	// Index is the index of the chunk, it starts from zero.
+	Index int
+
+	// Total is the total number of the chunks of the resync request.
+	Total int
+}
+
+// GetResyncChunk returns the chunk of a resync request event. If the event is not chunked, nil is returned, the event
+// carries the whole resync request.
+func GetResyncChunk(evt cloudevents.Event) (*ResyncChunk, error) {
+	extensions := evt.Extensions()
+	if _, ok := extensions[types.ExtensionChunkID]; !ok {
+		return nil, nil
+	}
+
+	id, err := cloudeventstypes.ToString(extensions[types.ExtensionChunkID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chunk id extension, %v", err)
+	}
+
+	index, err := cloudeventstypes.ToInteger(extensions[types.ExtensionChunkIndex])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chunk index extension, %v", err)
+	}
+
+	total, err := cloudeventstypes.ToInteger(extensions[types.ExtensionChunkTotal])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chunk total extension, %v", err)
+	}
+
+	if total <= 0 || index < 0 || index >= total {
+		return nil, fmt.Errorf("invalid chunk %d/%d of %s", index, total, id)
+	}
+
+	return &ResyncChunk{ID: id, Index: int(index), Total: int(total)}, nil
+}
+
+// SplitResourceVersionList splits the resource versions into chunks, each chunk has at most size resource versions.
+// If the size is less than or equal to zero, the list is not split.
+func SplitResourceVersionList(list *ResourceVersionList, size int) []*ResourceVersionList {
+	chunks := []*ResourceVersionList{}
+	for _, versions := range split(list.Versions, size) {
+		chunks = append(chunks, &ResourceVersionList{Versions: versions})
+	}
+	return chunks
+}
+
+// SplitResourceStatusHashList splits the resource status hashes into chunks, each chunk has at most size status
+// hashes. If the size is less than or equal to zero, the list is not split.
+func SplitResourceStatusHashList(list *ResourceStatusHashList, size int) []*ResourceStatusHashList {
+	chunks := []*ResourceStatusHashList{}
+	for _, hashes := range split(list.Hashes, size) {
+		chunks = append(chunks, &ResourceStatusHashList{Hashes: hashes})
+	}
+	return chunks
+}
+
+// MergeSpecResyncRequests merges the chunks of a spec resync request.
+func MergeSpecResyncRequests(evts []cloudevents.Event) (*ResourceVersionList, error) {
+	merged := &ResourceVersionList{}
+	for _, evt := range evts {
+		versions, err := DecodeSpecResyncRequest(evt)
+		if err != nil {
+			return nil, err
+		}
+		merged.Versions = append(merged.Versions, versions.Versions...)
+	}
+	return merged, nil
+}
+
+// MergeStatusResyncRequests merges the chunks of a status resync request.
+func MergeStatusResyncRequests(evts []cloudevents.Event) (*ResourceStatusHashList, error) {
+	merged := &ResourceStatusHashList{}
+	for _, evt := range evts {
+		hashes, err := DecodeStatusResyncRequest(evt)
+		if err != nil {
+			return nil, err
+		}
+		merged.Hashes = append(merged.Hashes, hashes.Hashes...)
+	}
+	return merged, nil
+}
+
+func split[E any](items []E, size int) [][]E {
+	if size <= 0 || len(items) <= size {
+		return [][]E{items}
+	}
+
+	chunks := [][]E{}
+	for start := 0; start < len(items); start += size {
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+		chunks = append(chunks, items[start:end])
+	}
+	return chunks
+}
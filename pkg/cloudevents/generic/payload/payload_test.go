@@ -1,9 +1,12 @@
 package payload
 
 import (
+	"reflect"
 	"testing"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
 func TestDecodeSpecResyncRequest(t *testing.T) {
@@ -37,3 +40,103 @@ func TestDecodeStatusResyncRequest(t *testing.T) {
 		t.Errorf("unexpected versions %v", hashes)
 	}
 }
+
+func TestSplitResourceVersionList(t *testing.T) {
+	cases := []struct {
+		name           string
+		versions       int
+		size           int
+		expectedChunks []int
+	}{
+		{
+			name:           "not split",
+			versions:       3,
+			size:           0,
+			expectedChunks: []int{3},
+		},
+		{
+			name:           "empty list",
+			versions:       0,
+			size:           2,
+			expectedChunks: []int{0},
+		},
+		{
+			name:           "split into chunks",
+			versions:       5,
+			size:           2,
+			expectedChunks: []int{2, 2, 1},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			list := &ResourceVersionList{Versions: make([]ResourceVersion, c.versions)}
+			chunks := SplitResourceVersionList(list, c.size)
+			if len(chunks) != len(c.expectedChunks) {
+				t.Fatalf("expected %d chunks, but got %d", len(c.expectedChunks), len(chunks))
+			}
+
+			for i, chunk := range chunks {
+				if len(chunk.Versions) != c.expectedChunks[i] {
+					t.Errorf("expected %d versions in chunk %d, but got %d", c.expectedChunks[i], i, len(chunk.Versions))
+				}
+			}
+		})
+	}
+}
+
+func TestMergeStatusResyncRequests(t *testing.T) {
+	list := &ResourceStatusHashList{Hashes: []ResourceStatusHash{
+		{ResourceID: "1", StatusHash: "a"},
+		{ResourceID: "2", StatusHash: "b"},
+		{ResourceID: "3", StatusHash: "c"},
+	}}
+
+	evts := []cloudevents.Event{}
+	for i, chunk := range SplitResourceStatusHashList(list, 2) {
+		evt := cloudevents.NewEvent()
+		evt.SetExtension(types.ExtensionChunkID, "test")
+		evt.SetExtension(types.ExtensionChunkIndex, i)
+		evt.SetExtension(types.ExtensionChunkTotal, 2)
+		if err := evt.SetData(cloudevents.ApplicationJSON, chunk); err != nil {
+			t.Fatal(err)
+		}
+
+		chunkInfo, err := GetResyncChunk(evt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if chunkInfo.ID != "test" || chunkInfo.Index != i || chunkInfo.Total != 2 {
+			t.Errorf("unexpected chunk %v", chunkInfo)
+		}
+
+		evts = append(evts, evt)
+	}
+
+	merged, err := MergeStatusResyncRequests(evts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(list, merged) {
+		t.Errorf("expected %v, but got %v", list, merged)
+	}
+}
+
+func TestGetResyncChunk(t *testing.T) {
+	evt := cloudevents.NewEvent()
+	chunk, err := GetResyncChunk(evt)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if chunk != nil {
+		t.Errorf("expected no chunk, but got %v", chunk)
+	}
+
+	evt.SetExtension(types.ExtensionChunkID, "test")
+	evt.SetExtension(types.ExtensionChunkIndex, 2)
+	evt.SetExtension(types.ExtensionChunkTotal, 2)
+	if _, err := GetResyncChunk(evt); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+}
@@ -1,11 +1,15 @@
 package generic
 
 import (
+	"context"
+	"sync"
 	"time"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"k8s.io/client-go/util/flowcontrol"
 
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
 // longThrottleLatency defines threshold for logging requests. All requests being
@@ -19,6 +23,58 @@ const (
 	DefaultBurst int     = 100
 )
 
+// eventPriority is the priority of a sent event, an event with a lower value is sent first.
+type eventPriority int
+
+const (
+	// priorityChange is the priority of the events that carry the spec/status changes of the resources.
+	priorityChange eventPriority = iota
+	// priorityResync is the priority of the resync requests and responses.
+	priorityResync
+	// priorityBroadcast is the priority of the events that are broadcast to all clusters/sources.
+	priorityBroadcast
+
+	numEventPriorities
+)
+
+func (p eventPriority) String() string {
+	switch p {
+	case priorityChange:
+		return "change"
+	case priorityResync:
+		return "resync"
+	default:
+		return "broadcast"
+	}
+}
+
+// eventPriorityOf returns the priority of an event that is sent to the recipient, the recipient is empty if the
+// event is broadcast.
+func eventPriorityOf(evt cloudevents.Event, recipient string) eventPriority {
+	if len(recipient) == 0 {
+		return priorityBroadcast
+	}
+
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return priorityChange
+	}
+
+	switch eventType.Action {
+	case types.ResyncRequestAction, types.ResyncResponseAction,
+		types.MerkleResyncRequestAction, types.MerkleResyncResponseAction:
+		return priorityResync
+	default:
+		return priorityChange
+	}
+}
+
+// eventRateLimiter limits the rate of the sent events.
+type eventRateLimiter interface {
+	// Wait blocks until an event with the priority can be sent to the recipient or the context is done.
+	Wait(ctx context.Context, priority eventPriority, recipient string) error
+}
+
 func NewRateLimiter(limit options.EventRateLimit) flowcontrol.RateLimiter {
 	qps := limit.QPS
 	if qps <= 0.0 {
@@ -32,3 +88,189 @@ func NewRateLimiter(limit options.EventRateLimit) flowcontrol.RateLimiter {
 
 	return flowcontrol.NewTokenBucketRateLimiter(qps, burst)
 }
+
+func newEventRateLimiter(limit options.EventRateLimit) eventRateLimiter {
+	if limit.PriorityAndFairness {
+		return newFairRateLimiter(NewRateLimiter(limit))
+	}
+
+	return &globalRateLimiter{limiter: NewRateLimiter(limit)}
+}
+
+// globalRateLimiter sends the events in the order they're published.
+type globalRateLimiter struct {
+	limiter flowcontrol.RateLimiter
+}
+
+func (l *globalRateLimiter) Wait(ctx context.Context, _ eventPriority, _ string) error {
+	return l.limiter.Wait(ctx)
+}
+
+// fairRateLimiter queues the throttled events by their priorities and recipients. Once a token of the limiter is
+// available, it's granted to the first waiter of the next recipient in the highest priority queue that has waiters,
+// the recipients of a queue are served in turn.
+type fairRateLimiter struct {
+	sync.Mutex
+
+	limiter flowcontrol.RateLimiter
+	queues  [numEventPriorities]*fairQueue
+	// pending is the number of the waiters that are not canceled
+	pending     int
+	dispatching bool
+	// cancelDispatch cancels the token waiting of the dispatcher once all waiters are canceled
+	cancelDispatch context.CancelFunc
+	// spare is true if a token is taken for the waiters that are canceled, it's granted to the next waiter
+	spare bool
+}
+
+type rateLimitWaiter struct {
+	granted  chan struct{}
+	canceled bool
+}
+
+func newFairRateLimiter(limiter flowcontrol.RateLimiter) *fairRateLimiter {
+	l := &fairRateLimiter{limiter: limiter}
+	l.resetQueues()
+	return l
+}
+
+func (l *fairRateLimiter) Wait(ctx context.Context, priority eventPriority, recipient string) error {
+	l.Lock()
+	if l.pending == 0 && (l.spare || l.limiter.TryAccept()) {
+		// no one is waiting, send the event immediately
+		l.spare = false
+		l.Unlock()
+		return nil
+	}
+
+	waiter := &rateLimitWaiter{granted: make(chan struct{})}
+	l.queues[priority].push(recipient, waiter)
+	l.pending++
+	if !l.dispatching {
+		l.dispatching = true
+		go l.dispatch()
+	}
+	l.Unlock()
+
+	select {
+	case <-waiter.granted:
+		return nil
+	case <-ctx.Done():
+		l.Lock()
+		defer l.Unlock()
+
+		select {
+		case <-waiter.granted:
+			// the token is granted before the waiter is canceled
+			return nil
+		default:
+		}
+
+		waiter.canceled = true
+		l.pending--
+		if l.pending == 0 && l.cancelDispatch != nil {
+			// no one is waiting for the token that the dispatcher is waiting for
+			l.cancelDispatch()
+		}
+		return ctx.Err()
+	}
+}
+
+// dispatch grants the tokens to the waiters until there is no waiter.
+func (l *fairRateLimiter) dispatch() {
+	for {
+		l.Lock()
+		if l.pending == 0 {
+			// drop the canceled waiters
+			l.resetQueues()
+			l.dispatching = false
+			l.Unlock()
+			return
+		}
+		spare := l.spare
+		l.spare = false
+		ctx, cancel := context.WithCancel(context.Background())
+		l.cancelDispatch = cancel
+		l.Unlock()
+
+		// wait for a token before picking a waiter, so that the token is granted to the waiter that has the highest
+		// priority at the time the token is available. The waiting is canceled if all waiters are canceled, the
+		// token is not taken from the limiter in that case.
+		var err error
+		if !spare {
+			err = l.limiter.Wait(ctx)
+		}
+		cancel()
+
+		l.Lock()
+		l.cancelDispatch = nil
+		if err != nil {
+			l.Unlock()
+			continue
+		}
+
+		granted := false
+		for _, queue := range l.queues {
+			if waiter := queue.pop(); waiter != nil {
+				l.pending--
+				close(waiter.granted)
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			// the waiters are canceled after the token is taken, keep the token for the next waiter
+			l.spare = true
+		}
+		l.Unlock()
+	}
+}
+
+func (l *fairRateLimiter) resetQueues() {
+	for i := range l.queues {
+		l.queues[i] = &fairQueue{waiters: map[string][]*rateLimitWaiter{}}
+	}
+}
+
+// fairQueue queues the waiters by their recipients.
+type fairQueue struct {
+	// recipients are the recipients that have waiters in the order they're served
+	recipients []string
+	waiters    map[string][]*rateLimitWaiter
+}
+
+func (q *fairQueue) push(recipient string, waiter *rateLimitWaiter) {
+	if _, ok := q.waiters[recipient]; !ok {
+		q.recipients = append(q.recipients, recipient)
+	}
+	q.waiters[recipient] = append(q.waiters[recipient], waiter)
+}
+
+// pop returns the first waiter that is not canceled of the next recipient, the recipient is moved to the end of
+// the queue if it still has waiters. It returns nil if there is no such waiter.
+func (q *fairQueue) pop() *rateLimitWaiter {
+	for len(q.recipients) > 0 {
+		recipient := q.recipients[0]
+		q.recipients = q.recipients[1:]
+
+		// drop the canceled waiters, they do not take the turn of the recipient
+		waiters := q.waiters[recipient]
+		for len(waiters) > 0 && waiters[0].canceled {
+			waiters = waiters[1:]
+		}
+		if len(waiters) == 0 {
+			delete(q.waiters, recipient)
+			continue
+		}
+
+		if len(waiters) == 1 {
+			delete(q.waiters, recipient)
+		} else {
+			q.waiters[recipient] = waiters[1:]
+			q.recipients = append(q.recipients, recipient)
+		}
+		return waiters[0]
+	}
+
+	return nil
+}
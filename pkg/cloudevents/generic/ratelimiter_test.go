@@ -0,0 +1,202 @@
+package generic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// manualRateLimiter grants a token each time a token is put to its channel.
+type manualRateLimiter struct {
+	tokens chan struct{}
+}
+
+func (l *manualRateLimiter) TryAccept() bool {
+	select {
+	case <-l.tokens:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *manualRateLimiter) Accept() {
+	<-l.tokens
+}
+
+func (l *manualRateLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-l.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *manualRateLimiter) Stop() {}
+
+func (l *manualRateLimiter) QPS() float32 {
+	return 0
+}
+
+func TestEventPriorityOf(t *testing.T) {
+	cases := []struct {
+		name             string
+		action           types.EventAction
+		recipient        string
+		expectedPriority eventPriority
+	}{
+		{
+			name:             "spec change",
+			action:           "create_request",
+			recipient:        "cluster1",
+			expectedPriority: priorityChange,
+		},
+		{
+			name:             "resync response",
+			action:           types.ResyncResponseAction,
+			recipient:        "cluster1",
+			expectedPriority: priorityResync,
+		},
+		{
+			name:             "merkle resync request",
+			action:           types.MerkleResyncRequestAction,
+			recipient:        "cluster1",
+			expectedPriority: priorityResync,
+		},
+		{
+			name:             "broadcast resync request",
+			action:           types.ResyncRequestAction,
+			recipient:        types.ClusterAll,
+			expectedPriority: priorityBroadcast,
+		},
+		{
+			name:             "heartbeat",
+			action:           types.HeartbeatAction,
+			recipient:        types.SourceAll,
+			expectedPriority: priorityBroadcast,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			evt := types.NewEventBuilder(testSourceName, types.CloudEventsType{
+				CloudEventsDataType: mockEventDataType,
+				SubResource:         types.SubResourceSpec,
+				Action:              c.action,
+			}).NewEvent()
+			require.Equal(t, c.expectedPriority, eventPriorityOf(evt, c.recipient))
+		})
+	}
+}
+
+func TestFairRateLimiter(t *testing.T) {
+	limiter := &manualRateLimiter{tokens: make(chan struct{}, 1)}
+	fairLimiter := newFairRateLimiter(limiter)
+
+	// the token is taken immediately if there is no waiter
+	limiter.tokens <- struct{}{}
+	require.NoError(t, fairLimiter.Wait(context.TODO(), priorityBroadcast, ""))
+
+	waiters := []struct {
+		name      string
+		priority  eventPriority
+		recipient string
+	}{
+		{name: "broadcast", priority: priorityBroadcast},
+		{name: "cluster1-resync", priority: priorityResync, recipient: "cluster1"},
+		{name: "cluster1-change-1", priority: priorityChange, recipient: "cluster1"},
+		{name: "cluster1-change-2", priority: priorityChange, recipient: "cluster1"},
+		{name: "cluster1-change-3", priority: priorityChange, recipient: "cluster1"},
+		{name: "cluster2-change-1", priority: priorityChange, recipient: "cluster2"},
+		{name: "cluster3-change-1", priority: priorityChange, recipient: "cluster3"},
+	}
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error, 1)
+	go func() {
+		canceled <- fairLimiter.Wait(canceledCtx, priorityChange, "cluster2")
+	}()
+	waitForPending(t, fairLimiter, 1)
+
+	granted := make(chan string, len(waiters))
+	for i, w := range waiters {
+		go func() {
+			if err := fairLimiter.Wait(context.TODO(), w.priority, w.recipient); err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+			granted <- w.name
+		}()
+		waitForPending(t, fairLimiter, i+2)
+	}
+
+	// the canceled waiter is skipped, but cluster2 keeps its turn
+	cancel()
+	require.ErrorIs(t, <-canceled, context.Canceled)
+
+	actual := []string{}
+	for range waiters {
+		limiter.tokens <- struct{}{}
+		select {
+		case name := <-granted:
+			actual = append(actual, name)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout to wait for the granted waiter")
+		}
+	}
+
+	require.Equal(t, []string{
+		"cluster2-change-1",
+		"cluster1-change-1",
+		"cluster3-change-1",
+		"cluster1-change-2",
+		"cluster1-change-3",
+		"cluster1-resync",
+		"broadcast",
+	}, actual)
+
+	// the dispatcher stops once there is no waiter
+	require.Eventually(t, func() bool {
+		fairLimiter.Lock()
+		defer fairLimiter.Unlock()
+		return !fairLimiter.dispatching
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestFairRateLimiterAllWaitersCanceled(t *testing.T) {
+	limiter := &manualRateLimiter{tokens: make(chan struct{}, 1)}
+	fairLimiter := newFairRateLimiter(limiter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error, 1)
+	go func() {
+		canceled <- fairLimiter.Wait(ctx, priorityChange, "cluster1")
+	}()
+	waitForPending(t, fairLimiter, 1)
+
+	// the dispatcher stops waiting for a token once all waiters are canceled
+	cancel()
+	require.ErrorIs(t, <-canceled, context.Canceled)
+	require.Eventually(t, func() bool {
+		fairLimiter.Lock()
+		defer fairLimiter.Unlock()
+		return !fairLimiter.dispatching
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the next token is not taken by the stopped dispatcher
+	limiter.tokens <- struct{}{}
+	require.NoError(t, fairLimiter.Wait(context.TODO(), priorityChange, "cluster1"))
+	require.Empty(t, limiter.tokens)
+}
+
+func waitForPending(t *testing.T, limiter *fairRateLimiter, pending int) {
+	require.Eventually(t, func() bool {
+		limiter.Lock()
+		defer limiter.Unlock()
+		return limiter.pending == pending
+	}, 5*time.Second, time.Millisecond)
+}
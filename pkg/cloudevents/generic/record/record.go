@@ -0,0 +1,119 @@
+package record
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// Direction represents whether a recorded event is sent or received by a client.
+type Direction string
+
+const (
+	// DirectionSent represents the event is sent by the client.
+	DirectionSent Direction = "sent"
+
+	// DirectionReceived represents the event is received by the client.
+	DirectionReceived Direction = "received"
+)
+
+// Record is a sent or received event that is recorded by a Recorder.
+type Record struct {
+	// Direction represents whether the event is sent or received.
+	Direction Direction `json:"direction"`
+
+	// Timestamp is the time when the event is sent or received.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Event is the recorded event, it's encoded in the structured CloudEvents JSON format.
+	Event cloudevents.Event `json:"event"`
+}
+
+// LoadFile reads the records from a file that is written by a Recorder. The rotated backups of the file are read
+// before the file, so the records are returned in the order they're recorded.
+func LoadFile(path string) ([]Record, error) {
+	backups, err := backupPaths(path)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []Record{}
+	for _, p := range append(backups, path) {
+		fileRecords, err := loadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, fileRecords...)
+	}
+
+	return records, nil
+}
+
+func loadFile(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records := []Record{}
+	scanner := bufio.NewScanner(file)
+	// an event may be larger than the default max token size (64KB)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		record := Record{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal record %s, %v", scanner.Text(), err)
+		}
+
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// backupPaths returns the paths of the rotated backups of the file from the oldest to the newest.
+func backupPaths(path string) ([]string, error) {
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := []int{}
+	for _, match := range matches {
+		index, err := strconv.Atoi(strings.TrimPrefix(match, path+"."))
+		if err != nil || index <= 0 {
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+
+	// the backup that has a larger index is older
+	sort.Sort(sort.Reverse(sort.IntSlice(indexes)))
+
+	paths := []string{}
+	for _, index := range indexes {
+		paths = append(paths, backupPath(path, index))
+	}
+	return paths, nil
+}
+
+func backupPath(path string, index int) string {
+	return fmt.Sprintf("%s.%d", path, index)
+}
@@ -0,0 +1,152 @@
+package record
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/stretchr/testify/require"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+)
+
+func newEvent(id string) cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetID(id)
+	evt.SetSource("test-source")
+	evt.SetType("test.v1.resources.spec.create_request")
+	evt.SetExtension("resourceid", id)
+	_ = evt.SetData(cloudevents.ApplicationJSON, map[string]string{"id": id})
+	return evt
+}
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	sourceOptions := fake.NewSourceOptions(gochan.New(), "source1")
+	recorder, err := NewRecorder(sourceOptions.CloudEventsOptions, RecorderOptions{Path: path})
+	require.NoError(t, err)
+
+	p, err := recorder.Protocol(context.TODO())
+	require.NoError(t, err)
+
+	// the events are sent to and received from the same channel
+	for i := 0; i < 2; i++ {
+		evt := newEvent(fmt.Sprintf("%d", i))
+		require.NoError(t, p.Send(context.TODO(), binding.ToMessage(&evt)))
+
+		m, err := p.Receive(context.TODO())
+		require.NoError(t, err)
+		received, err := binding.ToEvent(context.TODO(), m)
+		require.NoError(t, err)
+		require.Equal(t, evt.ID(), received.ID())
+		require.NoError(t, m.Finish(nil))
+	}
+	require.NoError(t, recorder.Close())
+
+	records, err := LoadFile(path)
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	for i, record := range records {
+		expectedDirection := DirectionSent
+		if i%2 == 1 {
+			expectedDirection = DirectionReceived
+		}
+		require.Equal(t, expectedDirection, record.Direction)
+		require.Equal(t, fmt.Sprintf("%d", i/2), record.Event.ID())
+		require.Equal(t, newEvent(record.Event.ID()).Data(), record.Event.Data())
+	}
+}
+
+func TestRecorderRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	// each file holds one record
+	recorder, err := NewRecorder(fake.NewSourceOptions(gochan.New(), "source1").CloudEventsOptions,
+		RecorderOptions{Path: path, MaxSize: 1, MaxBackups: 2})
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		recorder.record(DirectionSent, newEvent(fmt.Sprintf("%d", i)))
+	}
+	require.NoError(t, recorder.Close())
+
+	_, err = os.Stat(path + ".3")
+	require.True(t, os.IsNotExist(err))
+
+	// the oldest records are removed with the oldest backups
+	records, err := LoadFile(path)
+	require.NoError(t, err)
+	ids := []string{}
+	for _, record := range records {
+		ids = append(ids, record.Event.ID())
+	}
+	require.Equal(t, []string{"2", "3", "4"}, ids)
+}
+
+func TestReplayOptions(t *testing.T) {
+	now := time.Now()
+	records := []Record{
+		{Direction: DirectionReceived, Timestamp: now, Event: newEvent("0")},
+		{Direction: DirectionSent, Timestamp: now.Add(time.Second), Event: newEvent("1")},
+		{Direction: DirectionReceived, Timestamp: now.Add(2 * time.Second), Event: newEvent("2")},
+	}
+
+	// the recorded interval of 2 seconds is replayed in 200 milliseconds
+	replay := NewReplayOptions(records, 10)
+	p, err := replay.Protocol(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, 2, replay.Remaining())
+
+	start := time.Now()
+	for _, id := range []string{"0", "2"} {
+		m, err := p.Receive(context.TODO())
+		require.NoError(t, err)
+		evt, err := binding.ToEvent(context.TODO(), m)
+		require.NoError(t, err)
+		require.Equal(t, id, evt.ID())
+	}
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	require.Equal(t, 0, replay.Remaining())
+
+	// the sent events are kept
+	evt := newEvent("3")
+	require.NoError(t, p.Send(context.TODO(), binding.ToMessage(&evt)))
+	require.Len(t, replay.Sent(), 1)
+	require.Equal(t, "3", replay.Sent()[0].ID())
+
+	// the receiving is blocked until the protocol is closed
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = p.Close(context.TODO())
+	}()
+	_, err = p.Receive(context.TODO())
+	require.Equal(t, io.EOF, err)
+}
+
+func TestReplayOptionsWithoutWaiting(t *testing.T) {
+	now := time.Now()
+	replay := NewReplayOptions([]Record{
+		{Direction: DirectionReceived, Timestamp: now, Event: newEvent("0")},
+		{Direction: DirectionReceived, Timestamp: now.Add(time.Hour), Event: newEvent("1")},
+	}, 0)
+	p, err := replay.Protocol(context.TODO())
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := p.Receive(context.TODO())
+		require.NoError(t, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	defer cancel()
+	_, err = p.Receive(ctx)
+	require.Equal(t, io.EOF, err)
+}
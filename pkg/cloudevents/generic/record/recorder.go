@@ -0,0 +1,273 @@
+package record
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+
+	"k8s.io/apimachinery/pkg/util/runtime"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+const (
+	DefaultMaxSize    = 100 * 1024 * 1024
+	DefaultMaxBackups = 5
+)
+
+// RecorderOptions is the configuration of a Recorder.
+type RecorderOptions struct {
+	// Path is the path of the file that the events are recorded to, the file is created if it does not exist.
+	Path string
+
+	// MaxSize is the maximum size of the file in bytes, the file is rotated before it exceeds the size.
+	// If it's less than or equal to zero, the DefaultMaxSize (100MiB) will be used.
+	MaxSize int64
+
+	// MaxBackups is the maximum number of the rotated backups of the file, the oldest backups are removed when
+	// the file is rotated. The backups are named by appending a number to the path, e.g. events.jsonl.1 is the
+	// newest backup.
+	// If it's less than or equal to zero, the DefaultMaxBackups (5) will be used.
+	MaxBackups int
+}
+
+// Recorder wraps a CloudEventsOptions and appends every event that is sent or received by its protocol to a file in
+// JSON Lines format, each line is a JSON encoded Record whose event is in the structured CloudEvents JSON format.
+// The file is rotated by its size, the recorded file can be loaded with LoadFile and replayed with a ReplayOptions.
+type Recorder struct {
+	options.CloudEventsOptions
+
+	file *rotatingFile
+}
+
+var _ options.CloudEventsOptions = &Recorder{}
+var _ options.PipelinedSender = &Recorder{}
+
+// NewRecorder returns a Recorder that records the events of the given CloudEventsOptions.
+func NewRecorder(cloudEventsOptions options.CloudEventsOptions, opts RecorderOptions) (*Recorder, error) {
+	maxSize := opts.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+
+	maxBackups := opts.MaxBackups
+	if maxBackups <= 0 {
+		maxBackups = DefaultMaxBackups
+	}
+
+	file, err := openRotatingFile(opts.Path, maxSize, maxBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recorder{CloudEventsOptions: cloudEventsOptions, file: file}, nil
+}
+
+// Protocol returns the protocol of the wrapped CloudEventsOptions, the events that are sent or received by the
+// protocol are recorded.
+func (r *Recorder) Protocol(ctx context.Context) (options.CloudEventsProtocol, error) {
+	p, err := r.CloudEventsOptions.Protocol(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recording := &recordingProtocol{CloudEventsProtocol: p, recorder: r}
+
+	// the cloudevents client opens the inbound connection of the protocol if the protocol is an opener
+	if opener, ok := p.(protocol.Opener); ok {
+		return &recordingOpenerProtocol{recordingProtocol: recording, opener: opener}, nil
+	}
+
+	return recording, nil
+}
+
+// MaxInflightEvents returns the maximum inflight events of the wrapped CloudEventsOptions, it's 1 if the wrapped
+// CloudEventsOptions does not send the events in a pipeline.
+func (r *Recorder) MaxInflightEvents() int {
+	if sender, ok := r.CloudEventsOptions.(options.PipelinedSender); ok {
+		return sender.MaxInflightEvents()
+	}
+	return 1
+}
+
+// Close closes the file.
+func (r *Recorder) Close() error {
+	return r.file.close()
+}
+
+func (r *Recorder) record(direction Direction, evt cloudevents.Event) {
+	data, err := json.Marshal(Record{Direction: direction, Timestamp: time.Now(), Event: evt})
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("failed to marshal the record of event %s, %v", evt.ID(), err))
+		return
+	}
+
+	if err := r.file.write(append(data, '\n')); err != nil {
+		runtime.HandleError(fmt.Errorf("failed to record event %s, %v", evt.ID(), err))
+	}
+}
+
+type recordingProtocol struct {
+	options.CloudEventsProtocol
+
+	recorder *Recorder
+}
+
+func (p *recordingProtocol) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) error {
+	evt, err := binding.ToEvent(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	result := p.CloudEventsProtocol.Send(ctx, binding.ToMessage(evt), transformers...)
+	if !cloudevents.IsUndelivered(result) {
+		p.recorder.record(DirectionSent, *evt)
+	}
+
+	return result
+}
+
+func (p *recordingProtocol) Receive(ctx context.Context) (binding.Message, error) {
+	m, err := p.CloudEventsProtocol.Receive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	evt, err := binding.ToEvent(ctx, m)
+	if err != nil {
+		_ = m.Finish(err)
+		return nil, err
+	}
+
+	p.recorder.record(DirectionReceived, *evt)
+
+	// the original message is read, it's finished with the message that is read from it
+	return binding.WithFinish(binding.ToMessage(evt), func(err error) {
+		_ = m.Finish(err)
+	}), nil
+}
+
+type recordingOpenerProtocol struct {
+	*recordingProtocol
+
+	opener protocol.Opener
+}
+
+func (p *recordingOpenerProtocol) OpenInbound(ctx context.Context) error {
+	return p.opener.OpenInbound(ctx)
+}
+
+// rotatingFile is a file that is rotated before it exceeds the max size.
+type rotatingFile struct {
+	sync.Mutex
+
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open record file %s, %v", f.path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat record file %s, %v", f.path, err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+func (f *rotatingFile) write(data []byte) error {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.file == nil {
+		return fmt.Errorf("the record file %s is closed", f.path)
+	}
+
+	// a record that is larger than the max size is written to an empty file
+	if f.size > 0 && f.size+int64(len(data)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := f.file.Write(data)
+	f.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write record to %s, %v", f.path, err)
+	}
+
+	return nil
+}
+
+// rotate renames the file to the newest backup and opens a new file, the oldest backup is removed. The file is
+// reopened even if the backups fail to be renamed, so the recording continues.
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close record file %s, %v", f.path, err)
+	}
+	f.file = nil
+
+	rotateErr := f.renameBackups()
+	if err := f.open(); err != nil {
+		return err
+	}
+	return rotateErr
+}
+
+func (f *rotatingFile) renameBackups() error {
+	if err := os.Remove(backupPath(f.path, f.maxBackups)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove the oldest backup of %s, %v", f.path, err)
+	}
+
+	for i := f.maxBackups - 1; i > 0; i-- {
+		err := os.Rename(backupPath(f.path, i), backupPath(f.path, i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to rotate the backup of %s, %v", f.path, err)
+		}
+	}
+
+	if err := os.Rename(f.path, backupPath(f.path, 1)); err != nil {
+		return fmt.Errorf("failed to rotate record file %s, %v", f.path, err)
+	}
+
+	return nil
+}
+
+func (f *rotatingFile) close() error {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
@@ -0,0 +1,157 @@
+package record
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// ReplayOptions is a CloudEventsOptions that feeds the recorded received events into a source/agent client, so the
+// sequence of the events that are seen by a client can be reproduced. The sent events of the client are not
+// delivered, they're kept to be inspected.
+//
+// The events are replayed at their recorded intervals divided by the speed, e.g. 1 is the original timing and 10
+// is ten times faster. If the speed is less than or equal to zero, the events are replayed without waiting.
+type ReplayOptions struct {
+	sync.Mutex
+
+	// receiving serializes the receivers, so the events are received in order
+	receiving sync.Mutex
+	records   []Record
+	speed     float64
+	started   time.Time
+	next      int
+	sent      []cloudevents.Event
+	errorChan chan error
+}
+
+var _ options.CloudEventsOptions = &ReplayOptions{}
+
+// NewReplayOptions returns a ReplayOptions that replays the received events of the given records.
+func NewReplayOptions(records []Record, speed float64) *ReplayOptions {
+	received := []Record{}
+	for _, record := range records {
+		if record.Direction == DirectionReceived {
+			received = append(received, record)
+		}
+	}
+
+	return &ReplayOptions{
+		records:   received,
+		speed:     speed,
+		errorChan: make(chan error),
+	}
+}
+
+func (o *ReplayOptions) WithContext(ctx context.Context, evtContext cloudevents.EventContext) (context.Context, error) {
+	return ctx, nil
+}
+
+func (o *ReplayOptions) Protocol(ctx context.Context) (options.CloudEventsProtocol, error) {
+	return &replayProtocol{options: o, closed: make(chan struct{})}, nil
+}
+
+func (o *ReplayOptions) ErrorChan() <-chan error {
+	return o.errorChan
+}
+
+// Sent returns the events that are sent by the client during the replay.
+func (o *ReplayOptions) Sent() []cloudevents.Event {
+	o.Lock()
+	defer o.Unlock()
+
+	sent := make([]cloudevents.Event, len(o.sent))
+	copy(sent, o.sent)
+	return sent
+}
+
+// Remaining returns the number of the events that are not replayed yet.
+func (o *ReplayOptions) Remaining() int {
+	o.Lock()
+	defer o.Unlock()
+
+	return len(o.records) - o.next
+}
+
+// delay returns the duration from the start of the replay to the time when the record is replayed.
+func (o *ReplayOptions) delay(record Record) time.Duration {
+	if o.speed <= 0 {
+		return 0
+	}
+
+	return time.Duration(float64(record.Timestamp.Sub(o.records[0].Timestamp)) / o.speed)
+}
+
+type replayProtocol struct {
+	options   *ReplayOptions
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (p *replayProtocol) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) error {
+	evt, err := binding.ToEvent(ctx, m, transformers...)
+	if err != nil {
+		return err
+	}
+
+	p.options.Lock()
+	defer p.options.Unlock()
+
+	p.options.sent = append(p.options.sent, *evt)
+	return nil
+}
+
+// Receive returns the next recorded event when it's due, it returns io.EOF once the protocol is closed or the
+// context is done. The receiving is blocked after all events are replayed.
+func (p *replayProtocol) Receive(ctx context.Context) (binding.Message, error) {
+	p.options.receiving.Lock()
+	defer p.options.receiving.Unlock()
+
+	p.options.Lock()
+	if p.options.next >= len(p.options.records) {
+		p.options.Unlock()
+		select {
+		case <-ctx.Done():
+		case <-p.closed:
+		}
+		return nil, io.EOF
+	}
+
+	// the replay is started when the first event is received
+	if p.options.started.IsZero() {
+		p.options.started = time.Now()
+	}
+	record := p.options.records[p.options.next]
+	due := p.options.started.Add(p.options.delay(record))
+	p.options.Unlock()
+
+	timer := time.NewTimer(time.Until(due))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return nil, io.EOF
+	case <-p.closed:
+		return nil, io.EOF
+	case <-timer.C:
+	}
+
+	p.options.Lock()
+	p.options.next++
+	p.options.Unlock()
+
+	evt := record.Event.Clone()
+	return binding.ToMessage(&evt), nil
+}
+
+func (p *replayProtocol) Close(ctx context.Context) error {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+	})
+	return nil
+}
@@ -0,0 +1,162 @@
+package generic
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	DefaultResyncChunkSize               = 1000
+	DefaultResyncChunkTimeout            = 1 * time.Minute
+	DefaultResyncChunkMaxChunks          = 1000
+	DefaultResyncChunkMaxPendingRequests = 100
+)
+
+// resyncChunker splits the resync requests that are sent by the client into chunks and reassembles the chunked
+// resync requests that are received by the client.
+type resyncChunker struct {
+	sync.Mutex
+
+	// size is the maximum number of the resources in one chunk, zero means the resync requests are not split
+	size    int
+	timeout time.Duration
+	groups  map[string]*resyncChunkGroup
+	// maxChunks and maxGroups limit the received chunks, so a malformed or hostile chunk cannot exhaust the memory
+	maxChunks int
+	maxGroups int
+}
+
+type resyncChunkGroup struct {
+	chunks   []*cloudevents.Event
+	received int
+	created  time.Time
+}
+
+func newResyncChunker(config *options.ResyncChunk) *resyncChunker {
+	chunker := &resyncChunker{
+		timeout:   DefaultResyncChunkTimeout,
+		groups:    map[string]*resyncChunkGroup{},
+		maxChunks: DefaultResyncChunkMaxChunks,
+		maxGroups: DefaultResyncChunkMaxPendingRequests,
+	}
+
+	if config == nil {
+		return chunker
+	}
+
+	if config.MaxChunks > 0 {
+		chunker.maxChunks = config.MaxChunks
+	}
+
+	if config.MaxPendingRequests > 0 {
+		chunker.maxGroups = config.MaxPendingRequests
+	}
+
+	chunker.size = config.Size
+	if chunker.size <= 0 {
+		chunker.size = DefaultResyncChunkSize
+	}
+
+	if config.Timeout > 0 {
+		chunker.timeout = config.Timeout
+	}
+
+	return chunker
+}
+
+// assemble adds a received resync request event. If the event is not chunked, it is returned directly. Otherwise the
+// chunk is kept until all of the chunks of the resync request are received, then a merged resync request event is
+// returned. The returned event is nil if there are remaining chunks.
+func (c *resyncChunker) assemble(eventType types.CloudEventsType, evt cloudevents.Event) (*cloudevents.Event, error) {
+	chunk, err := payload.GetResyncChunk(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	if chunk == nil {
+		return &evt, nil
+	}
+
+	if chunk.Total > c.maxChunks {
+		return nil, fmt.Errorf("the chunk total %d of %s exceeds the limit %d", chunk.Total, chunk.ID, c.maxChunks)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	// drop the chunks that are not completed in time
+	now := time.Now()
+	for key, group := range c.groups {
+		if now.Sub(group.created) > c.timeout {
+			klog.Warningf("drop the resync request %s, only %d of %d chunks are received", key, group.received, len(group.chunks))
+			delete(c.groups, key)
+		}
+	}
+
+	key := fmt.Sprintf("%s/%s", evt.Source(), chunk.ID)
+	group, ok := c.groups[key]
+	if !ok {
+		if len(c.groups) >= c.maxGroups {
+			return nil, fmt.Errorf("too many pending resync requests (limit=%d), reject the chunk of %s", c.maxGroups, key)
+		}
+
+		group = &resyncChunkGroup{chunks: make([]*cloudevents.Event, chunk.Total), created: now}
+		c.groups[key] = group
+	}
+
+	if len(group.chunks) != chunk.Total {
+		return nil, fmt.Errorf("the chunk total %d of %s is mismatched with %d", chunk.Total, key, len(group.chunks))
+	}
+
+	if group.chunks[chunk.Index] == nil {
+		group.chunks[chunk.Index] = &evt
+		group.received++
+	}
+
+	if group.received < len(group.chunks) {
+		return nil, nil
+	}
+
+	delete(c.groups, key)
+	return mergeResyncChunks(eventType, group.chunks)
+}
+
+func mergeResyncChunks(eventType types.CloudEventsType, chunks []*cloudevents.Event) (*cloudevents.Event, error) {
+	evts := make([]cloudevents.Event, len(chunks))
+	for i, chunk := range chunks {
+		evts[i] = *chunk
+	}
+
+	var merged any
+	var err error
+	switch eventType.SubResource {
+	case types.SubResourceSpec:
+		merged, err = payload.MergeSpecResyncRequests(evts)
+	case types.SubResourceStatus:
+		merged, err = payload.MergeStatusResyncRequests(evts)
+	default:
+		err = fmt.Errorf("unsupported resync event type %s", eventType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	evt := evts[0].Clone()
+	evt.SetExtension(types.ExtensionChunkID, nil)
+	evt.SetExtension(types.ExtensionChunkIndex, nil)
+	evt.SetExtension(types.ExtensionChunkTotal, nil)
+	if err := evt.SetData(cloudevents.ApplicationJSON, merged); err != nil {
+		return nil, fmt.Errorf("failed to set data to cloud event: %v", err)
+	}
+
+	return &evt, nil
+}
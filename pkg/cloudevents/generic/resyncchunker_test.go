@@ -0,0 +1,166 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/stretchr/testify/require"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestAgentResyncWithChunks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	resources := []*mockResource{}
+	for i := 0; i < 5; i++ {
+		resources = append(resources, &mockResource{UID: kubetypes.UID(fmt.Sprintf("test%d", i)), ResourceVersion: "1"})
+	}
+
+	agentOptions := fake.NewAgentOptions(gochan.New(), nil, "cluster1", testAgentName)
+	agentOptions.ResyncChunk = &options.ResyncChunk{Size: 2}
+	agent, err := NewCloudEventAgentClient[*mockResource](
+		ctx, agentOptions, newMockResourceLister(resources...), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	var mutex sync.Mutex
+	received := []cloudevents.Event{}
+	go func() {
+		_ = agent.cloudEventsClient.StartReceiver(ctx, func(event cloudevents.Event) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			received = append(received, event)
+		})
+	}()
+
+	require.NoError(t, agent.Resync(ctx, types.SourceAll))
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(received) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ResyncRequestAction,
+	}
+
+	// the chunks are reassembled regardless of their order
+	chunker := newResyncChunker(nil)
+	mutex.Lock()
+	defer mutex.Unlock()
+	for i, evt := range received {
+		request, err := chunker.assemble(eventType, evt)
+		require.NoError(t, err)
+		if i < len(received)-1 {
+			require.Nil(t, request)
+			continue
+		}
+
+		require.NotNil(t, request)
+		_, err = request.Context.GetExtension(types.ExtensionChunkID)
+		require.Error(t, err)
+
+		versions, err := payload.DecodeSpecResyncRequest(*request)
+		require.NoError(t, err)
+		require.Len(t, versions.Versions, len(resources))
+	}
+}
+
+func TestResyncChunkerAssemble(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              types.ResyncRequestAction,
+	}
+
+	newChunk := func(id string, index, total int) cloudevents.Event {
+		evt := types.NewEventBuilder(testSourceName, eventType).WithChunk(id, index, total).NewEvent()
+		hashes := &payload.ResourceStatusHashList{Hashes: []payload.ResourceStatusHash{
+			{ResourceID: fmt.Sprintf("%s-%d", id, index), StatusHash: "hash"},
+		}}
+		require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, hashes))
+		return evt
+	}
+
+	t.Run("not chunked", func(t *testing.T) {
+		evt := types.NewEventBuilder(testSourceName, eventType).NewEvent()
+		request, err := newResyncChunker(nil).assemble(eventType, evt)
+		require.NoError(t, err)
+		require.Equal(t, evt.ID(), request.ID())
+	})
+
+	t.Run("duplicated chunks", func(t *testing.T) {
+		chunker := newResyncChunker(nil)
+		for _, evt := range []cloudevents.Event{newChunk("a", 0, 2), newChunk("a", 0, 2)} {
+			request, err := chunker.assemble(eventType, evt)
+			require.NoError(t, err)
+			require.Nil(t, request)
+		}
+
+		request, err := chunker.assemble(eventType, newChunk("a", 1, 2))
+		require.NoError(t, err)
+		hashes, err := payload.DecodeStatusResyncRequest(*request)
+		require.NoError(t, err)
+		require.Len(t, hashes.Hashes, 2)
+	})
+
+	t.Run("mismatched total", func(t *testing.T) {
+		chunker := newResyncChunker(nil)
+		_, err := chunker.assemble(eventType, newChunk("a", 0, 2))
+		require.NoError(t, err)
+		_, err = chunker.assemble(eventType, newChunk("a", 1, 3))
+		require.Error(t, err)
+	})
+
+	t.Run("too many chunks", func(t *testing.T) {
+		chunker := newResyncChunker(&options.ResyncChunk{MaxChunks: 2})
+		_, err := chunker.assemble(eventType, newChunk("a", 0, 3))
+		require.Error(t, err)
+		require.Empty(t, chunker.groups)
+
+		// the total of the chunks is validated before the chunks are kept
+		_, err = newResyncChunker(nil).assemble(eventType, newChunk("a", 0, DefaultResyncChunkMaxChunks+1))
+		require.Error(t, err)
+	})
+
+	t.Run("too many pending requests", func(t *testing.T) {
+		chunker := newResyncChunker(&options.ResyncChunk{MaxPendingRequests: 1})
+		_, err := chunker.assemble(eventType, newChunk("a", 0, 2))
+		require.NoError(t, err)
+		_, err = chunker.assemble(eventType, newChunk("b", 0, 2))
+		require.Error(t, err)
+
+		// the chunks of the pending request are still accepted
+		request, err := chunker.assemble(eventType, newChunk("a", 1, 2))
+		require.NoError(t, err)
+		require.NotNil(t, request)
+
+		_, err = chunker.assemble(eventType, newChunk("b", 0, 2))
+		require.NoError(t, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		chunker := newResyncChunker(&options.ResyncChunk{Timeout: 10 * time.Millisecond})
+		_, err := chunker.assemble(eventType, newChunk("a", 0, 2))
+		require.NoError(t, err)
+
+		time.Sleep(50 * time.Millisecond)
+
+		// the stale chunks are dropped, the request is not completed
+		request, err := chunker.assemble(eventType, newChunk("a", 1, 2))
+		require.NoError(t, err)
+		require.Nil(t, request)
+	})
+}
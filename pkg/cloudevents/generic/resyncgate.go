@@ -0,0 +1,131 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// resyncGatePollInterval is the interval to check whether the lister has been synced.
+var resyncGatePollInterval = 100 * time.Millisecond
+
+// resyncGate holds the resync requests until the lister is synced. The queued requests are coalesced by their
+// sender, cluster and event type, only the latest request is answered after the lister is synced.
+type resyncGate struct {
+	sync.Mutex
+
+	// ctx is the context of the client, the gate waits for the lister to be synced until it's done
+	ctx context.Context
+	// hasSynced is nil if the lister is not a SyncedLister
+	hasSynced func() bool
+	keys      []string
+	pending   map[string]pendingResyncRequest
+	waiting   bool
+	// waitingSince is the time when the gate starts waiting for the lister to be synced
+	waitingSince time.Time
+}
+
+type pendingResyncRequest struct {
+	ctx     context.Context
+	event   cloudevents.Event
+	respond resyncRespondFunc
+}
+
+type resyncRespondFunc func(ctx context.Context, evt cloudevents.Event)
+
+func newResyncGate[T ResourceObject](ctx context.Context, lister Lister[T]) *resyncGate {
+	gate := &resyncGate{ctx: ctx, pending: map[string]pendingResyncRequest{}}
+	if syncedLister, ok := lister.(SyncedLister[T]); ok {
+		gate.hasSynced = syncedLister.HasSynced
+	}
+	return gate
+}
+
+// listerSynced returns true if the lister is a SyncedLister and it has been synced.
+func (g *resyncGate) listerSynced() bool {
+	return g.hasSynced != nil && g.hasSynced()
+}
+
+// respond answers the resync request with the respond func if the lister has been synced (or the lister is not a
+// SyncedLister), otherwise the request is queued until the lister is synced. The context of a received event may be
+// canceled once the event is processed, so the queued request is answered with a context that only keeps the values of
+// the given context.
+func (g *resyncGate) respond(ctx context.Context, evt cloudevents.Event, respond resyncRespondFunc) {
+	if g.hasSynced == nil || g.hasSynced() {
+		respond(ctx, evt)
+		return
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	key := resyncRequestKey(evt)
+	if _, ok := g.pending[key]; !ok {
+		g.keys = append(g.keys, key)
+	}
+	g.pending[key] = pendingResyncRequest{ctx: context.WithoutCancel(ctx), event: evt, respond: respond}
+	klog.V(4).Infof("the lister is not synced, queue the resync request %s", evt.ID())
+
+	if g.waiting {
+		return
+	}
+
+	g.waiting = true
+	g.waitingSince = time.Now()
+	go g.waitForSync()
+}
+
+func (g *resyncGate) waitForSync() {
+	err := wait.PollUntilContextCancel(g.ctx, resyncGatePollInterval, true, func(ctx context.Context) (bool, error) {
+		return g.hasSynced(), nil
+	})
+
+	g.Lock()
+	requests := []pendingResyncRequest{}
+	for _, key := range g.keys {
+		requests = append(requests, g.pending[key])
+	}
+	g.keys = nil
+	g.pending = map[string]pendingResyncRequest{}
+	g.waiting = false
+	g.Unlock()
+
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("the lister is not synced, drop %d resync requests, %v", len(requests), err))
+		return
+	}
+
+	for _, request := range requests {
+		request.respond(request.ctx, request.event)
+	}
+}
+
+// pendingRequests returns the number of the queued resync requests and the time since when they are queued.
+func (g *resyncGate) pendingRequests() (int, time.Time) {
+	g.Lock()
+	defer g.Unlock()
+
+	if len(g.pending) == 0 {
+		return 0, time.Time{}
+	}
+	return len(g.pending), g.waitingSince
+}
+
+func resyncRequestKey(evt cloudevents.Event) string {
+	clusterName, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionClusterName])
+	if err != nil {
+		clusterName = ""
+	}
+
+	return fmt.Sprintf("%s/%s/%s", evt.Type(), evt.Source(), clusterName)
+}
@@ -0,0 +1,60 @@
+package generic
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResyncGate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lister := &mockSyncedResourceLister{mockResourceLister: newMockResourceLister()}
+	gate := newResyncGate[*mockResource](ctx, lister)
+
+	var mutex sync.Mutex
+	responded := []string{}
+	respond := func(ctx context.Context, evt cloudevents.Event) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		responded = append(responded, evt.ID())
+	}
+
+	newRequest := func(id, clusterName string) cloudevents.Event {
+		evt := cloudevents.NewEvent()
+		evt.SetID(id)
+		evt.SetType("test.v1.resources.spec.resync_request")
+		evt.SetSource("test-agent")
+		evt.SetExtension("clustername", clusterName)
+		return evt
+	}
+
+	// the requests are queued and coalesced before the lister is synced
+	gate.respond(ctx, newRequest("1", "cluster1"), respond)
+	gate.respond(ctx, newRequest("2", "cluster2"), respond)
+	gate.respond(ctx, newRequest("3", "cluster1"), respond)
+
+	time.Sleep(300 * time.Millisecond)
+	mutex.Lock()
+	require.Empty(t, responded)
+	mutex.Unlock()
+
+	lister.setSynced()
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(responded) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the request is answered directly after the lister is synced
+	gate.respond(ctx, newRequest("4", "cluster1"), respond)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.Equal(t, []string{"3", "2", "4"}, responded)
+}
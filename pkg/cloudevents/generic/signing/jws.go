@@ -0,0 +1,283 @@
+package signing
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// jwsHeader is the protected header of a JWS.
+type jwsHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// JWSSigner signs the events with a JWS with detached content, the content is the SHA-256 digest of the canonical
+// form of the event, and the JWS compact serialization without the payload is saved to the signature extension.
+type JWSSigner struct {
+	keyID string
+	key   crypto.Signer
+	alg   string
+}
+
+var _ options.EventSigner = &JWSSigner{}
+
+// NewJWSSigner returns a JWSSigner with the given private key, the ECDSA (P-256, P-384 and P-521), RSA and Ed25519
+// keys are supported. The key ID is set to the header of the JWS, it is usually the source/agent ID.
+func NewJWSSigner(keyID string, key crypto.Signer) (*JWSSigner, error) {
+	alg, err := algorithm(key.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	return &JWSSigner{keyID: keyID, key: key, alg: alg}, nil
+}
+
+// Sign signs the event and saves the signature to the signature extension of the event.
+func (s *JWSSigner) Sign(evt *cloudevents.Event) error {
+	header, err := json.Marshal(&jwsHeader{Algorithm: s.alg, KeyID: s.keyID})
+	if err != nil {
+		return err
+	}
+
+	encodedHeader := base64.RawURLEncoding.EncodeToString(header)
+	signature, err := sign(s.key, s.alg, signingInput(encodedHeader, *evt))
+	if err != nil {
+		return fmt.Errorf("failed to sign the event %s, %v", evt.ID(), err)
+	}
+
+	return evt.Context.SetExtension(types.ExtensionSignature,
+		fmt.Sprintf("%s..%s", encodedHeader, base64.RawURLEncoding.EncodeToString(signature)))
+}
+
+// JWSVerifier verifies the signatures of the events that are signed by a JWSSigner. The public key to verify an event
+// is got from the key store with the source of the event, so a sender cannot impersonate another one.
+type JWSVerifier struct {
+	keys KeyStore
+}
+
+var _ options.EventVerifier = &JWSVerifier{}
+
+// NewJWSVerifier returns a JWSVerifier with the given key store.
+func NewJWSVerifier(keys KeyStore) *JWSVerifier {
+	return &JWSVerifier{keys: keys}
+}
+
+// Verify returns an error if the event is not signed or its signature is invalid.
+func (v *JWSVerifier) Verify(evt cloudevents.Event) error {
+	value, ok := evt.Extensions()[types.ExtensionSignature]
+	if !ok {
+		return fmt.Errorf("the event %s from %s is not signed", evt.ID(), evt.Source())
+	}
+
+	jws, err := cloudeventstypes.ToString(value)
+	if err != nil {
+		return fmt.Errorf("failed to get the signature of the event %s, %v", evt.ID(), err)
+	}
+
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 || len(parts[1]) != 0 {
+		return fmt.Errorf("the signature of the event %s is not a JWS with detached content", evt.ID())
+	}
+
+	headerData, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("failed to decode the signature header of the event %s, %v", evt.ID(), err)
+	}
+
+	header := &jwsHeader{}
+	if err := json.Unmarshal(headerData, header); err != nil {
+		return fmt.Errorf("failed to unmarshal the signature header of the event %s, %v", evt.ID(), err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("failed to decode the signature of the event %s, %v", evt.ID(), err)
+	}
+
+	key, err := v.keys.PublicKey(evt.Source())
+	if err != nil {
+		return fmt.Errorf("failed to get the public key of %s, %v", evt.Source(), err)
+	}
+
+	// the algorithm is determined by the trusted key rather than the header
+	alg, err := algorithm(key)
+	if err != nil {
+		return err
+	}
+	if header.Algorithm != alg {
+		return fmt.Errorf("the signature algorithm %s of the event %s is mismatched with the key of %s",
+			header.Algorithm, evt.ID(), evt.Source())
+	}
+
+	if err := verify(key, alg, signingInput(parts[0], evt), signature); err != nil {
+		return fmt.Errorf("the signature of the event %s from %s is invalid, %v", evt.ID(), evt.Source(), err)
+	}
+
+	return nil
+}
+
+// signingInput returns the JWS signing input, the payload is the SHA-256 digest of the canonical form of the event.
+func signingInput(encodedHeader string, evt cloudevents.Event) []byte {
+	digest := sha256.Sum256(canonicalize(evt))
+	return []byte(encodedHeader + "." + base64.RawURLEncoding.EncodeToString(digest[:]))
+}
+
+// signedExtensions are the extensions that are set by the clients and included in the canonical form of an event.
+// The extensions that are added by the transports on receipt, e.g. the kafkaoffset of the Kafka binary mode, and the
+// trace context extensions that may be rewritten by the intermediaries are not signed. The extensions are written to
+// the canonical form in this order.
+var signedExtensions = []string{
+	types.ExtensionResourceID,
+	types.ExtensionResourceVersion,
+	types.ExtensionStatusUpdateSequenceID,
+	types.ExtensionDeletionTimestamp,
+	types.ExtensionClusterName,
+	types.ExtensionOriginalSource,
+	types.ExtensionChunkID,
+	types.ExtensionChunkIndex,
+	types.ExtensionChunkTotal,
+	types.ExtensionAcceptEncodings,
+	types.ExtensionDataVersions,
+	types.ExtensionEncryptedKey,
+	types.ExtensionPlainContentType,
+	// the work metadata extension of the manifest bundle codecs
+	"metadata",
+}
+
+// canonicalize returns the canonical form of an event, it includes the attributes that are not changed by the
+// transports, the signed extensions and the event data.
+func canonicalize(evt cloudevents.Event) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "id:%s\nsource:%s\ntype:%s\ndatacontenttype:%s\n",
+		evt.ID(), evt.Source(), evt.Type(), evt.DataContentType())
+
+	extensions := evt.Extensions()
+	for _, name := range signedExtensions {
+		extension, ok := extensions[name]
+		if !ok {
+			continue
+		}
+
+		value, err := cloudeventstypes.Format(extension)
+		if err != nil {
+			value = fmt.Sprintf("%v", extension)
+		}
+		fmt.Fprintf(&buf, "%s:%s\n", name, value)
+	}
+
+	buf.WriteString("data:")
+	buf.Write(evt.Data())
+	return buf.Bytes()
+}
+
+func algorithm(key crypto.PublicKey) (string, error) {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return "ES256", nil
+		case elliptic.P384():
+			return "ES384", nil
+		case elliptic.P521():
+			return "ES512", nil
+		}
+		return "", fmt.Errorf("unsupported ecdsa curve %s", k.Curve.Params().Name)
+	case *rsa.PublicKey:
+		return "RS256", nil
+	case ed25519.PublicKey:
+		return "EdDSA", nil
+	}
+	return "", fmt.Errorf("unsupported key type %T", key)
+}
+
+func hash(alg string, data []byte) (crypto.Hash, []byte) {
+	switch alg {
+	case "ES384":
+		digest := sha512.Sum384(data)
+		return crypto.SHA384, digest[:]
+	case "ES512":
+		digest := sha512.Sum512(data)
+		return crypto.SHA512, digest[:]
+	default:
+		digest := sha256.Sum256(data)
+		return crypto.SHA256, digest[:]
+	}
+}
+
+func sign(key crypto.Signer, alg string, data []byte) ([]byte, error) {
+	if alg == "EdDSA" {
+		return key.Sign(rand.Reader, data, crypto.Hash(0))
+	}
+
+	hashFunc, digest := hash(alg, data)
+	signature, err := key.Sign(rand.Reader, digest, hashFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	if publicKey, ok := key.Public().(*ecdsa.PublicKey); ok {
+		// the JWS ECDSA signature is the concatenation of R and S rather than ASN.1 DER
+		return toJWSECDSASignature(publicKey, signature)
+	}
+
+	return signature, nil
+}
+
+func verify(key crypto.PublicKey, alg string, data, signature []byte) error {
+	hashFunc, digest := hash(alg, data)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return fmt.Errorf("invalid signature length")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return fmt.Errorf("verification failed")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, hashFunc, digest, signature)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, data, signature) {
+			return fmt.Errorf("verification failed")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported key type %T", key)
+}
+
+func toJWSECDSASignature(key *ecdsa.PublicKey, der []byte) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, err
+	}
+
+	size := (key.Curve.Params().BitSize + 7) / 8
+	signature := make([]byte, 2*size)
+	sig.R.FillBytes(signature[:size])
+	sig.S.FillBytes(signature[size:])
+	return signature, nil
+}
@@ -0,0 +1,164 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func newTestEvent(t *testing.T, source string) cloudevents.Event {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: types.CloudEventsDataType{Group: "test", Version: "v1", Resource: "tests"},
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}
+	evt := types.NewEventBuilder(source, eventType).
+		WithClusterName("cluster1").
+		WithResourceID("test").
+		WithResourceVersion(1).
+		NewEvent()
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, map[string]string{"test": "test"}))
+	return evt
+}
+
+func TestSignAndVerify(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name        string
+		key         crypto.Signer
+		trustedKeys StaticKeyStore
+		mutate      func(evt *cloudevents.Event)
+		expectedErr bool
+	}{
+		{
+			name:        "ecdsa",
+			key:         ecdsaKey,
+			trustedKeys: StaticKeyStore{"source1": ecdsaKey.Public()},
+		},
+		{
+			name:        "rsa",
+			key:         rsaKey,
+			trustedKeys: StaticKeyStore{"source1": rsaKey.Public()},
+		},
+		{
+			name:        "ed25519",
+			key:         ed25519Key,
+			trustedKeys: StaticKeyStore{"source1": ed25519Key.Public()},
+		},
+		{
+			name:        "unknown source",
+			key:         ecdsaKey,
+			trustedKeys: StaticKeyStore{"source2": ecdsaKey.Public()},
+			expectedErr: true,
+		},
+		{
+			name:        "untrusted key",
+			key:         ecdsaKey,
+			trustedKeys: StaticKeyStore{"source1": otherKey.Public()},
+			expectedErr: true,
+		},
+		{
+			name:        "mismatched algorithm",
+			key:         ecdsaKey,
+			trustedKeys: StaticKeyStore{"source1": rsaKey.Public()},
+			expectedErr: true,
+		},
+		{
+			name:        "tampered data",
+			key:         ecdsaKey,
+			trustedKeys: StaticKeyStore{"source1": ecdsaKey.Public()},
+			mutate: func(evt *cloudevents.Event) {
+				require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, map[string]string{"test": "tampered"}))
+			},
+			expectedErr: true,
+		},
+		{
+			name:        "tampered extension",
+			key:         ecdsaKey,
+			trustedKeys: StaticKeyStore{"source1": ecdsaKey.Public()},
+			mutate: func(evt *cloudevents.Event) {
+				evt.SetExtension(types.ExtensionClusterName, "cluster2")
+			},
+			expectedErr: true,
+		},
+		{
+			name:        "transport extensions",
+			key:         ecdsaKey,
+			trustedKeys: StaticKeyStore{"source1": ecdsaKey.Public()},
+			mutate: func(evt *cloudevents.Event) {
+				// the extensions that are added by the Kafka binary mode receiver and the trace context
+				evt.SetExtension("kafkaoffset", "10")
+				evt.SetExtension("kafkapartition", "1")
+				evt.SetExtension("kafkatopic", "sourceevents")
+				evt.SetExtension("kafkamessagekey", "cluster1")
+				evt.SetExtension("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			},
+		},
+		{
+			name:        "impersonated source",
+			key:         ecdsaKey,
+			trustedKeys: StaticKeyStore{"source1": ecdsaKey.Public(), "source2": otherKey.Public()},
+			mutate: func(evt *cloudevents.Event) {
+				evt.SetSource("source2")
+			},
+			expectedErr: true,
+		},
+		{
+			name:        "unsigned",
+			key:         ecdsaKey,
+			trustedKeys: StaticKeyStore{"source1": ecdsaKey.Public()},
+			mutate: func(evt *cloudevents.Event) {
+				evt.SetExtension(types.ExtensionSignature, nil)
+			},
+			expectedErr: true,
+		},
+		{
+			name:        "malformed signature",
+			key:         ecdsaKey,
+			trustedKeys: StaticKeyStore{"source1": ecdsaKey.Public()},
+			mutate: func(evt *cloudevents.Event) {
+				evt.SetExtension(types.ExtensionSignature, "invalid")
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			signer, err := NewJWSSigner("source1", c.key)
+			require.NoError(t, err)
+
+			evt := newTestEvent(t, "source1")
+			require.NoError(t, signer.Sign(&evt))
+			require.Contains(t, evt.Extensions(), types.ExtensionSignature)
+
+			if c.mutate != nil {
+				c.mutate(&evt)
+			}
+
+			err = NewJWSVerifier(c.trustedKeys).Verify(evt)
+			if c.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package signing
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultSecretKeyStoreRefreshInterval is the default interval to reload the public keys from a Secret.
+const DefaultSecretKeyStoreRefreshInterval = 1 * time.Minute
+
+// DefaultSecretKeyStoreRefreshTimeout is the default timeout of loading the public keys from a Secret.
+const DefaultSecretKeyStoreRefreshTimeout = 10 * time.Second
+
+// secretKeyStoreRetryBackoff is the backoff of reloading the public keys after the loading fails.
+var secretKeyStoreRetryBackoff = wait.Backoff{
+	Duration: 1 * time.Second,
+	Cap:      DefaultSecretKeyStoreRefreshInterval,
+	Steps:    10,
+	Factor:   2.0,
+	Jitter:   0.1,
+}
+
+// KeyStore provides the public keys to verify the events.
+type KeyStore interface {
+	// PublicKey returns the public key of a source/agent.
+	PublicKey(id string) (crypto.PublicKey, error)
+}
+
+// StaticKeyStore provides the public keys from a static map that is keyed by the source/agent IDs.
+type StaticKeyStore map[string]crypto.PublicKey
+
+var _ KeyStore = StaticKeyStore{}
+
+// PublicKey returns the public key of a source/agent.
+func (s StaticKeyStore) PublicKey(id string) (crypto.PublicKey, error) {
+	key, ok := s[id]
+	if !ok {
+		return nil, fmt.Errorf("there is no public key for %s", id)
+	}
+	return key, nil
+}
+
+// NewFileKeyStore loads the public keys from the PEM files, the paths are keyed by the source/agent IDs.
+func NewFileKeyStore(paths map[string]string) (StaticKeyStore, error) {
+	store := StaticKeyStore{}
+	for id, path := range paths {
+		key, err := LoadPublicKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		store[id] = key
+	}
+	return store, nil
+}
+
+// SecretKeyStore provides the public keys from a Secret, each key of the Secret data is a source/agent ID and the
+// value is a PEM encoded public key. The Secret is reloaded periodically in the background so that the keys can be
+// rotated, the last loaded keys are kept if the reloading fails, and the reloading is retried with a backoff.
+type SecretKeyStore struct {
+	sync.Mutex
+	// loadLock serializes the loadings before the keys are loaded
+	loadLock sync.Mutex
+
+	kubeClient      kubernetes.Interface
+	namespace       string
+	name            string
+	refreshInterval time.Duration
+	refreshTimeout  time.Duration
+	keys            StaticKeyStore
+	lastRefresh     time.Time
+	refreshing      bool
+	// backoff and nextAttempt delay the next loading after the loading fails
+	backoff     wait.Backoff
+	nextAttempt time.Time
+	lastErr     error
+}
+
+var _ KeyStore = &SecretKeyStore{}
+
+// NewSecretKeyStore returns a SecretKeyStore that loads the public keys from the given Secret.
+func NewSecretKeyStore(kubeClient kubernetes.Interface, namespace, name string) *SecretKeyStore {
+	return &SecretKeyStore{
+		kubeClient:      kubeClient,
+		namespace:       namespace,
+		name:            name,
+		refreshInterval: DefaultSecretKeyStoreRefreshInterval,
+		refreshTimeout:  DefaultSecretKeyStoreRefreshTimeout,
+		backoff:         secretKeyStoreRetryBackoff,
+	}
+}
+
+// PublicKey returns the public key of a source/agent. The keys are loaded when they're requested at the first time,
+// after that, the stale keys are still returned while they're reloaded in the background.
+func (s *SecretKeyStore) PublicKey(id string) (crypto.PublicKey, error) {
+	keys, err := s.currentKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	return keys.PublicKey(id)
+}
+
+func (s *SecretKeyStore) currentKeys() (StaticKeyStore, error) {
+	s.Lock()
+	if keys := s.keys; keys != nil {
+		if !s.refreshing && time.Since(s.lastRefresh) > s.refreshInterval && !time.Now().Before(s.nextAttempt) {
+			s.refreshing = true
+			go s.refresh()
+		}
+		s.Unlock()
+		return keys, nil
+	}
+	s.Unlock()
+
+	// the keys are not loaded yet, load them and make the concurrent callers wait for the loading
+	s.loadLock.Lock()
+	defer s.loadLock.Unlock()
+
+	s.Lock()
+	if s.keys != nil {
+		defer s.Unlock()
+		return s.keys, nil
+	}
+	if time.Now().Before(s.nextAttempt) {
+		// the last loading failed, do not load the keys again until the backoff expires
+		defer s.Unlock()
+		return nil, s.lastErr
+	}
+	s.refreshing = true
+	s.Unlock()
+
+	s.refresh()
+
+	s.Lock()
+	defer s.Unlock()
+	if s.keys == nil {
+		return nil, s.lastErr
+	}
+	return s.keys, nil
+}
+
+// refresh reloads the keys from the Secret, the current keys are kept if the reloading fails.
+func (s *SecretKeyStore) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.refreshTimeout)
+	defer cancel()
+
+	keys, err := s.load(ctx)
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.refreshing = false
+	if err != nil {
+		delay := s.backoff.Step()
+		s.nextAttempt = time.Now().Add(delay)
+		s.lastErr = err
+		runtime.HandleError(fmt.Errorf("%v, retry after %v", err, delay))
+		return
+	}
+
+	s.keys = keys
+	s.lastRefresh = time.Now()
+	s.backoff = secretKeyStoreRetryBackoff
+	s.nextAttempt = time.Time{}
+	s.lastErr = nil
+}
+
+func (s *SecretKeyStore) load(ctx context.Context) (StaticKeyStore, error) {
+	secret, err := s.kubeClient.CoreV1().Secrets(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the public keys from the secret %s/%s, %v", s.namespace, s.name, err)
+	}
+
+	keys := StaticKeyStore{}
+	for id, data := range secret.Data {
+		key, err := ParsePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the public key %s of the secret %s/%s, %v", id, s.namespace, s.name, err)
+		}
+		keys[id] = key
+	}
+
+	return keys, nil
+}
+
+// LoadPublicKeyFile loads a PEM encoded public key from a file.
+func LoadPublicKeyFile(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ParsePublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the public key file %s, %v", path, err)
+	}
+	return key, nil
+}
+
+// LoadPrivateKeyFile loads a PEM encoded PKCS8, PKCS1 or EC private key from a file.
+func LoadPrivateKeyFile(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the private key file %s, %v", path, err)
+	}
+	return key, nil
+}
+
+// ParsePublicKey parses a PEM encoded PKIX public key.
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data is found")
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS8, PKCS1 or EC private key.
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data is found")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
@@ -0,0 +1,156 @@
+package signing
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestFileKeyStore(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+
+	privateKeyData, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	privateKeyFile := filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(privateKeyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privateKeyData}), 0600))
+
+	publicKeyFile := filepath.Join(dir, "source1.pub")
+	require.NoError(t, os.WriteFile(publicKeyFile, encodePublicKey(t, key.Public()), 0600))
+
+	privateKey, err := LoadPrivateKeyFile(privateKeyFile)
+	require.NoError(t, err)
+	signer, err := NewJWSSigner("source1", privateKey)
+	require.NoError(t, err)
+
+	keys, err := NewFileKeyStore(map[string]string{"source1": publicKeyFile})
+	require.NoError(t, err)
+
+	evt := newTestEvent(t, "source1")
+	require.NoError(t, signer.Sign(&evt))
+	require.NoError(t, NewJWSVerifier(keys).Verify(evt))
+
+	_, err = NewFileKeyStore(map[string]string{"source1": filepath.Join(dir, "none.pub")})
+	require.Error(t, err)
+}
+
+func TestSecretKeyStore(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	kubeClient := kubefake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "keys"},
+		Data:       map[string][]byte{"source1": encodePublicKey(t, key.Public())},
+	})
+	keys := NewSecretKeyStore(kubeClient, "test", "keys")
+
+	publicKey, err := keys.PublicKey("source1")
+	require.NoError(t, err)
+	require.True(t, key.PublicKey.Equal(publicKey))
+
+	_, err = keys.PublicKey("source2")
+	require.Error(t, err)
+
+	// rotate the key
+	_, err = kubeClient.CoreV1().Secrets("test").Update(context.TODO(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "keys"},
+		Data:       map[string][]byte{"source1": encodePublicKey(t, newKey.Public())},
+	}, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// the stale key is returned while the keys are reloaded in the background
+	keys.refreshInterval = 0
+	require.Eventually(t, func() bool {
+		publicKey, err := keys.PublicKey("source1")
+		require.NoError(t, err)
+		return newKey.PublicKey.Equal(publicKey)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSecretKeyStoreRefreshFailure(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	kubeClient := kubefake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "keys"},
+		Data:       map[string][]byte{"source1": encodePublicKey(t, key.Public())},
+	})
+	keys := NewSecretKeyStore(kubeClient, "test", "keys")
+	keys.backoff.Duration = time.Hour
+
+	_, err = keys.PublicKey("source1")
+	require.NoError(t, err)
+
+	// mimic the apiserver is unavailable
+	var mutex sync.Mutex
+	gets := 0
+	kubeClient.PrependReactor("get", "secrets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		gets++
+		return true, nil, fmt.Errorf("the server is unavailable")
+	})
+
+	// the last loaded keys are still returned after the reloading fails
+	keys.refreshInterval = 0
+	require.Eventually(t, func() bool {
+		_, err := keys.PublicKey("source1")
+		require.NoError(t, err)
+
+		keys.Lock()
+		defer keys.Unlock()
+		return keys.lastErr != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 10; i++ {
+		publicKey, err := keys.PublicKey("source1")
+		require.NoError(t, err)
+		require.True(t, key.PublicKey.Equal(publicKey))
+	}
+
+	// the reloading is not retried until the backoff expires
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.Equal(t, 1, gets)
+}
+
+func TestSecretKeyStoreLoadFailure(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	keys := NewSecretKeyStore(kubeClient, "test", "keys")
+	keys.backoff.Duration = time.Hour
+
+	_, err := keys.PublicKey("source1")
+	require.Error(t, err)
+
+	// the failed loading is not retried until the backoff expires
+	_, err = keys.PublicKey("source1")
+	require.Error(t, err)
+	require.Len(t, kubeClient.Actions(), 1)
+}
+
+func encodePublicKey(t *testing.T, key any) []byte {
+	data, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: data})
+}
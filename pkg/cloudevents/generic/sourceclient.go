@@ -8,6 +8,7 @@ import (
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/uuid"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
@@ -26,6 +27,10 @@ type CloudEventSourceClient[T ResourceObject] struct {
 	lister           Lister[T]
 	codecs           map[types.CloudEventsDataType]Codec[T]
 	statusHashGetter StatusHashGetter[T]
+	handlerRunner    *handlerRunner[T]
+	resyncGate       *resyncGate
+	merkleResync     *merkleResyncTracker
+	heartbeatTracker *HeartbeatTracker
 	sourceID         string
 }
 
@@ -46,24 +51,38 @@ func NewCloudEventSourceClient[T ResourceObject](
 	baseClient := &baseClient{
 		clientID:               sourceOptions.SourceID,
 		cloudEventsOptions:     sourceOptions.CloudEventsOptions,
-		cloudEventsRateLimiter: NewRateLimiter(sourceOptions.EventRateLimit),
-		reconnectedChan:        make(chan struct{}),
+		cloudEventsRateLimiter: newEventRateLimiter(sourceOptions.EventRateLimit),
+		reconnectedChan:        make(chan struct{}, 1),
+		outbox:                 newOutbox(sourceOptions.SourceID, sourceOptions.Outbox),
+		deadLetterSink:         sourceOptions.DeadLetterSink,
+		dispatcher:             newDispatcher(sourceOptions.Dispatch),
+		dedupCache:             newDedupCache(sourceOptions.SourceID, sourceOptions.Deduplication),
+		resyncChunker:          newResyncChunker(sourceOptions.ResyncChunk),
+		encoder:                newEventEncoder(sourceOptions.Encoding, extensionPeer(types.ExtensionClusterName), extensionPeer(types.ExtensionClusterName)),
+		versions:               newVersionNegotiator(sourceOptions.Conversion, codecDataTypes(codecs...), extensionPeer(types.ExtensionClusterName), extensionPeer(types.ExtensionClusterName)),
+		signer:                 sourceOptions.Signer,
+		verifier:               sourceOptions.Verifier,
+		encryptor:              sourceOptions.Encryptor,
+		decryptor:              sourceOptions.Decryptor,
+		recipient:              extensionPeer(types.ExtensionClusterName),
+		tracer:                 newTracer(sourceOptions.TracerProvider),
+		connectionState:        newConnectionStateTracker(sourceOptions.SourceID),
+		reconnectBackoff:       newReconnectBackoff(sourceOptions.Reconnect),
 	}
 
 	if err := baseClient.connect(ctx); err != nil {
 		return nil, err
 	}
 
-	evtCodes := make(map[types.CloudEventsDataType]Codec[T])
-	for _, codec := range codecs {
-		evtCodes[codec.EventDataType()] = codec
-	}
-
 	return &CloudEventSourceClient[T]{
 		baseClient:       baseClient,
 		lister:           lister,
-		codecs:           evtCodes,
+		codecs:           newCodecs(codecs...),
 		statusHashGetter: statusHashGetter,
+		handlerRunner:    newHandlerRunner[T](baseClient, sourceOptions.HandlerRetry),
+		resyncGate:       newResyncGate[T](ctx, lister),
+		merkleResync:     newMerkleResyncTracker(ctx, sourceOptions.MerkleResync),
+		heartbeatTracker: newHeartbeatTracker(ctx, sourceOptions.SourceID, sourceOptions.HeartbeatLease),
 		sourceID:         sourceOptions.SourceID,
 	}, nil
 }
@@ -72,38 +91,76 @@ func (c *CloudEventSourceClient[T]) ReconnectedChan() <-chan struct{} {
 	return c.reconnectedChan
 }
 
+// HeartbeatTracker returns the tracker of the agent heartbeats, it's nil if the HeartbeatLease is not set.
+func (c *CloudEventSourceClient[T]) HeartbeatTracker() *HeartbeatTracker {
+	return c.heartbeatTracker
+}
+
+// Status returns the status of the source client, it's used to check the health of the client.
+func (c *CloudEventSourceClient[T]) Status() ClientStatus {
+	status := c.status()
+	status.PendingResyncRequests, status.PendingResyncSince = c.resyncGate.pendingRequests()
+	status.PendingMerkleResyncs = c.merkleResync.pendingRequests()
+	return status
+}
+
 // Resync the resources status by sending a status resync request from the current source to a specified cluster.
+// If the merkle resync is enabled and the cluster is specified, a merkle resync request is sent, otherwise the status
+// hashes of all resources are sent.
 func (c *CloudEventSourceClient[T]) Resync(ctx context.Context, clusterName string) error {
+	return withSpan(ctx, c.tracer, resyncSpanName, func(ctx context.Context) error {
+		return c.resync(ctx, clusterName)
+	}, eventSourceAttribute.String(c.sourceID), clusterNameAttribute.String(clusterName))
+}
+
+func (c *CloudEventSourceClient[T]) resync(ctx context.Context, clusterName string) error {
 	// only resync the resources whose event data type is registered
-	for eventDataType := range c.codecs {
-		// list the resource objects that are maintained by the current source with a specified cluster
-		options := types.ListOptions{Source: c.sourceID, ClusterName: clusterName, CloudEventsDataType: eventDataType}
-		objs, err := c.lister.List(options)
-		if err != nil {
-			return err
+	for eventDataType, codec := range c.codecs {
+		if codec.EventDataType() != eventDataType {
+			// the other supported versions of a versioned codec
+			continue
 		}
 
-		hashes := &payload.ResourceStatusHashList{Hashes: make([]payload.ResourceStatusHash, len(objs))}
-		for i, obj := range objs {
-			statusHash, err := c.statusHashGetter(obj)
-			if err != nil {
+		if c.merkleResync != nil && clusterName != types.ClusterAll {
+			if err := c.resyncWithMerkleTree(ctx, clusterName, eventDataType); err != nil {
 				return err
 			}
+			continue
+		}
 
-			hashes.Hashes[i] = payload.ResourceStatusHash{
-				ResourceID: string(obj.GetUID()),
-				StatusHash: statusHash,
-			}
+		if err := c.resyncStatus(ctx, clusterName, eventDataType); err != nil {
+			return err
 		}
+	}
 
-		eventType := types.CloudEventsType{
-			CloudEventsDataType: eventDataType,
-			SubResource:         types.SubResourceStatus,
-			Action:              types.ResyncRequestAction,
+	return nil
+}
+
+func (c *CloudEventSourceClient[T]) resyncStatus(
+	ctx context.Context, clusterName string, eventDataType types.CloudEventsDataType) error {
+	statusHashes, err := c.listStatusHashes(clusterName, eventDataType)
+	if err != nil {
+		return err
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: eventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              types.ResyncRequestAction,
+	}
+
+	// split the large resync request into chunks
+	hashes := &payload.ResourceStatusHashList{Hashes: statusHashes}
+	chunks := payload.SplitResourceStatusHashList(hashes, c.resyncChunker.size)
+	chunkID := uuid.New().String()
+	for i, chunk := range chunks {
+		builder := types.NewEventBuilder(c.sourceID, eventType).WithClusterName(clusterName)
+		if len(chunks) > 1 {
+			builder.WithChunk(chunkID, i, len(chunks))
 		}
 
-		evt := types.NewEventBuilder(c.sourceID, eventType).WithClusterName(clusterName).NewEvent()
-		if err := evt.SetData(cloudevents.ApplicationJSON, hashes); err != nil {
+		evt := builder.NewEvent()
+		if err := evt.SetData(cloudevents.ApplicationJSON, chunk); err != nil {
 			return fmt.Errorf("failed to set data to cloud event: %v", err)
 		}
 
@@ -117,6 +174,76 @@ func (c *CloudEventSourceClient[T]) Resync(ctx context.Context, clusterName stri
 	return nil
 }
 
+// resyncWithMerkleTree sends a merkle resync request with the root node of the merkle tree of the resources status to
+// a specified cluster. If the agent does not respond in time, the source falls back to the full status resync.
+func (c *CloudEventSourceClient[T]) resyncWithMerkleTree(
+	ctx context.Context, clusterName string, eventDataType types.CloudEventsDataType) error {
+	statusHashes, err := c.listStatusHashes(clusterName, eventDataType)
+	if err != nil {
+		return err
+	}
+
+	tree := payload.NewMerkleTree(c.merkleResync.depth, statusHashes)
+
+	// the fallback runs after the Resync returns, it should not be canceled with the context of the caller
+	fallbackCtx := context.WithoutCancel(ctx)
+	c.merkleResync.track(merkleResyncKey(clusterName, eventDataType), func() {
+		klog.Warningf("there is no merkle resync response from the cluster %s, fall back to the full status resync", clusterName)
+		if err := c.resyncStatus(fallbackCtx, clusterName, eventDataType); err != nil {
+			klog.Errorf("failed to resync resources status, %v", err)
+		}
+	})
+
+	return c.publishMerkleResyncRequest(ctx, clusterName, eventDataType, tree.Depth(), []payload.MerkleNode{tree.Root()})
+}
+
+// listStatusHashes lists the status hashes of the resource objects that are maintained by the current source with a
+// specified cluster.
+func (c *CloudEventSourceClient[T]) listStatusHashes(
+	clusterName string, eventDataType types.CloudEventsDataType) ([]payload.ResourceStatusHash, error) {
+	options := types.ListOptions{Source: c.sourceID, ClusterName: clusterName, CloudEventsDataType: eventDataType}
+	objs, err := c.lister.List(options)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]payload.ResourceStatusHash, len(objs))
+	for i, obj := range objs {
+		statusHash, err := c.statusHashGetter(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		hashes[i] = payload.ResourceStatusHash{
+			ResourceID: string(obj.GetUID()),
+			StatusHash: statusHash,
+		}
+	}
+
+	return hashes, nil
+}
+
+func (c *CloudEventSourceClient[T]) publishMerkleResyncRequest(ctx context.Context, clusterName string,
+	eventDataType types.CloudEventsDataType, depth int, nodes []payload.MerkleNode) error {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: eventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              types.MerkleResyncRequestAction,
+	}
+
+	evt := types.NewEventBuilder(c.sourceID, eventType).WithClusterName(clusterName).NewEvent()
+	if err := evt.SetData(cloudevents.ApplicationJSON, &payload.MerkleNodeList{Depth: depth, Nodes: nodes}); err != nil {
+		return fmt.Errorf("failed to set data to cloud event: %v", err)
+	}
+
+	if err := c.publish(ctx, evt); err != nil {
+		return err
+	}
+
+	increaseCloudEventsSentCounter(evt.Source(), clusterName, eventDataType.String())
+	return nil
+}
+
 // Publish a resource spec from a source to an agent.
 func (c *CloudEventSourceClient[T]) Publish(ctx context.Context, eventType types.CloudEventsType, obj T) error {
 	if eventType.SubResource != types.SubResourceSpec {
@@ -143,10 +270,55 @@ func (c *CloudEventSourceClient[T]) Publish(ctx context.Context, eventType types
 	return nil
 }
 
+// PublishBatch publishes the resources spec from a source to the agents. The resources are grouped by their clusters,
+// and the resources of a cluster are sent in a pipeline if the transport supports it, so the resources should be
+// different from each other. It returns the errors of the resources in the order of the given resources, the error
+// of a resource is nil if the resource is published.
+func (c *CloudEventSourceClient[T]) PublishBatch(ctx context.Context, eventType types.CloudEventsType, objs []T) []error {
+	errs := make([]error, len(objs))
+
+	if eventType.SubResource != types.SubResourceSpec {
+		return fillErrors(errs, fmt.Errorf("unsupported event eventType %s", eventType))
+	}
+
+	codec, ok := c.codecs[eventType.CloudEventsDataType]
+	if !ok {
+		return fillErrors(errs, fmt.Errorf("failed to find the codec for event %s", eventType.CloudEventsDataType))
+	}
+
+	evts := []cloudevents.Event{}
+	indexes := []int{}
+	for i, obj := range objs {
+		evt, err := codec.Encode(c.sourceID, eventType, obj)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		evts = append(evts, *evt)
+		indexes = append(indexes, i)
+	}
+
+	for j, err := range c.publishBatch(ctx, evts) {
+		if err != nil {
+			errs[indexes[j]] = err
+			continue
+		}
+
+		clusterName := evts[j].Context.GetExtensions()[types.ExtensionClusterName].(string)
+		increaseCloudEventsSentCounter(evts[j].Source(), clusterName, eventType.CloudEventsDataType.String())
+	}
+
+	return errs
+}
+
 // Subscribe the events that are from the agent spec resync request or agent resource status request.
 // For spec resync request, source publish the current resources spec back as response.
 // For resource status request, source receives resource status and handles the status with resource handlers.
 func (c *CloudEventSourceClient[T]) Subscribe(ctx context.Context, handlers ...ResourceHandler[T]) {
+	// start a go routine to retry the failed resources
+	go c.handlerRunner.run(ctx)
+
 	c.subscribe(ctx, func(ctx context.Context, evt cloudevents.Event) {
 		c.receive(ctx, evt, handlers...)
 	})
@@ -156,6 +328,7 @@ func (c *CloudEventSourceClient[T]) receive(ctx context.Context, evt cloudevents
 	eventType, err := types.ParseCloudEventsType(evt.Type())
 	if err != nil {
 		klog.Errorf("failed to parse cloud event type, %v", err)
+		c.deadLetter(ctx, evt, options.DeadLetterStageParse, err)
 		return
 	}
 
@@ -168,6 +341,19 @@ func (c *CloudEventSourceClient[T]) receive(ctx context.Context, evt cloudevents
 
 	increaseCloudEventsReceivedCounter(evt.Source(), cn, eventType.CloudEventsDataType.String())
 
+	if eventType.Action == types.HeartbeatAction {
+		if c.heartbeatTracker == nil {
+			klog.V(4).Infof("the heartbeat tracking is disabled, ignore the heartbeat %s", evt.ID())
+			return
+		}
+
+		if err := c.heartbeatTracker.renew(evt); err != nil {
+			klog.Errorf("failed to renew the agent lease with the heartbeat %s, %v", evt.ID(), err)
+			c.deadLetter(ctx, evt, options.DeadLetterStageDecode, err)
+		}
+		return
+	}
+
 	if eventType.Action == types.ResyncRequestAction {
 		if eventType.SubResource != types.SubResourceSpec {
 			klog.Warningf("unsupported event type %s, ignore", eventType)
@@ -180,11 +366,42 @@ func (c *CloudEventSourceClient[T]) receive(ctx context.Context, evt cloudevents
 			return
 		}
 
-		startTime := time.Now()
-		if err := c.respondResyncSpecRequest(ctx, eventType.CloudEventsDataType, evt); err != nil {
-			klog.Errorf("failed to resync resources spec, %v", err)
+		// the request may be split into chunks, wait for all of the chunks
+		request, err := c.resyncChunker.assemble(*eventType, evt)
+		if err != nil {
+			klog.Errorf("failed to assemble the resync request %s, %v", evt.ID(), err)
+			c.deadLetter(ctx, evt, options.DeadLetterStageDecode, err)
+			return
+		}
+		if request == nil {
+			return
+		}
+
+		// the request is answered after the lister is synced
+		c.resyncGate.respond(ctx, *request, func(ctx context.Context, evt cloudevents.Event) {
+			startTime := time.Now()
+			if err := withSpan(ctx, c.tracer, respondResyncSpanName, func(ctx context.Context) error {
+				return c.respondResyncSpecRequest(ctx, eventType.CloudEventsDataType, evt)
+			}); err != nil {
+				klog.Errorf("failed to resync resources spec, %v", err)
+			}
+			updateResourceSpecResyncDurationMetric(c.sourceID, fmt.Sprintf("%s", clusterName), eventType.CloudEventsDataType.String(), startTime)
+		})
+
+		return
+	}
+
+	if eventType.Action == types.MerkleResyncResponseAction {
+		if eventType.SubResource != types.SubResourceStatus {
+			klog.Warningf("unsupported event type %s, ignore", eventType)
+			return
+		}
+
+		if err := withSpan(ctx, c.tracer, respondResyncSpanName, func(ctx context.Context) error {
+			return c.respondMerkleResyncResponse(ctx, eventType.CloudEventsDataType, evt)
+		}); err != nil {
+			klog.Errorf("failed to resync resources status with merkle tree, %v", err)
 		}
-		updateResourceSpecResyncDurationMetric(c.sourceID, fmt.Sprintf("%s", clusterName), eventType.CloudEventsDataType.String(), startTime)
 
 		return
 	}
@@ -192,6 +409,8 @@ func (c *CloudEventSourceClient[T]) receive(ctx context.Context, evt cloudevents
 	codec, ok := c.codecs[eventType.CloudEventsDataType]
 	if !ok {
 		klog.Warningf("failed to find the codec for event %s, ignore", eventType.CloudEventsDataType)
+		c.deadLetter(ctx, evt, options.DeadLetterStageCodec,
+			fmt.Errorf("failed to find the codec for event %s", eventType.CloudEventsDataType))
 		return
 	}
 
@@ -203,14 +422,11 @@ func (c *CloudEventSourceClient[T]) receive(ctx context.Context, evt cloudevents
 	obj, err := codec.Decode(&evt)
 	if err != nil {
 		klog.Errorf("failed to decode status, %v", err)
+		c.deadLetter(ctx, evt, options.DeadLetterStageDecode, err)
 		return
 	}
 
-	for _, handler := range handlers {
-		if err := handler(types.StatusModified, obj); err != nil {
-			klog.Errorf("failed to handle status event %s, %v", evt, err)
-		}
-	}
+	c.handlerRunner.handle(ctx, evt, types.StatusModified, obj, handlers...)
 }
 
 // Upon receiving the spec resync event, the source responds by sending resource status events to the broker as follows:
@@ -250,9 +466,10 @@ func (c *CloudEventSourceClient[T]) respondResyncSpecRequest(
 		return err
 	}
 
-	// TODO we cannot list objs now, the lister may be not ready, we may need to add HasSynced
-	// for the lister
-	if len(objs) == 0 {
+	// if the lister is not a SyncedLister, an empty list may be caused by the lister is not ready, do nothing to avoid
+	// deleting the resources on the agent. Otherwise, the lister is synced and the resources that exist on the agent
+	// should be deleted.
+	if len(objs) == 0 && !c.resyncGate.listerSynced() {
 		klog.V(4).Infof("there are is no objs from the list, do nothing")
 		return nil
 	}
@@ -306,6 +523,51 @@ func (c *CloudEventSourceClient[T]) respondResyncSpecRequest(
 	return nil
 }
 
+// Upon receiving the merkle resync response, the source compares the child nodes of the agent with its own nodes,
+// and sends the mismatched nodes back to the agent as a new merkle resync request. The status hashes of the resources
+// are carried by the mismatched leaf nodes, so that the agent only compares and sends the status of these resources.
+func (c *CloudEventSourceClient[T]) respondMerkleResyncResponse(
+	ctx context.Context, eventDataType types.CloudEventsDataType, evt cloudevents.Event) error {
+	clusterName, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionClusterName])
+	if err != nil {
+		return fmt.Errorf("failed to get cluster name extension, %v", err)
+	}
+
+	// the agent supports the merkle resync, stop waiting for its response
+	c.merkleResync.responded(merkleResyncKey(clusterName, eventDataType))
+
+	nodes, err := payload.DecodeMerkleResyncResponse(evt)
+	if err != nil {
+		return err
+	}
+
+	statusHashes, err := c.listStatusHashes(clusterName, eventDataType)
+	if err != nil {
+		return err
+	}
+
+	tree := payload.NewMerkleTree(nodes.Depth, statusHashes)
+	mismatched := []payload.MerkleNode{}
+	for _, node := range nodes.Nodes {
+		if tree.Hash(node.Path) == node.Hash {
+			continue
+		}
+
+		mismatched = append(mismatched, tree.Node(node.Path))
+	}
+
+	if len(mismatched) == 0 {
+		klog.V(4).Infof("the resources status of the cluster %s is in sync", clusterName)
+		return nil
+	}
+
+	return c.publishMerkleResyncRequest(ctx, clusterName, eventDataType, tree.Depth(), mismatched)
+}
+
+func merkleResyncKey(clusterName string, eventDataType types.CloudEventsDataType) string {
+	return fmt.Sprintf("%s/%s", clusterName, eventDataType)
+}
+
 func findResourceVersion(id string, versions []payload.ResourceVersion) int64 {
 	for _, version := range versions {
 		if id == version.ResourceID {
@@ -2,6 +2,9 @@ package generic
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"fmt"
 	"sync"
 	"testing"
@@ -12,8 +15,11 @@ import (
 	"github.com/stretchr/testify/require"
 	kubetypes "k8s.io/apimachinery/pkg/types"
 
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/encryption"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/signing"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
@@ -415,3 +421,187 @@ func TestReceiveResourceStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestSpecResyncResponseWithSyncedLister(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceOptions := fake.NewSourceOptions(gochan.New(), testSourceName)
+	lister := &mockSyncedResourceLister{mockResourceLister: newMockResourceLister()}
+	source, err := NewCloudEventSourceClient[*mockResource](ctx, sourceOptions, lister, statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	// start receiver
+	receivedEvents := []cloudevents.Event{}
+	mutex := &sync.Mutex{}
+	go func() {
+		_ = source.cloudEventsClient.StartReceiver(ctx, func(event cloudevents.Event) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			receivedEvents = append(receivedEvents, event)
+		})
+	}()
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ResyncRequestAction,
+	}
+	versions := &payload.ResourceVersionList{
+		Versions: []payload.ResourceVersion{{ResourceID: "test1", ResourceVersion: 1}},
+	}
+	evt := cloudevents.NewEvent()
+	evt.SetType(eventType.String())
+	evt.SetExtension("clustername", "cluster1")
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, versions))
+
+	// the lister is not synced, the request is queued
+	source.receive(ctx, evt)
+	time.Sleep(500 * time.Millisecond)
+	mutex.Lock()
+	require.Empty(t, receivedEvents)
+	mutex.Unlock()
+
+	// the lister is synced and there are no resources on the source, the resource on the agent should be deleted
+	lister.setSynced()
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if len(receivedEvents) != 1 {
+			return false
+		}
+		_, err := receivedEvents[0].Context.GetExtension("deletiontimestamp")
+		return err == nil
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
+func TestSpecResyncResponseWithSyncedListerAndDispatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceOptions := fake.NewSourceOptions(gochan.New(), testSourceName)
+	lister := &mockSyncedResourceLister{mockResourceLister: newMockResourceLister()}
+	source, err := NewCloudEventSourceClient[*mockResource](ctx, sourceOptions, lister, statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	// start receiver
+	receivedEvents := []cloudevents.Event{}
+	mutex := &sync.Mutex{}
+	go func() {
+		_ = source.cloudEventsClient.StartReceiver(ctx, func(event cloudevents.Event) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			receivedEvents = append(receivedEvents, event)
+		})
+	}()
+
+	// the resync request is processed by a dispatcher worker, its context is canceled once it's processed
+	d := newDispatcher(&options.EventDispatch{})
+	d.run(ctx, func(ctx context.Context, evt cloudevents.Event) {
+		source.receive(ctx, evt)
+	})
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ResyncRequestAction,
+	}
+	versions := &payload.ResourceVersionList{
+		Versions: []payload.ResourceVersion{{ResourceID: "test1", ResourceVersion: 1}},
+	}
+	evt := cloudevents.NewEvent()
+	evt.SetType(eventType.String())
+	evt.SetExtension("clustername", "cluster1")
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, versions))
+
+	// the lister is not synced, the request is queued
+	d.dispatch(ctx, evt)
+	time.Sleep(500 * time.Millisecond)
+	mutex.Lock()
+	require.Empty(t, receivedEvents)
+	mutex.Unlock()
+
+	// the queued request is still answered after the lister is synced
+	lister.setSynced()
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(receivedEvents) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
+type mockSyncedResourceLister struct {
+	*mockResourceLister
+	sync.Mutex
+	synced bool
+}
+
+func (l *mockSyncedResourceLister) HasSynced() bool {
+	l.Lock()
+	defer l.Unlock()
+	return l.synced
+}
+
+func (l *mockSyncedResourceLister) setSynced() {
+	l.Lock()
+	defer l.Unlock()
+	l.synced = true
+}
+
+func TestSourcePublishEncrypted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clusterKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	sourceKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer, err := signing.NewJWSSigner(testSourceName, sourceKey)
+	require.NoError(t, err)
+
+	sourceOptions := fake.NewSourceOptions(gochan.New(), testSourceName)
+	sourceOptions.Signer = signer
+	sourceOptions.Encryptor = encryption.NewEnvelopeEncryptor(signing.StaticKeyStore{"cluster1": clusterKey.Public()})
+	source, err := NewCloudEventSourceClient[*mockResource](ctx, sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	eventChan := make(chan cloudevents.Event, 1)
+	go func() {
+		_ = source.cloudEventsClient.StartReceiver(ctx, func(event cloudevents.Event) {
+			eventChan <- event
+		})
+	}()
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	resource := &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1", Status: "secret"}
+	require.NoError(t, source.Publish(ctx, eventType, resource))
+
+	// the event data is encrypted, and the routing extensions are kept in clear
+	evt := <-eventChan
+	require.Equal(t, encryption.ContentTypeEncrypted, evt.DataContentType())
+	require.NotContains(t, string(evt.Data()), "secret")
+	require.Equal(t, "cluster1", evt.Extensions()[types.ExtensionClusterName])
+	require.Equal(t, "test1", evt.Extensions()[types.ExtensionResourceID])
+
+	// the agent verifies and decrypts the event
+	agentOptions := fake.NewAgentOptions(gochan.New(), nil, "cluster1", testAgentName)
+	agentOptions.Verifier = signing.NewJWSVerifier(signing.StaticKeyStore{testSourceName: sourceKey.Public()})
+	agentOptions.Decryptor = encryption.NewEnvelopeDecryptor(clusterKey)
+	agent, err := NewCloudEventAgentClient[*mockResource](ctx, agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	var received *cloudevents.Event
+	receive := agent.verifyReceived(agent.decryptReceived(agent.decodeReceived(func(ctx context.Context, evt cloudevents.Event) {
+		received = &evt
+	})))
+	receive(ctx, evt)
+	require.NotNil(t, received)
+
+	decoded, err := newMockResourceCodec().Decode(received)
+	require.NoError(t, err)
+	require.Equal(t, "secret", decoded.Status)
+}
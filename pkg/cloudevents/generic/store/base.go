@@ -0,0 +1,115 @@
+package store
+
+import (
+	"fmt"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+type baseStore[T Object] struct {
+	sync.RWMutex
+
+	store     cache.Store
+	initiated bool
+}
+
+// List the resources from the store with the list options
+func (b *baseStore[T]) List(namespace string, opts metav1.ListOptions) ([]T, error) {
+	b.RLock()
+	defer b.RUnlock()
+
+	return ListWithOptions[T](b.store, namespace, opts)
+}
+
+// Get a resource from the store
+func (b *baseStore[T]) Get(namespace, name string) (T, bool, error) {
+	b.RLock()
+	defer b.RUnlock()
+
+	var empty T
+	obj, exists, err := b.store.GetByKey(fmt.Sprintf("%s/%s", namespace, name))
+	if err != nil {
+		return empty, false, err
+	}
+
+	if !exists {
+		return empty, false, nil
+	}
+
+	resource, ok := obj.(T)
+	if !ok {
+		return empty, false, fmt.Errorf("unknown type %T", obj)
+	}
+
+	return resource, true, nil
+}
+
+// List all of resources from the store
+func (b *baseStore[T]) ListAll() ([]T, error) {
+	b.RLock()
+	defer b.RUnlock()
+
+	resources := []T{}
+	for _, obj := range b.store.List() {
+		if resource, ok := obj.(T); ok {
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// ListWithOptions retrieves the resources from the store which match the namespace and the label/field selectors of
+// the options, the supported fields are metadata.name and metadata.namespace.
+func ListWithOptions[T Object](store cache.Store, namespace string, opts metav1.ListOptions) ([]T, error) {
+	var err error
+
+	labelSelector := labels.Everything()
+	fieldSelector := fields.Everything()
+
+	if len(opts.LabelSelector) != 0 {
+		labelSelector, err = labels.Parse(opts.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid labels selector %q: %v", opts.LabelSelector, err)
+		}
+	}
+
+	if len(opts.FieldSelector) != 0 {
+		fieldSelector, err = fields.ParseSelector(opts.FieldSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fields selector %q: %v", opts.FieldSelector, err)
+		}
+	}
+
+	resources := []T{}
+	// list with labels
+	if err := cache.ListAll(store, labelSelector, func(obj interface{}) {
+		resource, ok := obj.(T)
+		if !ok {
+			return
+		}
+
+		if namespace != metav1.NamespaceAll && resource.GetNamespace() != namespace {
+			return
+		}
+
+		fieldSet := fields.Set{
+			"metadata.name":      resource.GetName(),
+			"metadata.namespace": resource.GetNamespace(),
+		}
+
+		if !fieldSelector.Matches(fieldSet) {
+			return
+		}
+
+		resources = append(resources, resource)
+	}); err != nil {
+		return nil, err
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// InformerWatcherStore extends the baseStore.
+// It gets/lists the resources from the given informer store and send
+// the resource add/update/delete event to the watch channel directly.
+//
+// It is used for building a source/agent client.
+type InformerWatcherStore[T Object] struct {
+	baseStore[T]
+	informer cache.SharedIndexInformer
+	watcher  *Watcher
+	handler  ReceivedHandler[T]
+}
+
+// NewInformerWatcherStore returns an InformerWatcherStore, the received resources are handled by the given handler.
+func NewInformerWatcherStore[T Object](handler ReceivedHandler[T]) *InformerWatcherStore[T] {
+	return &InformerWatcherStore[T]{
+		baseStore: baseStore[T]{},
+		watcher:   NewWatcher(),
+		handler:   handler,
+	}
+}
+
+func (s *InformerWatcherStore[T]) Add(obj T) error {
+	s.watcher.Receive(watch.Event{Type: watch.Added, Object: obj})
+	return nil
+}
+
+func (s *InformerWatcherStore[T]) Update(obj T) error {
+	s.watcher.Receive(watch.Event{Type: watch.Modified, Object: obj})
+	return nil
+}
+
+func (s *InformerWatcherStore[T]) Delete(obj T) error {
+	s.watcher.Receive(watch.Event{Type: watch.Deleted, Object: obj})
+	return nil
+}
+
+// HandleReceivedResource handles the received resource with the handler of the store.
+func (s *InformerWatcherStore[T]) HandleReceivedResource(action types.ResourceAction, obj T) error {
+	return s.handler(s, action, obj)
+}
+
+func (s *InformerWatcherStore[T]) GetWatcher(namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return s.watcher, nil
+}
+
+func (s *InformerWatcherStore[T]) HasInitiated() bool {
+	return s.initiated && s.informer.HasSynced()
+}
+
+func (s *InformerWatcherStore[T]) SetInformer(informer cache.SharedIndexInformer) {
+	s.informer = informer
+	s.store = informer.GetStore()
+	s.initiated = true
+}
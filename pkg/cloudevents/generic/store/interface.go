@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const syncedPollPeriod = 100 * time.Millisecond
+
+// Object is a resource object that can be cached and watched with a WatcherStore.
+type Object interface {
+	generic.ResourceObject
+	metav1.Object
+	runtime.Object
+}
+
+// StoreInitiated is a function that can be used to determine if a store has initiated.
+type StoreInitiated func() bool
+
+// WatcherStore caches the resources that are sent/received by a source/agent client and provides a watcher to
+// receive the resource changes, so that an informer can be built on the client.
+type WatcherStore[T generic.ResourceObject] interface {
+	// GetWatcher returns a watcher to receive the resource changes.
+	GetWatcher(namespace string, opts metav1.ListOptions) (watch.Interface, error)
+
+	// HandleReceivedResource handles the resource events that are received by the client.
+	HandleReceivedResource(action types.ResourceAction, obj T) error
+
+	// Add will be called by the client when adding a resource. The implementation is based on the specific
+	// watcher store, in some case, it does not need to update a store, but just send a watch event.
+	Add(obj T) error
+
+	// Update will be called by the client when updating a resource. The implementation is based on the specific
+	// watcher store, in some case, it does not need to update a store, but just send a watch event.
+	Update(obj T) error
+
+	// Delete will be called by the client when deleting a resource. The implementation is based on the specific
+	// watcher store, in some case, it does not need to update a store, but just send a watch event.
+	Delete(obj T) error
+
+	// List returns the resources from the store for a given namespace with the list options.
+	List(namespace string, opts metav1.ListOptions) ([]T, error)
+
+	// ListAll lists all of the resources from the store.
+	ListAll() ([]T, error)
+
+	// Get returns a resource from the store with its namespace and name.
+	Get(namespace, name string) (T, bool, error)
+
+	// HasInitiated marks the store has been initiated, A resync may be required after the store is initiated
+	// when building a client.
+	HasInitiated() bool
+}
+
+// ReceivedHandler handles a resource that is received by a source/agent client with the store, e.g. a source updates
+// the status of the resource in the store, an agent adds/updates the resource in the store.
+type ReceivedHandler[T Object] func(store WatcherStore[T], action types.ResourceAction, obj T) error
+
+func WaitForStoreInit(ctx context.Context, cacheSyncs ...StoreInitiated) bool {
+	err := wait.PollUntilContextCancel(
+		ctx,
+		syncedPollPeriod,
+		true,
+		func(ctx context.Context) (bool, error) {
+			for _, syncFunc := range cacheSyncs {
+				if !syncFunc() {
+					return false, nil
+				}
+			}
+			return true, nil
+		},
+	)
+	if err != nil {
+		klog.Errorf("stop WaitForStoreInit, %v", err)
+		return false
+	}
+
+	return true
+}
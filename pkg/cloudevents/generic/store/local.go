@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// ListLocalObjectsFunc loads the resources from the local environment.
+type ListLocalObjectsFunc[T Object] func(ctx context.Context) ([]T, error)
+
+type watchEvent struct {
+	Key  string
+	Type watch.EventType
+	// Object is the deleted resource, it's only set for the deleted event
+	Object Object
+}
+
+// LocalWatcherStore caches the resources in this local store and provide the watch ability by watch event channel.
+//
+// It is used for building a source client.
+type LocalWatcherStore[T Object] struct {
+	baseStore[T]
+	watcher    *Watcher
+	eventQueue cache.Queue
+	handler    ReceivedHandler[T]
+}
+
+// NewLocalWatcherStore returns a LocalWatcherStore with the resources that are listed by the ListLocalObjectsFunc, the
+// received resources are handled by the given handler.
+func NewLocalWatcherStore[T Object](ctx context.Context, listFunc ListLocalObjectsFunc[T],
+	handler ReceivedHandler[T]) (*LocalWatcherStore[T], error) {
+	objs, err := listFunc(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// A local store to cache the resources
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	for _, obj := range objs {
+		if err := store.Add(obj.DeepCopyObject()); err != nil {
+			return nil, err
+		}
+	}
+
+	s := &LocalWatcherStore[T]{
+		baseStore: baseStore[T]{
+			store:     store,
+			initiated: true,
+		},
+
+		watcher: NewWatcher(),
+
+		// A queue to save the client send events, if run a client without a watcher,
+		// it will block the client, this queue helps to resolve this blocking.
+		// Only save the latest event for a resource.
+		eventQueue: cache.NewFIFO(func(obj interface{}) (string, error) {
+			evt, ok := obj.(*watchEvent)
+			if !ok {
+				return "", fmt.Errorf("unknown object type %T", obj)
+			}
+
+			return evt.Key, nil
+		}),
+
+		handler: handler,
+	}
+
+	// start a goroutine to handle the events that are produced by the client
+	go wait.Until(s.processLoop, time.Second, ctx.Done())
+
+	return s, nil
+}
+
+// Add a resource to the cache and send an event to the event queue
+func (s *LocalWatcherStore[T]) Add(obj T) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.store.Add(obj); err != nil {
+		return err
+	}
+
+	return s.eventQueue.Add(&watchEvent{Key: key(obj), Type: watch.Added})
+}
+
+// Update a resource in the cache and send an event to the event queue
+func (s *LocalWatcherStore[T]) Update(obj T) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.store.Update(obj); err != nil {
+		return err
+	}
+
+	return s.eventQueue.Update(&watchEvent{Key: key(obj), Type: watch.Modified})
+}
+
+// Delete a resource from the cache and send an event to the event queue
+func (s *LocalWatcherStore[T]) Delete(obj T) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.store.Delete(obj); err != nil {
+		return err
+	}
+
+	return s.eventQueue.Update(&watchEvent{Key: key(obj), Type: watch.Deleted, Object: obj})
+}
+
+// HandleReceivedResource handles the received resource with the handler of the store.
+func (s *LocalWatcherStore[T]) HandleReceivedResource(action types.ResourceAction, obj T) error {
+	return s.handler(s, action, obj)
+}
+
+func (s *LocalWatcherStore[T]) HasInitiated() bool {
+	return s.initiated
+}
+
+func (s *LocalWatcherStore[T]) GetWatcher(namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	// TODO may consider to support watch with namespace
+	if namespace != metav1.NamespaceAll {
+		return nil, fmt.Errorf("unsupported to watch from the namespace %s", namespace)
+	}
+
+	return s.watcher, nil
+}
+
+// processLoop drains the event queue and send the event to the watch channel.
+func (s *LocalWatcherStore[T]) processLoop() {
+	for {
+		// this will be blocked until the event queue has events
+		obj, err := s.eventQueue.Pop(func(interface{}, bool) error {
+			// do nothing
+			return nil
+		})
+		if err != nil {
+			if err == cache.ErrFIFOClosed {
+				return
+			}
+
+			klog.Warningf("failed to pop the %v requeue it, %v", obj, err)
+			// this is the safe way to re-enqueue.
+			if err := s.eventQueue.AddIfNotPresent(obj); err != nil {
+				klog.Errorf("failed to requeue the obj %v, %v", obj, err)
+				return
+			}
+		}
+
+		evt, ok := obj.(*watchEvent)
+		if !ok {
+			klog.Errorf("unknown the object type %T from the event queue", obj)
+			return
+		}
+
+		obj, exists, err := s.store.GetByKey(evt.Key)
+		if err != nil {
+			klog.Errorf("failed to get the resource %s, %v", evt.Key, err)
+			return
+		}
+
+		if !exists {
+			if evt.Type == watch.Deleted && evt.Object != nil {
+				// the resource has been deleted, return its last state
+				// this will be blocked until this event is consumed
+				s.watcher.Receive(watch.Event{Type: watch.Deleted, Object: evt.Object})
+				return
+			}
+
+			klog.Errorf("the resource %s does not exist in the cache", evt.Key)
+			return
+		}
+
+		resource, ok := obj.(T)
+		if !ok {
+			klog.Errorf("unknown the object type %T from the cache", obj)
+			return
+		}
+
+		// this will be blocked until this event is consumed
+		s.watcher.Receive(watch.Event{Type: evt.Type, Object: resource})
+	}
+}
+
+func key(obj metav1.Object) string {
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func newConfigMap(namespace, name string, labels map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       namespace,
+			Name:            name,
+			UID:             kubetypes.UID(namespace + "-" + name),
+			ResourceVersion: "1",
+			Labels:          labels,
+		},
+	}
+}
+
+func TestLocalWatcherStore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the received config map is updated to the store after it fails once
+	failures := atomic.Int32{}
+	processor := NewReceivedProcessor(ctx, "test", func(store WatcherStore[*corev1.ConfigMap],
+		action types.ResourceAction, obj *corev1.ConfigMap) error {
+		if failures.Add(1) == 1 {
+			return fmt.Errorf("failed")
+		}
+		return store.Update(obj)
+	})
+
+	store, err := NewLocalWatcherStore(ctx, func(ctx context.Context) ([]*corev1.ConfigMap, error) {
+		return []*corev1.ConfigMap{
+			newConfigMap("ns1", "cm1", map[string]string{"app": "test"}),
+			newConfigMap("ns1", "cm2", nil),
+			newConfigMap("ns2", "cm1", map[string]string{"app": "test"}),
+		}, nil
+	}, processor.Handle)
+	require.NoError(t, err)
+	require.True(t, store.HasInitiated())
+
+	all, err := store.ListAll()
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	listed, err := store.List("ns1", metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+
+	listed, err = store.List(metav1.NamespaceAll, metav1.ListOptions{LabelSelector: "app=test"})
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+
+	listed, err = store.List(metav1.NamespaceAll, metav1.ListOptions{FieldSelector: "metadata.namespace=ns2"})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.Equal(t, "ns2", listed[0].Namespace)
+
+	_, err = store.List(metav1.NamespaceAll, metav1.ListOptions{LabelSelector: "app in test"})
+	require.Error(t, err)
+
+	_, exists, err := store.Get("ns1", "cm3")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	_, err = store.GetWatcher("ns1", metav1.ListOptions{})
+	require.Error(t, err)
+
+	watcher, err := store.GetWatcher(metav1.NamespaceAll, metav1.ListOptions{})
+	require.NoError(t, err)
+
+	// handle a received config map
+	updated := newConfigMap("ns1", "cm2", map[string]string{"app": "test"})
+	require.NoError(t, store.HandleReceivedResource(types.StatusModified, updated))
+	evt := nextWatchEvent(t, watcher)
+	require.Equal(t, watch.Modified, evt.Type)
+	require.Equal(t, updated, evt.Object)
+
+	cm, exists, err := store.Get("ns1", "cm2")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "test", cm.Labels["app"])
+
+	// the last state of the deleted config map is sent to the watcher
+	require.NoError(t, store.Delete(cm))
+	evt = nextWatchEvent(t, watcher)
+	require.Equal(t, watch.Deleted, evt.Type)
+	require.Equal(t, cm, evt.Object)
+}
+
+func nextWatchEvent(t *testing.T, watcher watch.Interface) watch.Event {
+	select {
+	case evt := <-watcher.ResultChan():
+		return evt
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout to wait for the watch event")
+	}
+	return watch.Event{}
+}
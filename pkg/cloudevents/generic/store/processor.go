@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// ReceivedProcessor queues the received resources and processes them with a ReceivedHandler asynchronously, the
+// resources that fail to be processed are requeued with a backoff.
+type ReceivedProcessor[T Object] struct {
+	name      string
+	resources workqueue.RateLimitingInterface
+	handler   ReceivedHandler[T]
+}
+
+type receivedResource[T Object] struct {
+	store  WatcherStore[T]
+	action types.ResourceAction
+	obj    T
+}
+
+// NewReceivedProcessor returns a ReceivedProcessor with the given name and handler, the processor runs until the
+// context is done.
+func NewReceivedProcessor[T Object](ctx context.Context, name string, handler ReceivedHandler[T]) *ReceivedProcessor[T] {
+	p := &ReceivedProcessor[T]{
+		name:      name,
+		resources: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), name),
+		handler:   handler,
+	}
+
+	// start a goroutine to process the received resources from the queue
+	go p.run(ctx.Done())
+
+	return p
+}
+
+// Handle is a ReceivedHandler that queues the received resource to be processed.
+func (p *ReceivedProcessor[T]) Handle(store WatcherStore[T], action types.ResourceAction, obj T) error {
+	p.resources.Add(&receivedResource[T]{store: store, action: action, obj: obj})
+	generic.UpdateReceivedQueueDepthMetric(p.name, p.resources.Len())
+	return nil
+}
+
+func (p *ReceivedProcessor[T]) run(stopCh <-chan struct{}) {
+	defer p.resources.ShutDown()
+
+	// start a goroutine to handle the resources from the queue
+	// the .Until will re-kick the runWorker one second after the runWorker completes
+	go wait.Until(p.runWorker, time.Second, stopCh)
+
+	// wait until we're told to stop
+	<-stopCh
+}
+
+func (p *ReceivedProcessor[T]) runWorker() {
+	// hot loop until we're told to stop. processNext will automatically wait until there's resource available, so
+	// we don't worry about secondary waits
+	for p.processNext() {
+	}
+}
+
+// processNext deals with one resource off the queue.
+func (p *ReceivedProcessor[T]) processNext() bool {
+	// pull the next resource from queue.
+	// resources queue blocks until it can return an item to be processed
+	key, quit := p.resources.Get()
+	if quit {
+		// the current queue is shutdown and becomes empty, quit this process
+		return false
+	}
+	defer p.resources.Done(key)
+	generic.UpdateReceivedQueueDepthMetric(p.name, p.resources.Len())
+
+	received := key.(*receivedResource[T])
+	if err := p.handler(received.store, received.action, received.obj); err != nil {
+		klog.V(4).Infof("failed to process the received resource %s, %v", received.obj.GetUID(), err)
+		// we failed to handle the resource, we should requeue the item to work on later
+		// this method will add a backoff to avoid hotlooping on particular items
+		p.resources.AddRateLimited(key)
+		return true
+	}
+
+	// we handle the resource successfully, tell the queue to stop tracking history for this resource
+	p.resources.Forget(key)
+	return true
+}
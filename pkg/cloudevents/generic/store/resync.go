@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+
+	"k8s.io/klog/v2"
+)
+
+// Resyncer resyncs the resources with the peers, it's implemented by the source/agent CloudEventsClient.
+type Resyncer interface {
+	// Resync the resources of the given cluster name/source ID.
+	Resync(ctx context.Context, target string) error
+
+	// ReconnectedChan returns a chan which indicates the client is reconnected.
+	ReconnectedChan() <-chan struct{}
+}
+
+// StartResync resyncs the resources of the given cluster name/source ID after the store is initiated, and resyncs
+// them again each time the client is reconnected, until the context is done.
+func StartResync(ctx context.Context, initiated StoreInitiated, resyncer Resyncer, target string) {
+	// start a go routine to receive client reconnect signal
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-resyncer.ReconnectedChan():
+				// when receiving a client reconnected signal, we resync the resources
+				if err := resyncer.Resync(ctx, target); err != nil {
+					klog.Errorf("failed to send resync request, %v", err)
+				}
+			}
+		}
+	}()
+
+	// start a go routine to resync the resources after the store is initiated
+	go func() {
+		if WaitForStoreInit(ctx, initiated) {
+			if err := resyncer.Resync(ctx, target); err != nil {
+				klog.Errorf("failed to send resync request, %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,122 @@
+package generic
+
+import (
+	"context"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const tracerName = "open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+
+// Names of the spans:
+const (
+	publishSpanName       = "cloudevents.publish"
+	receiveSpanName       = "cloudevents.receive"
+	handleSpanName        = "cloudevents.handle"
+	resyncSpanName        = "cloudevents.resync"
+	respondResyncSpanName = "cloudevents.resync.respond"
+)
+
+// Names of the span attributes, the cloud events attributes follow the OpenTelemetry semantic conventions.
+const (
+	eventIDAttribute        = attribute.Key("cloudevents.event_id")
+	eventSourceAttribute    = attribute.Key("cloudevents.event_source")
+	eventTypeAttribute      = attribute.Key("cloudevents.event_type")
+	clusterNameAttribute    = attribute.Key("cloudevents.clustername")
+	resourceIDAttribute     = attribute.Key("cloudevents.resourceid")
+	resourceActionAttribute = attribute.Key("cloudevents.resource_action")
+)
+
+// traceContext propagates the W3C trace context with the cloud events distributed tracing extension.
+var traceContext = propagation.TraceContext{}
+
+func newTracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = noop.NewTracerProvider()
+	}
+	return provider.Tracer(tracerName)
+}
+
+// startEventSpan starts a span for an event. If the context does not have a span, the trace context of the event is
+// used as the parent, so the span is linked to the sender of the event.
+func startEventSpan(ctx context.Context, tracer trace.Tracer, name string, kind trace.SpanKind,
+	evt cloudevents.Event) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = extractTraceContext(ctx, evt)
+	}
+
+	attrs := []attribute.KeyValue{
+		eventIDAttribute.String(evt.ID()),
+		eventSourceAttribute.String(evt.Source()),
+		eventTypeAttribute.String(evt.Type()),
+	}
+	exts := evt.Extensions()
+	if clusterName, err := cloudeventstypes.ToString(exts[types.ExtensionClusterName]); err == nil {
+		attrs = append(attrs, clusterNameAttribute.String(clusterName))
+	}
+	if resourceID, err := cloudeventstypes.ToString(exts[types.ExtensionResourceID]); err == nil {
+		attrs = append(attrs, resourceIDAttribute.String(resourceID))
+	}
+
+	return tracer.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// withSpan runs the func in a span and records its error to the span.
+func withSpan(ctx context.Context, tracer trace.Tracer, name string, fn func(ctx context.Context) error,
+	attrs ...attribute.KeyValue) error {
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	defer span.End()
+
+	err := fn(ctx)
+	recordSpanError(span, err)
+	return err
+}
+
+func recordSpanError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// injectTraceContext sets the trace context of the context to the distributed tracing extension of the event.
+func injectTraceContext(ctx context.Context, evt *cloudevents.Event) {
+	carrier := propagation.MapCarrier{}
+	traceContext.Inject(ctx, carrier)
+
+	traceParent := carrier.Get(extensions.TraceParentExtension)
+	if len(traceParent) == 0 {
+		return
+	}
+
+	evt.SetExtension(extensions.TraceStateExtension, nil)
+	extensions.DistributedTracingExtension{
+		TraceParent: traceParent,
+		TraceState:  carrier.Get(extensions.TraceStateExtension),
+	}.AddTracingAttributes(evt)
+}
+
+// extractTraceContext returns a context with the remote span context that is got from the distributed tracing
+// extension of the event.
+func extractTraceContext(ctx context.Context, evt cloudevents.Event) context.Context {
+	ext, ok := extensions.GetDistributedTracingExtension(evt)
+	if !ok {
+		return ctx
+	}
+
+	return traceContext.Extract(ctx, propagation.MapCarrier{
+		extensions.TraceParentExtension: ext.TraceParent,
+		extensions.TraceStateExtension:  ext.TraceState,
+	})
+}
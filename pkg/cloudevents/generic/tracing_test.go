@@ -0,0 +1,133 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestTracePropagation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	sourceOptions := fake.NewSourceOptions(gochan.New(), testSourceName)
+	sourceOptions.TracerProvider = provider
+	source, err := NewCloudEventSourceClient[*mockResource](ctx, sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	eventChan := make(chan cloudevents.Event, 1)
+	go func() {
+		_ = source.cloudEventsClient.StartReceiver(ctx, func(event cloudevents.Event) {
+			eventChan <- event
+		})
+	}()
+
+	// the caller starts a span to create the resource
+	callerCtx, callerSpan := provider.Tracer("test").Start(ctx, "create")
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	resource := &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"}
+	require.NoError(t, source.Publish(callerCtx, eventType, resource))
+	callerSpan.End()
+
+	evt := <-eventChan
+	require.Contains(t, evt.Extensions(), "traceparent")
+
+	// the agent receives and handles the event in the same trace
+	agentOptions := fake.NewAgentOptions(gochan.New(), nil, "cluster1", testAgentName)
+	agentOptions.TracerProvider = provider
+	agent, err := NewCloudEventAgentClient[*mockResource](ctx, agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	receive := agent.traceReceived(func(ctx context.Context, evt cloudevents.Event) {
+		agent.receive(ctx, evt, func(action types.ResourceAction, obj *mockResource) error {
+			return fmt.Errorf("failed to handle")
+		})
+	})
+	receive(ctx, evt)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	require.Contains(t, spans, publishSpanName)
+	require.Contains(t, spans, receiveSpanName)
+	require.Contains(t, spans, handleSpanName)
+
+	traceID := callerSpan.SpanContext().TraceID()
+	for _, span := range spans {
+		require.Equal(t, traceID, span.SpanContext().TraceID())
+	}
+
+	require.Equal(t, callerSpan.SpanContext().SpanID(), spans[publishSpanName].Parent().SpanID())
+	require.Equal(t, trace.SpanKindProducer, spans[publishSpanName].SpanKind())
+	require.Equal(t, spans[publishSpanName].SpanContext().SpanID(), spans[receiveSpanName].Parent().SpanID())
+	require.Equal(t, trace.SpanKindConsumer, spans[receiveSpanName].SpanKind())
+	require.Equal(t, spans[receiveSpanName].SpanContext().SpanID(), spans[handleSpanName].Parent().SpanID())
+	require.Equal(t, codes.Error, spans[handleSpanName].Status().Code)
+}
+
+func TestTraceResync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	recorder := tracetest.NewSpanRecorder()
+	agentOptions := fake.NewAgentOptions(gochan.New(), nil, "cluster1", testAgentName)
+	agentOptions.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	agent, err := NewCloudEventAgentClient[*mockResource](ctx, agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	require.NoError(t, agent.Resync(ctx, testSourceName))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, publishSpanName, spans[0].Name())
+	require.Equal(t, resyncSpanName, spans[1].Name())
+	require.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
+
+func TestNoopTracer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceOptions := fake.NewSourceOptions(gochan.New(), testSourceName)
+	source, err := NewCloudEventSourceClient[*mockResource](ctx, sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	require.NoError(t, err)
+
+	eventChan := make(chan cloudevents.Event, 1)
+	go func() {
+		_ = source.cloudEventsClient.StartReceiver(ctx, func(event cloudevents.Event) {
+			eventChan <- event
+		})
+	}()
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	resource := &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"}
+	require.NoError(t, source.Publish(ctx, eventType, resource))
+
+	// there is no trace context to propagate
+	evt := <-eventChan
+	require.NotContains(t, evt.Extensions(), "traceparent")
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/google/uuid"
 )
 
@@ -39,6 +40,18 @@ const (
 
 	// ResyncRequestAction represents the cloud event is for the resync response.
 	ResyncResponseAction EventAction = "resync_response"
+
+	// MerkleResyncRequestAction represents the cloud event is for the merkle tree based resync request, the request
+	// carries the merkle tree nodes of the requester.
+	MerkleResyncRequestAction EventAction = "merkle_resync_request"
+
+	// MerkleResyncResponseAction represents the cloud event is for the merkle tree based resync response, the
+	// response carries the child merkle tree nodes of the responder for the mismatched nodes of the request.
+	MerkleResyncResponseAction EventAction = "merkle_resync_response"
+
+	// HeartbeatAction represents the cloud event is an agent heartbeat, the heartbeat is published by an agent
+	// periodically to tell the sources that it's alive.
+	HeartbeatAction EventAction = "heartbeat"
 )
 
 const (
@@ -60,6 +73,36 @@ const (
 
 	// ExtensionOriginalSource is the cloud event extension key of the original source.
 	ExtensionOriginalSource = "originalsource"
+
+	// ExtensionChunkID is the cloud event extension key of the chunk ID. A resync request may be split into several
+	// chunks, the chunks of one resync request have the same chunk ID.
+	ExtensionChunkID = "chunkid"
+
+	// ExtensionChunkIndex is the cloud event extension key of the chunk index, the index starts from zero.
+	ExtensionChunkIndex = "chunkindex"
+
+	// ExtensionChunkTotal is the cloud event extension key of the total number of the chunks of a resync request.
+	ExtensionChunkTotal = "chunktotal"
+
+	// ExtensionAcceptEncodings is the cloud event extension key of the content types that are accepted by the sender
+	// of a resync request, the content types are separated by commas.
+	ExtensionAcceptEncodings = "acceptencodings"
+
+	// ExtensionDataVersions is the cloud event extension key of the versions of the cloud events data type that are
+	// supported by the sender of a resync request, the versions are separated by commas.
+	ExtensionDataVersions = "dataversions"
+
+	// ExtensionSignature is the cloud event extension key of the signature of the event, the signature is a JWS with
+	// detached content.
+	ExtensionSignature = "signature"
+
+	// ExtensionEncryptedKey is the cloud event extension key of the data key that encrypts the event data, the data
+	// key is wrapped by the key of the recipient.
+	ExtensionEncryptedKey = "encryptedkey"
+
+	// ExtensionPlainContentType is the cloud event extension key of the content type of the event data before it is
+	// encrypted.
+	ExtensionPlainContentType = "plaincontenttype"
 )
 
 // ResourceAction represents an action on a resource object on the source or agent.
@@ -151,6 +194,20 @@ func (t CloudEventsDataType) String() string {
 	return fmt.Sprintf("%s.%s.%s", t.Group, t.Version, t.Resource)
 }
 
+// HeartbeatEventDataType is the cloud events data type of the agent heartbeats.
+var HeartbeatEventDataType = CloudEventsDataType{
+	Group:    "io.open-cluster-management.agents",
+	Version:  "v1",
+	Resource: "heartbeats",
+}
+
+// HeartbeatEventType is the cloud events type of the agent heartbeats.
+var HeartbeatEventType = CloudEventsType{
+	CloudEventsDataType: HeartbeatEventDataType,
+	SubResource:         SubResourceStatus,
+	Action:              HeartbeatAction,
+}
+
 // CloudEventsType represents the type of cloud events, which describes the type of cloud event data.
 type CloudEventsType struct {
 	// CloudEventsDataType uniquely identifies the type of cloud event data.
@@ -217,6 +274,11 @@ type EventBuilder struct {
 	resourceVersion   *int64
 	eventType         CloudEventsType
 	deletionTimestamp time.Time
+	chunkID           string
+	chunkIndex        int
+	chunkTotal        int
+	traceParent       string
+	traceState        string
 }
 
 func NewEventBuilder(source string, eventType CloudEventsType) *EventBuilder {
@@ -256,6 +318,20 @@ func (b *EventBuilder) WithDeletionTimestamp(timestamp time.Time) *EventBuilder
 	return b
 }
 
+func (b *EventBuilder) WithChunk(chunkID string, index, total int) *EventBuilder {
+	b.chunkID = chunkID
+	b.chunkIndex = index
+	b.chunkTotal = total
+	return b
+}
+
+// WithTraceContext sets the W3C trace context of the event, so the receiver of the event can continue the trace.
+func (b *EventBuilder) WithTraceContext(traceParent, traceState string) *EventBuilder {
+	b.traceParent = traceParent
+	b.traceState = traceState
+	return b
+}
+
 func (b *EventBuilder) NewEvent() cloudevents.Event {
 	evt := cloudevents.NewEvent()
 	evt.SetID(uuid.New().String())
@@ -282,5 +358,18 @@ func (b *EventBuilder) NewEvent() cloudevents.Event {
 		evt.SetExtension(ExtensionDeletionTimestamp, b.deletionTimestamp)
 	}
 
+	if len(b.chunkID) != 0 {
+		evt.SetExtension(ExtensionChunkID, b.chunkID)
+		evt.SetExtension(ExtensionChunkIndex, b.chunkIndex)
+		evt.SetExtension(ExtensionChunkTotal, b.chunkTotal)
+	}
+
+	if len(b.traceParent) != 0 {
+		extensions.DistributedTracingExtension{
+			TraceParent: b.traceParent,
+			TraceState:  b.traceState,
+		}.AddTracingAttributes(&evt)
+	}
+
 	return evt
 }
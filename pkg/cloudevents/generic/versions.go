@@ -0,0 +1,237 @@
+package generic
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// versionNegotiator negotiates the versions of the cloud events data types with the peers. The versions that are
+// supported by a peer are learned from the resync requests of the peer, the sent events are converted to the newest
+// version that is supported by both sides, and the received events are converted to the newest version that has a
+// codec.
+type versionNegotiator struct {
+	sync.RWMutex
+
+	converter options.EventConverter
+	// codecVersions is keyed by the data type group and resource, the versions have codecs and are sorted from the
+	// newest to the oldest
+	codecVersions map[string][]string
+	// supported is keyed by the data type group and resource, the versions have codecs or can be converted from and
+	// back to a version that has a codec
+	supported map[string][]string
+	// peers is keyed by the peer and the data type group and resource
+	peers       map[string][]string
+	sendPeer    peerFunc
+	receivePeer peerFunc
+}
+
+func newVersionNegotiator(converter options.EventConverter, dataTypes []types.CloudEventsDataType,
+	sendPeer, receivePeer peerFunc) *versionNegotiator {
+	if converter == nil {
+		return nil
+	}
+
+	codecVersions := map[string]sets.Set[string]{}
+	for _, dataType := range dataTypes {
+		key := groupResource(dataType)
+		if _, ok := codecVersions[key]; !ok {
+			codecVersions[key] = sets.New[string]()
+		}
+		codecVersions[key].Insert(dataType.Version)
+	}
+
+	supported := map[string]sets.Set[string]{}
+	for _, dataType := range dataTypes {
+		key := groupResource(dataType)
+		if _, ok := supported[key]; !ok {
+			supported[key] = sets.New[string]()
+		}
+		supported[key].Insert(dataType.Version)
+
+		// a version is supported only if the events can be converted to it and back, otherwise the received events
+		// of the version cannot be handled by any codec
+		for _, version := range converter.ConvertibleVersions(dataType) {
+			versionDataType := dataType
+			versionDataType.Version = version
+			if codecVersions[key].HasAny(converter.ConvertibleVersions(versionDataType)...) {
+				supported[key].Insert(version)
+			}
+		}
+	}
+
+	return &versionNegotiator{
+		converter:     converter,
+		codecVersions: sortedVersions(codecVersions),
+		supported:     sortedVersions(supported),
+		peers:         map[string][]string{},
+		sendPeer:      sendPeer,
+		receivePeer:   receivePeer,
+	}
+}
+
+// convertSent advertises the supported versions in the resync requests and converts the event to the newest version
+// that is supported by the peer of the event. The event is not changed if the versions of the peer are unknown.
+func (n *versionNegotiator) convertSent(evt cloudevents.Event) (cloudevents.Event, error) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return evt, err
+	}
+
+	key := groupResource(eventType.CloudEventsDataType)
+	if isResyncRequest(eventType.Action) {
+		if supported, ok := n.supported[key]; ok {
+			evt = evt.Clone()
+			evt.SetExtension(types.ExtensionDataVersions, strings.Join(supported, ","))
+		}
+	}
+
+	n.RLock()
+	peerVersions, ok := n.peers[peerKey(n.sendPeer(evt), key)]
+	n.RUnlock()
+	if !ok || sets.New(peerVersions...).Has(eventType.CloudEventsDataType.Version) {
+		return evt, nil
+	}
+
+	// the resync requests have no resource data, only their types are changed, the other events are converted
+	candidates := n.codecVersions[key]
+	if !isResyncRequest(eventType.Action) {
+		candidates = append([]string{eventType.CloudEventsDataType.Version},
+			n.converter.ConvertibleVersions(eventType.CloudEventsDataType)...)
+	}
+
+	target, ok := newestCommonVersion(candidates, peerVersions)
+	if !ok {
+		klog.Warningf("no common version of %s with the peer %s, the supported versions of the peer are %v",
+			eventType.CloudEventsDataType, n.sendPeer(evt), peerVersions)
+		return evt, nil
+	}
+
+	return n.convert(evt, *eventType, target)
+}
+
+// convertReceived learns the supported versions of the peer from the resync requests and converts the event to the
+// newest version that has a codec. The event is not changed if the version of the event has a codec.
+func (n *versionNegotiator) convertReceived(evt cloudevents.Event) (cloudevents.Event, error) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		// the event will be rejected by the client
+		return evt, nil
+	}
+
+	key := groupResource(eventType.CloudEventsDataType)
+	if isResyncRequest(eventType.Action) {
+		n.learn(evt, key)
+	}
+
+	codecVersions, ok := n.codecVersions[key]
+	if !ok || sets.New(codecVersions...).Has(eventType.CloudEventsDataType.Version) {
+		return evt, nil
+	}
+
+	return n.convert(evt, *eventType, codecVersions[0])
+}
+
+// learn records the versions that are supported by the peer of a received resync request. If the resync request does
+// not advertise the supported versions, e.g. the peer is rolled back to a version without negotiation support, the
+// versions that were learned from the peer are forgotten, so the events are sent to the peer as they are.
+func (n *versionNegotiator) learn(evt cloudevents.Event, key string) {
+	peer := peerKey(n.receivePeer(evt), key)
+
+	versions, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionDataVersions])
+	if err != nil {
+		n.Lock()
+		defer n.Unlock()
+		delete(n.peers, peer)
+		return
+	}
+
+	peerVersions := []string{}
+	for _, v := range strings.Split(versions, ",") {
+		peerVersions = append(peerVersions, strings.TrimSpace(v))
+	}
+
+	n.Lock()
+	defer n.Unlock()
+	n.peers[peer] = peerVersions
+}
+
+func (n *versionNegotiator) convert(evt cloudevents.Event, eventType types.CloudEventsType, version string) (
+	cloudevents.Event, error) {
+	if isResyncRequest(eventType.Action) {
+		evt = evt.Clone()
+		eventType.CloudEventsDataType.Version = version
+		evt.SetType(eventType.String())
+		return evt, nil
+	}
+
+	return n.converter.Convert(evt, version)
+}
+
+// newestCommonVersion returns the newest version in the candidates that is also in the peer versions.
+func newestCommonVersion(candidates, peerVersions []string) (string, bool) {
+	common := sets.New(candidates...).Intersection(sets.New(peerVersions...)).UnsortedList()
+	if len(common) == 0 {
+		return "", false
+	}
+
+	sortVersions(common)
+	return common[0], true
+}
+
+func sortedVersions(versions map[string]sets.Set[string]) map[string][]string {
+	sorted := map[string][]string{}
+	for key, vs := range versions {
+		sorted[key] = vs.UnsortedList()
+		sortVersions(sorted[key])
+	}
+	return sorted
+}
+
+// codecDataTypes returns the data types of the codecs, including the supported versions of the VersionedCodecs.
+func codecDataTypes[T ResourceObject](codecs ...Codec[T]) []types.CloudEventsDataType {
+	dataTypes := []types.CloudEventsDataType{}
+	for _, codec := range codecs {
+		dataTypes = append(dataTypes, codec.EventDataType())
+
+		versionedCodec, ok := codec.(VersionedCodec[T])
+		if !ok {
+			continue
+		}
+
+		for _, v := range versionedCodec.SupportedVersions() {
+			dataType := codec.EventDataType()
+			dataType.Version = v
+			dataTypes = append(dataTypes, dataType)
+		}
+	}
+	return dataTypes
+}
+
+// newCodecs registers the codecs with their data types, a VersionedCodec is registered with each of its versions.
+func newCodecs[T ResourceObject](codecs ...Codec[T]) map[types.CloudEventsDataType]Codec[T] {
+	evtCodecs := make(map[types.CloudEventsDataType]Codec[T])
+	for _, codec := range codecs {
+		for _, dataType := range codecDataTypes(codec) {
+			evtCodecs[dataType] = codec
+		}
+	}
+	return evtCodecs
+}
+
+func groupResource(dataType types.CloudEventsDataType) string {
+	return fmt.Sprintf("%s.%s", dataType.Group, dataType.Resource)
+}
+
+func peerKey(peer, key string) string {
+	return fmt.Sprintf("%s/%s", peer, key)
+}
@@ -0,0 +1,142 @@
+package generic
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type mockVersionedCodec struct {
+	*mockResourceCodec
+}
+
+func (c *mockVersionedCodec) SupportedVersions() []string {
+	return []string{"v2"}
+}
+
+func TestVersionNegotiator(t *testing.T) {
+	// a source with the v2 codec talks with an agent that only supports v1
+	negotiator := newVersionNegotiator(newMockConversionRegistry(t), []types.CloudEventsDataType{mockEventDataTypeV2},
+		extensionPeer(types.ExtensionClusterName), extensionPeer(types.ExtensionClusterName))
+
+	// the supported versions are advertised in the resync requests
+	request := newMockEvent(t, mockEventDataTypeV2, types.ResyncRequestAction)
+	sent, err := negotiator.convertSent(request)
+	require.NoError(t, err)
+	require.Equal(t, "v2,v1", sent.Extensions()[types.ExtensionDataVersions])
+	require.Equal(t, request.Type(), sent.Type())
+
+	// the events are sent as they are before the versions of the peer are learned
+	evt := newMockEvent(t, mockEventDataTypeV2, "create_request")
+	sent, err = negotiator.convertSent(evt)
+	require.NoError(t, err)
+	require.Equal(t, evt, sent)
+
+	// the resync request of the peer is received with the version that has a codec
+	request = newMockEvent(t, mockEventDataType, types.ResyncRequestAction)
+	request.SetExtension(types.ExtensionDataVersions, "v1")
+	received, err := negotiator.convertReceived(request)
+	require.NoError(t, err)
+	require.Equal(t, "resources.test.v2.mockresources.spec.resync_request", received.Type())
+	require.Equal(t, request.Data(), received.Data())
+
+	// the events are converted to the version of the peer after the versions of the peer are learned
+	sent, err = negotiator.convertSent(evt)
+	require.NoError(t, err)
+	require.Equal(t, "resources.test.v1.mockresources.spec.create_request", sent.Type())
+	require.JSONEq(t, `{"convertedTo":"v1"}`, string(sent.Data()))
+
+	// the events of the other clusters are not converted
+	otherEvt := evt.Clone()
+	otherEvt.SetExtension(types.ExtensionClusterName, "cluster2")
+	sent, err = negotiator.convertSent(otherEvt)
+	require.NoError(t, err)
+	require.Equal(t, otherEvt, sent)
+
+	// the received events are converted to the version that has a codec
+	received, err = negotiator.convertReceived(newMockEvent(t, mockEventDataType, "update_request"))
+	require.NoError(t, err)
+	require.Equal(t, "resources.test.v2.mockresources.spec.update_request", received.Type())
+	require.JSONEq(t, `{"convertedTo":"v2"}`, string(received.Data()))
+
+	// the peer is downgraded to a version without negotiation support, the versions of the peer are forgotten
+	_, err = negotiator.convertReceived(newMockEvent(t, mockEventDataType, types.ResyncRequestAction))
+	require.NoError(t, err)
+	sent, err = negotiator.convertSent(evt)
+	require.NoError(t, err)
+	require.Equal(t, evt, sent)
+
+	// the events of the other data types are not changed
+	heartbeat := types.NewEventBuilder("agent1", types.HeartbeatEventType).NewEvent()
+	received, err = negotiator.convertReceived(heartbeat)
+	require.NoError(t, err)
+	require.Equal(t, heartbeat, received)
+
+	// the negotiation is disabled without a converter
+	require.Nil(t, newVersionNegotiator(nil, []types.CloudEventsDataType{mockEventDataType}, nil, nil))
+}
+
+func TestVersionNegotiatorWithOneWayConversion(t *testing.T) {
+	// the v1 events can be converted to v2, but the v2 events cannot be converted back to v1
+	registry := NewConversionRegistry()
+	require.NoError(t, registry.Register(mockEventDataType, mockEventDataTypeV2, func(evt cloudevents.Event) (cloudevents.Event, error) {
+		return evt, nil
+	}))
+
+	// the v2 is not advertised by the v1 codec, since the received v2 events cannot be handled
+	negotiator := newVersionNegotiator(registry, []types.CloudEventsDataType{mockEventDataType},
+		extensionPeer(types.ExtensionClusterName), extensionPeer(types.ExtensionClusterName))
+	sent, err := negotiator.convertSent(newMockEvent(t, mockEventDataType, types.ResyncRequestAction))
+	require.NoError(t, err)
+	require.Equal(t, "v1", sent.Extensions()[types.ExtensionDataVersions])
+
+	negotiator = newVersionNegotiator(registry, []types.CloudEventsDataType{mockEventDataTypeV2},
+		extensionPeer(types.ExtensionClusterName), extensionPeer(types.ExtensionClusterName))
+	sent, err = negotiator.convertSent(newMockEvent(t, mockEventDataTypeV2, types.ResyncRequestAction))
+	require.NoError(t, err)
+	require.Equal(t, "v2", sent.Extensions()[types.ExtensionDataVersions])
+}
+
+func TestNewestCommonVersion(t *testing.T) {
+	version, ok := newestCommonVersion([]string{"v1alpha1", "v1", "v2"}, []string{"v1beta1", "v1alpha1", "v1"})
+	require.True(t, ok)
+	require.Equal(t, "v1", version)
+
+	_, ok = newestCommonVersion([]string{"v2"}, []string{"v1"})
+	require.False(t, ok)
+}
+
+func TestNewCodecs(t *testing.T) {
+	codecs := newCodecs[*mockResource](&mockVersionedCodec{mockResourceCodec: newMockResourceCodec()})
+	require.Len(t, codecs, 2)
+	require.Contains(t, codecs, mockEventDataType)
+	require.Contains(t, codecs, mockEventDataTypeV2)
+}
+
+func TestAgentReceiveConvertedResourceSpec(t *testing.T) {
+	// the agent only has the v1 codec, the spec of v2 is converted by the registry
+	registry := NewConversionRegistry()
+	require.NoError(t, registry.Register(mockEventDataTypeV2, mockEventDataType, func(evt cloudevents.Event) (cloudevents.Event, error) {
+		return evt, nil
+	}))
+
+	negotiator := newVersionNegotiator(registry, codecDataTypes[*mockResource](newMockResourceCodec()),
+		extensionPeer(types.ExtensionOriginalSource), sourcePeer)
+	evt, err := newMockResourceCodec().Encode("source1", types.CloudEventsType{
+		CloudEventsDataType: mockEventDataTypeV2,
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}, &mockResource{UID: "test1", ResourceVersion: "1", Namespace: "cluster1"})
+	require.NoError(t, err)
+
+	received, err := negotiator.convertReceived(*evt)
+	require.NoError(t, err)
+
+	obj, err := newMockResourceCodec().Decode(&received)
+	require.NoError(t, err)
+	require.Equal(t, "test1", string(obj.UID))
+	require.Equal(t, "resources.test.v1.mockresources.spec.create_request", received.Type())
+}
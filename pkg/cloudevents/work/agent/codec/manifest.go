@@ -79,6 +79,7 @@ func (c *ManifestCodec) Encode(source string, eventType types.CloudEventsType, w
 		WithResourceVersion(resourceVersion).
 		WithClusterName(work.Namespace).
 		WithOriginalSource(originalSource).
+		WithTraceContext(common.TraceContextFromAnnotations(work.Annotations)).
 		NewEvent()
 
 	statusPayload := &payload.ManifestStatus{
@@ -140,6 +141,9 @@ func (c *ManifestCodec) Decode(evt *cloudevents.Event) (*workv1.ManifestWork, er
 		},
 	}
 
+	// keep the trace context of the spec, so the status update can continue the trace
+	common.SetTraceContextAnnotations(work.Annotations, *evt)
+
 	if _, ok := evtExtensions[types.ExtensionDeletionTimestamp]; ok {
 		deletionTimestamp, err := cloudeventstypes.ToTime(evtExtensions[types.ExtensionDeletionTimestamp])
 		if err != nil {
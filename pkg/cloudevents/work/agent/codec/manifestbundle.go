@@ -51,6 +51,7 @@ func (c *ManifestBundleCodec) Encode(source string, eventType types.CloudEventsT
 		WithResourceVersion(resourceVersion).
 		WithClusterName(work.Namespace).
 		WithOriginalSource(originalSource).
+		WithTraceContext(common.TraceContextFromAnnotations(work.Annotations)).
 		NewEvent()
 
 	manifestBundleStatus := &payload.ManifestBundleStatus{
@@ -109,6 +110,9 @@ func (c *ManifestBundleCodec) Decode(evt *cloudevents.Event) (*workv1.ManifestWo
 		},
 	}
 
+	// keep the trace context of the spec, so the status update can continue the trace
+	common.SetTraceContextAnnotations(work.Annotations, *evt)
+
 	if _, ok := evtExtensions[types.ExtensionDeletionTimestamp]; ok {
 		deletionTimestamp, err := cloudeventstypes.ToTime(evtExtensions[types.ExtensionDeletionTimestamp])
 		if err != nil {
@@ -10,6 +10,7 @@ import (
 
 	workv1 "open-cluster-management.io/api/work/v1"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/payload"
 )
 
@@ -243,3 +244,45 @@ func TestManifestBundleDecode(t *testing.T) {
 		})
 	}
 }
+
+func TestManifestBundleTraceContext(t *testing.T) {
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	evt := cloudevents.NewEvent()
+	evt.SetSource("source1")
+	evt.SetType("io.open-cluster-management.works.v1alpha1.manifestbundles.spec.create_request")
+	evt.SetExtension("resourceid", "test")
+	evt.SetExtension("resourceversion", "13")
+	evt.SetExtension("clustername", "cluster1")
+	evt.SetExtension("traceparent", traceParent)
+	evt.SetExtension("tracestate", "test=test")
+	if err := evt.SetData(cloudevents.ApplicationJSON, &payload.ManifestBundle{
+		Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: toConfigMap(t)}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	codec := NewManifestBundleCodec()
+
+	// the trace context of the spec is kept in the work annotations
+	work, err := codec.Decode(&evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if work.Annotations[common.CloudEventsTraceParentAnnotationKey] != traceParent {
+		t.Errorf("unexpected annotations %v", work.Annotations)
+	}
+
+	// the status update continues the trace of the spec
+	statusEvt, err := codec.Encode("cluster1-work-agent", types.CloudEventsType{
+		CloudEventsDataType: payload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "status_update",
+	}, work)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statusEvt.Extensions()["traceparent"] != traceParent || statusEvt.Extensions()["tracestate"] != "test=test" {
+		t.Errorf("unexpected extensions %v", statusEvt.Extensions())
+	}
+}
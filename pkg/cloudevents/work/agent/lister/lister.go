@@ -4,9 +4,11 @@ import (
 	"fmt"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	workv1 "open-cluster-management.io/api/work/v1"
 
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/store"
@@ -17,12 +19,19 @@ type WatcherStoreLister struct {
 	store store.WorkClientWatcherStore
 }
 
+var _ generic.SourcesLister[*workv1.ManifestWork] = &WatcherStoreLister{}
+
 func NewWatcherStoreLister(store store.WorkClientWatcherStore) *WatcherStoreLister {
 	return &WatcherStoreLister{
 		store: store,
 	}
 }
 
+// HasSynced returns true if the WorkClientWatcherStore has been initiated.
+func (l *WatcherStoreLister) HasSynced() bool {
+	return l.store.HasInitiated()
+}
+
 // List returns the ManifestWorks from a WorkClientWatcherStore with list options
 func (l *WatcherStoreLister) List(options types.ListOptions) ([]*workv1.ManifestWork, error) {
 	opts := metav1.ListOptions{}
@@ -48,3 +57,21 @@ func (l *WatcherStoreLister) List(options types.ListOptions) ([]*workv1.Manifest
 
 	return works, nil
 }
+
+// ListSources returns the sources of the ManifestWorks from a WorkClientWatcherStore with the given cluster name, the
+// source of a ManifestWork is recorded by its originalsource label.
+func (l *WatcherStoreLister) ListSources(clusterName string) ([]string, error) {
+	list, err := l.store.List(clusterName, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	sources := sets.New[string]()
+	for _, work := range list.Items {
+		if source, ok := work.Labels[common.CloudEventsOriginalSourceLabelKey]; ok {
+			sources.Insert(source)
+		}
+	}
+
+	return sets.List(sources), nil
+}
@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 
-	"k8s.io/klog/v2"
-
 	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
 	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
 	workv1 "open-cluster-management.io/api/work/v1"
 
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	genericstore "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/store"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 	agentclient "open-cluster-management.io/sdk-go/pkg/cloudevents/work/agent/client"
 	agentlister "open-cluster-management.io/sdk-go/pkg/cloudevents/work/agent/lister"
@@ -24,7 +24,9 @@ import (
 //
 // ClientHolder also implements the ManifestWorksGetter interface.
 type ClientHolder struct {
-	workClientSet workclientset.Interface
+	workClientSet  workclientset.Interface
+	clientStatus   generic.ClientStatusGetter
+	storeInitiated func() bool
 }
 
 var _ workv1client.ManifestWorksGetter = &ClientHolder{}
@@ -41,7 +43,7 @@ func (h *ClientHolder) ManifestWorks(namespace string) workv1client.ManifestWork
 
 // ClientHolderBuilder builds the ClientHolder with different configuration.
 type ClientHolderBuilder struct {
-	config       any
+	config       options.TransportConfig
 	watcherStore store.WorkClientWatcherStore
 	codecs       []generic.Codec[*workv1.ManifestWork]
 	sourceID     string
@@ -56,9 +58,8 @@ type ClientHolderBuilder struct {
 //   - MQTTOptions (*mqtt.MQTTOptions): builds a manifestwork client based on cloudevents with MQTT
 //   - GRPCOptions (*grpc.GRPCOptions): builds a manifestwork client based on cloudevents with GRPC
 //   - KafkaOptions (*kafka.KafkaOptions): builds a manifestwork client based on cloudevents with Kafka
-//
-// TODO using a specified config instead of any
-func NewClientHolderBuilder(config any) *ClientHolderBuilder {
+//   - Any other options.TransportConfig: builds a manifestwork client based on cloudevents with a custom transport
+func NewClientHolderBuilder(config options.TransportConfig) *ClientHolderBuilder {
 	return &ClientHolderBuilder{
 		config: config,
 		resync: true,
@@ -140,41 +141,18 @@ func (b *ClientHolderBuilder) NewSourceClientHolder(ctx context.Context) (*Clien
 	manifestWorkClient := sourceclient.NewManifestWorkSourceClient(b.sourceID, cloudEventsClient, b.watcherStore)
 	workClient := &internal.WorkV1ClientWrapper{ManifestWorkClient: manifestWorkClient}
 	workClientSet := &internal.WorkClientSetWrapper{WorkV1ClientWrapper: workClient}
-
-	// start a go routine to receive client reconnect signal
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-cloudEventsClient.ReconnectedChan():
-				if !b.resync {
-					klog.V(4).Infof("resync is disabled, do nothing")
-					continue
-				}
-
-				// when receiving a client reconnected signal, we resync all clusters for this source
-				if err := cloudEventsClient.Resync(ctx, types.ClusterAll); err != nil {
-					klog.Errorf("failed to send resync request, %v", err)
-				}
-			}
-		}
-	}()
-
-	if !b.resync {
-		return &ClientHolder{workClientSet: workClientSet}, nil
+	clientHolder := &ClientHolder{
+		workClientSet:  workClientSet,
+		clientStatus:   cloudEventsClient,
+		storeInitiated: b.watcherStore.HasInitiated,
 	}
 
-	// start a go routine to resync the works after this client's store is initiated
-	go func() {
-		if store.WaitForStoreInit(ctx, b.watcherStore.HasInitiated) {
-			if err := cloudEventsClient.Resync(ctx, types.ClusterAll); err != nil {
-				klog.Errorf("failed to send resync request, %v", err)
-			}
-		}
-	}()
+	if b.resync {
+		// resync all clusters for this source after the store is initiated and each time the client is reconnected
+		genericstore.StartResync(ctx, b.watcherStore.HasInitiated, cloudEventsClient, types.ClusterAll)
+	}
 
-	return &ClientHolder{workClientSet: workClientSet}, nil
+	return clientHolder, nil
 }
 
 // NewAgentClientHolder returns a ClientHolder for an agent
@@ -213,40 +191,17 @@ func (b *ClientHolderBuilder) NewAgentClientHolder(ctx context.Context) (*Client
 	manifestWorkClient := agentclient.NewManifestWorkAgentClient(cloudEventsClient, b.watcherStore, b.clusterName)
 	workClient := &internal.WorkV1ClientWrapper{ManifestWorkClient: manifestWorkClient}
 	workClientSet := &internal.WorkClientSetWrapper{WorkV1ClientWrapper: workClient}
-
-	// start a go routine to receive client reconnect signal
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-cloudEventsClient.ReconnectedChan():
-				if !b.resync {
-					klog.V(4).Infof("resync is disabled, do nothing")
-					continue
-				}
-
-				// when receiving a client reconnected signal, we resync all sources for this agent
-				// TODO after supporting multiple sources, we should only resync agent known sources
-				if err := cloudEventsClient.Resync(ctx, types.SourceAll); err != nil {
-					klog.Errorf("failed to send resync request, %v", err)
-				}
-			}
-		}
-	}()
-
-	if !b.resync {
-		return &ClientHolder{workClientSet: workClientSet}, nil
+	clientHolder := &ClientHolder{
+		workClientSet:  workClientSet,
+		clientStatus:   cloudEventsClient,
+		storeInitiated: b.watcherStore.HasInitiated,
 	}
 
-	// start a go routine to resync the works after this client's store is initiated
-	go func() {
-		if store.WaitForStoreInit(ctx, b.watcherStore.HasInitiated) {
-			if err := cloudEventsClient.Resync(ctx, types.SourceAll); err != nil {
-				klog.Errorf("failed to send resync request, %v", err)
-			}
-		}
-	}()
+	if b.resync {
+		// resync the known sources for this agent after the store is initiated and each time the client is reconnected,
+		// the agent broadcasts the resync request to all sources only when it does not know any source yet
+		genericstore.StartResync(ctx, b.watcherStore.HasInitiated, cloudEventsClient, types.SourceAll)
+	}
 
-	return &ClientHolder{workClientSet: workClientSet}, nil
+	return clientHolder, nil
 }
@@ -1,6 +1,8 @@
 package common
 
 import (
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	workv1 "open-cluster-management.io/api/work/v1"
@@ -19,6 +21,14 @@ const (
 	// CloudEventsSequenceIDAnnotationKey is the key of the status update event sequence ID.
 	// The sequence id represents the order in which status update events occur on a single agent.
 	CloudEventsSequenceIDAnnotationKey = "cloudevents.open-cluster-management.io/sequenceid"
+
+	// CloudEventsTraceParentAnnotationKey is the key of the W3C traceparent of the last received event of the
+	// ManifestWork, it's used to link the status update of the ManifestWork to the trace of its spec.
+	CloudEventsTraceParentAnnotationKey = "cloudevents.open-cluster-management.io/traceparent"
+
+	// CloudEventsTraceStateAnnotationKey is the key of the W3C tracestate of the last received event of the
+	// ManifestWork.
+	CloudEventsTraceStateAnnotationKey = "cloudevents.open-cluster-management.io/tracestate"
 )
 
 // CloudEventsOriginalSourceLabelKey is the key of the cloudevents original source label.
@@ -33,5 +43,29 @@ const (
 	DeleteRequestAction = "delete_request"
 )
 
+// SetTraceContextAnnotations saves the trace context of a received event to the annotations of its ManifestWork.
+func SetTraceContextAnnotations(annotations map[string]string, evt cloudevents.Event) {
+	ext, ok := extensions.GetDistributedTracingExtension(evt)
+	if !ok {
+		return
+	}
+
+	annotations[CloudEventsTraceParentAnnotationKey] = ext.TraceParent
+	if len(ext.TraceState) != 0 {
+		annotations[CloudEventsTraceStateAnnotationKey] = ext.TraceState
+	}
+}
+
+// RemoveTraceContextAnnotations removes the trace context annotations from the annotations of a ManifestWork.
+func RemoveTraceContextAnnotations(annotations map[string]string) {
+	delete(annotations, CloudEventsTraceParentAnnotationKey)
+	delete(annotations, CloudEventsTraceStateAnnotationKey)
+}
+
+// TraceContextFromAnnotations returns the trace context that is saved in the annotations of a ManifestWork.
+func TraceContextFromAnnotations(annotations map[string]string) (traceParent, traceState string) {
+	return annotations[CloudEventsTraceParentAnnotationKey], annotations[CloudEventsTraceStateAnnotationKey]
+}
+
 var ManifestWorkGK = schema.GroupKind{Group: workv1.GroupName, Kind: "ManifestWork"}
 var ManifestWorkGR = schema.GroupResource{Group: workv1.GroupName, Resource: "manifestworks"}
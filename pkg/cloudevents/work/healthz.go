@@ -0,0 +1,44 @@
+package work
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+)
+
+// ClientHolderHealthCheckerName is the name of the health checker of a ClientHolder.
+const ClientHolderHealthCheckerName = "manifestwork-client"
+
+// clientHolderHealthChecker checks the store and the cloudevents client of a ClientHolder.
+type clientHolderHealthChecker struct {
+	holder  *ClientHolder
+	options generic.HealthCheckOptions
+}
+
+// HealthChecker returns a HealthChecker for the ClientHolder, the checker returns an error if the store of the
+// ClientHolder is not initiated or its cloudevents client is unhealthy.
+func (h *ClientHolder) HealthChecker(options generic.HealthCheckOptions) generic.HealthChecker {
+	return &clientHolderHealthChecker{holder: h, options: options}
+}
+
+func (c *clientHolderHealthChecker) Name() string {
+	return ClientHolderHealthCheckerName
+}
+
+func (c *clientHolderHealthChecker) Check(_ *http.Request) error {
+	if c.holder.clientStatus == nil {
+		return fmt.Errorf("the manifestwork client is not built with cloudevents")
+	}
+
+	if !c.holder.storeInitiated() {
+		return fmt.Errorf("the manifestwork store is not initiated")
+	}
+
+	if err := generic.CheckClientStatus(c.holder.clientStatus.Status(), c.options, time.Now()); err != nil {
+		return fmt.Errorf("the manifestwork client is unhealthy, %v", err)
+	}
+
+	return nil
+}
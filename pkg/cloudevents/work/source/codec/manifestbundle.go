@@ -123,6 +123,8 @@ func (c *ManifestBundleCodec) Decode(evt *cloudevents.Event) (*workv1.ManifestWo
 		metaObj.Annotations = map[string]string{}
 	}
 	metaObj.Annotations[common.CloudEventsSequenceIDAnnotationKey] = sequenceID
+	// the trace context of the status is not kept, so it is not persisted with the status of the work
+	common.RemoveTraceContextAnnotations(metaObj.Annotations)
 
 	work := &workv1.ManifestWork{
 		TypeMeta:   metav1.TypeMeta{},
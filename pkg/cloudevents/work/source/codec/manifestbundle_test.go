@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,6 +14,7 @@ import (
 
 	workv1 "open-cluster-management.io/api/work/v1"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/payload"
 )
 
@@ -60,11 +62,29 @@ func TestManifestBundleEncode(t *testing.T) {
 				Spec: workv1.ManifestWorkSpec{},
 			},
 		},
+		{
+			name: "encode a manifestwork with the trace context of its status",
+			eventType: types.CloudEventsType{
+				CloudEventsDataType: payload.ManifestBundleEventDataType,
+				SubResource:         types.SubResourceSpec,
+				Action:              "test",
+			},
+			work: &workv1.ManifestWork{
+				ObjectMeta: metav1.ObjectMeta{
+					UID:             "test",
+					ResourceVersion: "0",
+					Annotations: map[string]string{
+						common.CloudEventsTraceParentAnnotationKey: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+					},
+				},
+				Spec: workv1.ManifestWorkSpec{},
+			},
+		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			_, err := NewManifestBundleCodec().Encode("cluster1-work-agent", c.eventType, c.work)
+			evt, err := NewManifestBundleCodec().Encode("cluster1-work-agent", c.eventType, c.work)
 			if c.expectedErr {
 				if err == nil {
 					t.Errorf("expected an error, but failed")
@@ -75,6 +95,11 @@ func TestManifestBundleEncode(t *testing.T) {
 			if err != nil {
 				t.Errorf("unexpected error %v", err)
 			}
+
+			// the spec is not sent with the stale trace context of the status
+			if _, ok := evt.Extensions()[extensions.TraceParentExtension]; ok {
+				t.Errorf("unexpected traceparent extension %v", evt.Extensions())
+			}
 		})
 	}
 }
@@ -171,8 +196,11 @@ func TestManifestBundleDecode(t *testing.T) {
 					Name:            "test",
 					Namespace:       "cluster1",
 					Labels:          map[string]string{"test1": "test1"},
-					Annotations:     map[string]string{"test2": "test2"},
-					Finalizers:      []string{"test"},
+					Annotations: map[string]string{
+						"test2": "test2",
+						common.CloudEventsTraceParentAnnotationKey: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+					},
+					Finalizers: []string{"test"},
 				})
 				if err != nil {
 					t.Fatal(err)
@@ -180,6 +208,9 @@ func TestManifestBundleDecode(t *testing.T) {
 				evt := cloudevents.NewEvent()
 				evt.SetSource("source1")
 				evt.SetType("io.open-cluster-management.works.v1alpha1.manifestbundles.status.test")
+				// the trace context of the status is not kept in the work
+				evt.SetExtension(extensions.TraceParentExtension, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+				evt.SetExtension(extensions.TraceStateExtension, "test=test")
 				evt.SetExtension("resourceid", "test")
 				evt.SetExtension("resourceversion", "13")
 				evt.SetExtension("metadata", string(metaJson))
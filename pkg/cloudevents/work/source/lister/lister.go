@@ -20,6 +20,11 @@ func NewWatcherStoreLister(store store.WorkClientWatcherStore) *WatcherStoreList
 	}
 }
 
+// HasSynced returns true if the WorkClientWatcherStore has been initiated.
+func (l *WatcherStoreLister) HasSynced() bool {
+	return l.store.HasInitiated()
+}
+
 // List returns the ManifestWorks from the WorkClientWatcherCache with list options.
 func (l *WatcherStoreLister) List(options types.ListOptions) ([]*workv1.ManifestWork, error) {
 	list, err := l.store.List(options.ClusterName, metav1.ListOptions{})
@@ -6,59 +6,45 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
 	workv1 "open-cluster-management.io/api/work/v1"
+
+	genericstore "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/store"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
-// SourceInformerWatcherStore extends the baseStore.
+// SourceInformerWatcherStore is an InformerWatcherStore of the works.
 // It gets/lists the works from the given informer store and send
 // the work add/update/delete event to the watch channel directly.
 //
 // It is used for building ManifestWork source client.
 type SourceInformerWatcherStore struct {
-	baseSourceStore
-	watcher  *workWatcher
-	informer cache.SharedIndexInformer
+	*genericstore.InformerWatcherStore[*workv1.ManifestWork]
 }
 
 var _ WorkClientWatcherStore = &SourceInformerWatcherStore{}
 
 func NewSourceInformerWatcherStore(ctx context.Context) *SourceInformerWatcherStore {
-	s := &SourceInformerWatcherStore{
-		baseSourceStore: baseSourceStore{
-			baseStore:     baseStore{},
-			receivedWorks: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "informer-watcher-store"),
-		},
-		watcher: newWorkWatcher(),
-	}
-
 	// start a goroutine to process the received work events from the work queue with current store.
-	go newWorkProcessor(s.receivedWorks, s).run(ctx.Done())
-
-	return s
-}
+	processor := genericstore.NewReceivedProcessor(ctx, "informer-watcher-store", processWorkStatus)
 
-func (s *SourceInformerWatcherStore) Add(work *workv1.ManifestWork) error {
-	s.watcher.Receive(watch.Event{Type: watch.Added, Object: work})
-	return nil
+	return &SourceInformerWatcherStore{
+		InformerWatcherStore: genericstore.NewInformerWatcherStore(handleReceivedWorkStatus(processor)),
+	}
 }
 
-func (s *SourceInformerWatcherStore) Update(work *workv1.ManifestWork) error {
-	s.watcher.Receive(watch.Event{Type: watch.Modified, Object: work})
-	return nil
+func (s *SourceInformerWatcherStore) HandleReceivedWork(action types.ResourceAction, work *workv1.ManifestWork) error {
+	return s.HandleReceivedResource(action, work)
 }
 
-func (s *SourceInformerWatcherStore) Delete(work *workv1.ManifestWork) error {
-	s.watcher.Receive(watch.Event{Type: watch.Deleted, Object: work})
-	return nil
-}
+func (s *SourceInformerWatcherStore) List(namespace string, opts metav1.ListOptions) (*workv1.ManifestWorkList, error) {
+	works, err := s.InformerWatcherStore.List(namespace, opts)
+	if err != nil {
+		return nil, err
+	}
 
-func (s *SourceInformerWatcherStore) HasInitiated() bool {
-	return s.initiated && s.informer.HasSynced()
+	return workList(works), nil
 }
 
 func (s *SourceInformerWatcherStore) GetWatcher(namespace string, opts metav1.ListOptions) (watch.Interface, error) {
@@ -66,56 +52,46 @@ func (s *SourceInformerWatcherStore) GetWatcher(namespace string, opts metav1.Li
 		return nil, fmt.Errorf("unsupported to watch from the namespace %s", namespace)
 	}
 
-	return s.watcher, nil
-}
-
-func (s *SourceInformerWatcherStore) SetInformer(informer cache.SharedIndexInformer) {
-	s.informer = informer
-	s.store = informer.GetStore()
-	s.initiated = true
+	return s.InformerWatcherStore.GetWatcher(namespace, opts)
 }
 
-// AgentInformerWatcherStore extends the baseStore.
+// AgentInformerWatcherStore is an InformerWatcherStore of the works.
 // It gets/lists the works from the given informer store and send
 // the work add/update/delete event to the watch channel directly.
 //
 // It is used for building ManifestWork agent client.
 type AgentInformerWatcherStore struct {
-	baseStore
-	informer cache.SharedIndexInformer
-	watcher  *workWatcher
+	*genericstore.InformerWatcherStore[*workv1.ManifestWork]
 }
 
 var _ WorkClientWatcherStore = &AgentInformerWatcherStore{}
 
 func NewAgentInformerWatcherStore() *AgentInformerWatcherStore {
 	return &AgentInformerWatcherStore{
-		baseStore: baseStore{},
-		watcher:   newWorkWatcher(),
+		InformerWatcherStore: genericstore.NewInformerWatcherStore(handleReceivedWorkSpec),
 	}
 }
 
-func (s *AgentInformerWatcherStore) Add(work *workv1.ManifestWork) error {
-	s.watcher.Receive(watch.Event{Type: watch.Added, Object: work})
-	return nil
+func (s *AgentInformerWatcherStore) HandleReceivedWork(action types.ResourceAction, work *workv1.ManifestWork) error {
+	return s.HandleReceivedResource(action, work)
 }
 
-func (s *AgentInformerWatcherStore) Update(work *workv1.ManifestWork) error {
-	s.watcher.Receive(watch.Event{Type: watch.Modified, Object: work})
-	return nil
-}
+func (s *AgentInformerWatcherStore) List(namespace string, opts metav1.ListOptions) (*workv1.ManifestWorkList, error) {
+	works, err := s.InformerWatcherStore.List(namespace, opts)
+	if err != nil {
+		return nil, err
+	}
 
-func (s *AgentInformerWatcherStore) Delete(work *workv1.ManifestWork) error {
-	s.watcher.Receive(watch.Event{Type: watch.Deleted, Object: work})
-	return nil
+	return workList(works), nil
 }
 
-func (s *AgentInformerWatcherStore) HandleReceivedWork(action types.ResourceAction, work *workv1.ManifestWork) error {
+// handleReceivedWorkSpec adds/updates the work in the store with the received work spec.
+func handleReceivedWorkSpec(store workStore, action types.ResourceAction, work *workv1.ManifestWork) error {
 	switch action {
 	case types.Added:
-		return s.Add(work.DeepCopy())
+		return store.Add(work.DeepCopy())
 	case types.Modified:
-		lastWork, exists, err := s.Get(work.Namespace, work.Name)
+		lastWork, exists, err := store.Get(work.Namespace, work.Name)
 		if err != nil {
 			return err
 		}
@@ -136,10 +112,10 @@ func (s *AgentInformerWatcherStore) HandleReceivedWork(action types.ResourceActi
 		updatedWork.Finalizers = lastWork.Finalizers
 		updatedWork.Status = lastWork.Status
 
-		return s.Update(updatedWork)
+		return store.Update(updatedWork)
 	case types.Deleted:
 		// the manifestwork is deleting on the source, we just update its deletion timestamp.
-		lastWork, exists, err := s.Get(work.Namespace, work.Name)
+		lastWork, exists, err := store.Get(work.Namespace, work.Name)
 		if err != nil {
 			return err
 		}
@@ -149,22 +125,8 @@ func (s *AgentInformerWatcherStore) HandleReceivedWork(action types.ResourceActi
 
 		updatedWork := lastWork.DeepCopy()
 		updatedWork.DeletionTimestamp = work.DeletionTimestamp
-		return s.Update(updatedWork)
+		return store.Update(updatedWork)
 	default:
 		return fmt.Errorf("unsupported resource action %s", action)
 	}
 }
-
-func (s *AgentInformerWatcherStore) GetWatcher(namespace string, opts metav1.ListOptions) (watch.Interface, error) {
-	return s.watcher, nil
-}
-
-func (s *AgentInformerWatcherStore) HasInitiated() bool {
-	return s.initiated && s.informer.HasSynced()
-}
-
-func (s *AgentInformerWatcherStore) SetInformer(informer cache.SharedIndexInformer) {
-	s.informer = informer
-	s.store = informer.GetStore()
-	s.initiated = true
-}
@@ -2,21 +2,17 @@ package store
 
 import (
 	"context"
-	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/klog/v2"
 
 	workv1 "open-cluster-management.io/api/work/v1"
+	genericstore "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/store"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
-const syncedPollPeriod = 100 * time.Millisecond
-
 // StoreInitiated is a function that can be used to determine if a store has initiated.
-type StoreInitiated func() bool
+type StoreInitiated = genericstore.StoreInitiated
 
 // WorkClientWatcherStore provides a watcher with a work store.
 type WorkClientWatcherStore interface {
@@ -53,23 +49,5 @@ type WorkClientWatcherStore interface {
 }
 
 func WaitForStoreInit(ctx context.Context, cacheSyncs ...StoreInitiated) bool {
-	err := wait.PollUntilContextCancel(
-		ctx,
-		syncedPollPeriod,
-		true,
-		func(ctx context.Context) (bool, error) {
-			for _, syncFunc := range cacheSyncs {
-				if !syncFunc() {
-					return false, nil
-				}
-			}
-			return true, nil
-		},
-	)
-	if err != nil {
-		klog.Errorf("stop WaitForStoreInit, %v", err)
-		return false
-	}
-
-	return true
+	return genericstore.WaitForStoreInit(ctx, cacheSyncs...)
 }
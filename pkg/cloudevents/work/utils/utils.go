@@ -13,8 +13,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
-	"k8s.io/apimachinery/pkg/fields"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/tools/cache"
@@ -22,6 +20,7 @@ import (
 	workv1 "open-cluster-management.io/api/work/v1"
 
 	"open-cluster-management.io/sdk-go/pkg/apis/work/v1/validator"
+	genericstore "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/store"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
 )
 
@@ -70,52 +69,7 @@ func UID(sourceID, namespace, name string) string {
 
 // ListWorksWithOptions retrieves the manifestworks from store which matches the options.
 func ListWorksWithOptions(store cache.Store, namespace string, opts metav1.ListOptions) ([]*workv1.ManifestWork, error) {
-	var err error
-
-	labelSelector := labels.Everything()
-	fieldSelector := fields.Everything()
-
-	if len(opts.LabelSelector) != 0 {
-		labelSelector, err = labels.Parse(opts.LabelSelector)
-		if err != nil {
-			return nil, fmt.Errorf("invalid labels selector %q: %v", opts.LabelSelector, err)
-		}
-	}
-
-	if len(opts.FieldSelector) != 0 {
-		fieldSelector, err = fields.ParseSelector(opts.FieldSelector)
-		if err != nil {
-			return nil, fmt.Errorf("invalid fields selector %q: %v", opts.FieldSelector, err)
-		}
-	}
-
-	works := []*workv1.ManifestWork{}
-	// list with labels
-	if err := cache.ListAll(store, labelSelector, func(obj interface{}) {
-		work, ok := obj.(*workv1.ManifestWork)
-		if !ok {
-			return
-		}
-
-		if namespace != metav1.NamespaceAll && work.Namespace != namespace {
-			return
-		}
-
-		workFieldSet := fields.Set{
-			"metadata.name":      work.Name,
-			"metadata.namespace": work.Namespace,
-		}
-
-		if !fieldSelector.Matches(workFieldSet) {
-			return
-		}
-
-		works = append(works, work)
-	}); err != nil {
-		return nil, err
-	}
-
-	return works, nil
+	return genericstore.ListWithOptions[*workv1.ManifestWork](store, namespace, opts)
 }
 
 func Validate(work *workv1.ManifestWork) field.ErrorList {
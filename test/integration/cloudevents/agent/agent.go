@@ -11,13 +11,14 @@ import (
 	workv1 "open-cluster-management.io/api/work/v1"
 
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/store"
 )
 
 func StartWorkAgent(ctx context.Context,
 	clusterName string,
-	config any,
+	config options.TransportConfig,
 	codecs ...generic.Codec[*workv1.ManifestWork],
 ) (*work.ClientHolder, workv1informers.ManifestWorkInformer, error) {
 	watcherStore := store.NewAgentInformerWatcherStore()
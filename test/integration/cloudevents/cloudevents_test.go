@@ -58,7 +58,7 @@ func runCloudeventsClientPubSubTest(getSourceOptionsFn GetSourceOptionsFn) func(
 
 		var sourceOptions *options.CloudEventsSourceOptions
 		var driver string
-		var agentOptions any
+		var agentOptions options.TransportConfig
 
 		var sourceCloudEventsClient generic.CloudEventsClient[*store.Resource]
 
@@ -125,7 +125,7 @@ func runCloudeventsClientPubSubTest(getSourceOptionsFn GetSourceOptionsFn) func(
 
 func crudResource(
 	ctx context.Context,
-	config any,
+	config options.TransportConfig,
 	sourceStore *store.MemoryStore,
 	sourceCloudEventsClient generic.CloudEventsClient[*store.Resource],
 	clusterName, resourceName string,
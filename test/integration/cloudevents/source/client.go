@@ -45,7 +45,7 @@ func StartResourceSourceClient(
 func StartManifestWorkSourceClient(
 	ctx context.Context,
 	sourceID string,
-	config any,
+	config options.TransportConfig,
 ) (*work.ClientHolder, workv1informers.ManifestWorkInformer, error) {
 	watcherStore := workstore.NewSourceInformerWatcherStore(ctx)
 